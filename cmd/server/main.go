@@ -2,41 +2,266 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"log/slog"
+	stdhttp "net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"text/template"
+	"time"
 
 	"github.com/Paulooo0/modak-challenge/internal/adapters/db"
 	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
 	"github.com/Paulooo0/modak-challenge/internal/adapters/gateway"
 	"github.com/Paulooo0/modak-challenge/internal/adapters/http"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/health"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/throttle"
+	v1 "github.com/Paulooo0/modak-challenge/internal/adapters/http/v1"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/metrics"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/otel"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/scheduler"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/stream"
 	"github.com/Paulooo0/modak-challenge/internal/config"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/config/tracing"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 
 	_ "github.com/Paulooo0/modak-challenge/docs"
 )
 
 // @title Modak Challenge API
 // @version 1.0
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description API key or end-user JWT sent as "Bearer <token>"
 func main() {
 	cfg := config.Load()
 
-	pool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
-	if err != nil || cfg.DatabaseURL == "" {
-		log.Fatalf("failed to connect to db: %v", err)
+	logger, err := logging.New(os.Stdout, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+	slog.SetDefault(logger)
+	cfg.LogFallbacks(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracesExporter, cfg.ServiceName)
+	if err != nil {
+		fatal("failed to set up tracing", err)
+	}
+
+	if cfg.DatabaseURL == "" {
+		slog.Error("DB_URL is not set")
+		os.Exit(1)
+	}
+	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	if err != nil {
+		fatal("invalid DB_URL", err)
+	}
+	db.IsolateTenants(poolConfig)
+	db.StoreTimesInUTC(poolConfig)
+	db.TraceQueries(poolConfig)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		fatal("failed to connect to db", err)
+	}
+	if err := db.Ping(context.Background(), pool); err != nil {
+		fatal("failed to connect to db", err)
 	}
-	defer pool.Close()
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.NewPoolCollector(pool),
+	)
 
 	q := sqlc.New(pool)
 	repo := db.NewNotificationRepository(q)
-	gateway := gateway.NewFakeGateway()
+	rateLimits := db.NewRateLimitRuleRepository(q)
+	templates := db.NewTemplateRepository(q)
+	prefs := db.NewUserPreferencesRepository(q)
+	segments := db.NewSegmentRepository(q)
+	// Connected users get notifications over WebSocket; everyone else
+	// through the console gateway.
+	ws := gateway.NewWebSocketGateway(cfg.WebSocketBuffer, cfg.WebSocketPing)
+	gw := gateway.NewFallbackGateway(
+		gateway.NewTracedGateway("websocket", ws),
+		gateway.NewTracedGateway("console", gateway.NewFakeGateway()),
+	)
+	hub := stream.NewHub(cfg.StreamBuffer)
+
+	defaultLocale, ok := entity.NormalizeLocale(cfg.DefaultLocale)
+	if !ok {
+		fatal("invalid default locale", fmt.Errorf("unknown locale %q", cfg.DefaultLocale))
+	}
 
-	uc := usecase.NewNotificationUseCase(repo, gateway, entity.DefaultRateLimits)
+	digestTemplate := cfg.DigestTemplate
+	if digestTemplate == "" {
+		digestTemplate = entity.DefaultDigestTemplate
+	}
+	digest, err := template.New("digest").Parse(digestTemplate)
+	if err != nil {
+		fatal("invalid digest template", err)
+	}
+	digestTypes := make([]entity.NotificationType, 0, len(cfg.DigestTypes))
+	for _, t := range cfg.DigestTypes {
+		digestTypes = append(digestTypes, entity.NotificationType(t))
+	}
 
-	r := http.NewRouter(uc)
+	uc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits,
+		usecase.WithTenantRateLimits(rateLimits),
+		usecase.WithTTLs(entity.DefaultTTLs),
+		usecase.WithDedupeWindows(entity.DefaultDedupeWindows),
+		usecase.WithDigests(digest, digestTypes...),
+		usecase.WithTemplates(templates),
+		usecase.WithLocales(prefs, defaultLocale),
+		usecase.WithStream(hub),
+		usecase.WithIdempotencyRetention(cfg.IdempotencyRetention),
+		usecase.WithDispatchLease(cfg.SchedulerLease),
+		usecase.WithMetrics(metrics.NewPrometheus(reg)),
+		usecase.WithTracer(otel.NewTracer()),
+	)
 
-	log.Println("Server running on :" + cfg.Port)
-	if err := r.Run(":" + cfg.Port); err != nil {
-		log.Fatal(err)
+	broadcasts := usecase.NewBroadcastUseCase(db.NewBroadcastRepository(q), segments, uc,
+		cfg.BroadcastPageSize, cfg.BroadcastLease)
+	campaigns := usecase.NewCampaignUseCase(db.NewCampaignRepository(q), segments, templates, uc,
+		cfg.CampaignBatchSize, cfg.CampaignLease)
+
+	// Workers run until drained on shutdown; cancelling workCtx aborts
+	// whatever they are still doing at the shutdown deadline.
+	workCtx, abortWork := context.WithCancel(context.Background())
+	defer abortWork()
+	workers := []namedWorker{
+		{"scheduler", scheduler.NewScheduler(uc, cfg.SchedulerInterval, cfg.SchedulerBatchSize)},
+		{"broadcasts", scheduler.NewBatchWorker(broadcasts, cfg.SchedulerInterval)},
+		{"campaigns", scheduler.NewBatchWorker(campaigns, cfg.SchedulerInterval)},
+	}
+	for _, w := range workers {
+		go w.Run(logging.With(workCtx, "worker", w.name))
 	}
+
+	apiKeys := usecase.NewAPIKeyUseCase(db.NewAPIKeyRepository(q))
+	if cfg.BootstrapAPIKey != "" {
+		if err := apiKeys.Bootstrap(context.Background(), cfg.BootstrapAPIKey); err != nil {
+			fatal("failed to bootstrap api key", err)
+		}
+	}
+
+	// End-user tokens are only accepted when a key set to verify them
+	// against is configured.
+	var tokens auth.TokenVerifier
+	switch {
+	case cfg.JWKSFile != "":
+		keys, err := auth.NewJWKSFile(cfg.JWKSFile)
+		if err != nil {
+			fatal("failed to load jwks", err)
+		}
+		tokens = auth.NewJWTVerifier(keys, cfg.JWTIssuer, cfg.JWTAudience)
+	case cfg.JWKSURL != "":
+		tokens = auth.NewJWTVerifier(auth.NewJWKSURL(cfg.JWKSURL, cfg.JWKSRefresh), cfg.JWTIssuer, cfg.JWTAudience)
+	}
+
+	var clientRates v1.ClientRates
+	for _, rate := range []struct {
+		name  string
+		value string
+		dst   *throttle.Rate
+	}{
+		{"CLIENT_RATE_LIMIT_IP", cfg.ClientRateIP, &clientRates.IP},
+		{"CLIENT_RATE_LIMIT_SEND", cfg.ClientRateSend, &clientRates.Send},
+		{"CLIENT_RATE_LIMIT_READ", cfg.ClientRateRead, &clientRates.Read},
+		{"CLIENT_RATE_LIMIT_ADMIN", cfg.ClientRateAdmin, &clientRates.Admin},
+	} {
+		if *rate.dst, err = throttle.ParseRate(rate.value); err != nil {
+			fatal("invalid "+rate.name, err)
+		}
+	}
+
+	probes := health.New(cfg.HealthCheckTimeout,
+		health.Check{Name: "database", Run: func(ctx context.Context) error { return db.Ping(ctx, pool) }},
+		health.Check{Name: "migrations", Run: db.NewSchemaCheck(q).Check},
+		health.Check{Name: "gateway.websocket", Run: ws.Check},
+	)
+
+	r, err := http.NewRouter(v1.Dependencies{
+		Notifications:   uc,
+		Templates:       usecase.NewTemplateUseCase(templates),
+		Preferences:     usecase.NewUserPreferencesUseCase(prefs),
+		Attributes:      usecase.NewUserAttributesUseCase(db.NewUserAttributesRepository(q)),
+		Segments:        usecase.NewSegmentUseCase(segments),
+		Broadcasts:      broadcasts,
+		Campaigns:       campaigns,
+		APIKeys:         apiKeys,
+		RateLimits:      usecase.NewRateLimitRuleUseCase(rateLimits, entity.DefaultRateLimits),
+		Tokens:          tokens,
+		ClientRates:     clientRates,
+		StreamHeartbeat: cfg.StreamHeartbeat,
+		Sessions:        ws,
+	}, reg, probes, cfg.TrustedProxies)
+	if err != nil {
+		fatal("invalid TRUSTED_PROXIES", err)
+	}
+
+	srv := &stdhttp.Server{Addr: ":" + cfg.Port, Handler: r}
+	// Streams and WebSocket sessions last as long as their clients stay,
+	// so they are ended rather than waited for.
+	srv.RegisterOnShutdown(hub.Close)
+	srv.RegisterOnShutdown(ws.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	served := make(chan error, 1)
+	go func() { served <- srv.ListenAndServe() }()
+	slog.Info("server running", "port", cfg.Port)
+
+	exitCode := 0
+	select {
+	case err := <-served:
+		if !errors.Is(err, stdhttp.ErrServerClosed) {
+			slog.Error("server stopped", "err", err)
+			exitCode = 1
+		}
+	case <-ctx.Done():
+	}
+	// A second signal kills the process without waiting for the drain.
+	stop()
+
+	// Keep serving while failing readiness, so load balancers stop sending
+	// traffic before the listener closes.
+	probes.Drain()
+	if exitCode == 0 && cfg.ShutdownDelay > 0 {
+		slog.Info("draining", "delay", cfg.ShutdownDelay)
+		time.Sleep(cfg.ShutdownDelay)
+	}
+
+	slog.Info("shutting down", "timeout", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	drain(shutdownCtx, srv, workers, abortWork)
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		slog.Warn("flushing traces failed", "err", err)
+	}
+	// Only close the pool once nothing is left to use it.
+	pool.Close()
+	slog.Info("server stopped")
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// fatal logs msg with err and exits.
+func fatal(msg string, err error) {
+	slog.Error(msg, "err", err)
+	os.Exit(1)
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// worker is a background loop, such as the scheduler, that can be drained.
+type worker interface {
+	Run(ctx context.Context)
+	Shutdown(ctx context.Context) error
+}
+
+type namedWorker struct {
+	name string
+	worker
+}
+
+// drain stops srv and the workers from taking new work and waits until
+// what they have in flight finishes or ctx is done. Whatever is still
+// running at that point is aborted: srv's connections are closed and
+// abort cancels the context the workers run with.
+func drain(ctx context.Context, srv *http.Server, workers []namedWorker, abort context.CancelFunc) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := srv.Shutdown(ctx); err != nil {
+			slog.Warn("aborting in-flight requests", "err", err)
+			srv.Close()
+		}
+	}()
+	for _, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log := slog.With("worker", w.name)
+			if err := w.Shutdown(ctx); err != nil {
+				log.Warn("aborting work in progress", "err", err)
+				abort()
+				w.Shutdown(context.Background())
+			}
+			log.Info("worker stopped")
+		}()
+	}
+	wg.Wait()
+}
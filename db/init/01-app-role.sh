@@ -0,0 +1,15 @@
+#!/bin/sh
+# Creates the role the server logs in as when the database is first
+# initialized. It is not a superuser and inherits no privileges: each
+# connection switches to modak_api, bound by row-level security, or to
+# modak_worker, which bypasses it. Migration 000019 grants them access to
+# the tables.
+set -e
+
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+	-v app_user="$DB_APP_USER" -v app_password="$DB_APP_PASSWORD" <<'EOSQL'
+CREATE ROLE modak_api NOLOGIN;
+CREATE ROLE modak_worker NOLOGIN BYPASSRLS;
+CREATE ROLE :"app_user" LOGIN NOINHERIT PASSWORD :'app_password';
+GRANT modak_api, modak_worker TO :"app_user";
+EOSQL
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_notifications_scheduled_send_at;
+DROP INDEX IF EXISTS idx_notifications_user_type_sent;
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_pkey;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS sent_at,
+DROP COLUMN IF EXISTS send_at,
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,16 @@
+ALTER TABLE notifications
+ADD COLUMN status text NOT NULL DEFAULT 'sent',
+ADD COLUMN send_at timestamp,
+ADD COLUMN sent_at timestamp;
+
+UPDATE notifications SET sent_at = created_at;
+
+ALTER TABLE notifications ADD CONSTRAINT notifications_pkey PRIMARY KEY (id);
+
+CREATE INDEX idx_notifications_user_type_sent
+		ON notifications(user_id, type, sent_at)
+		WHERE status = 'sent';
+
+CREATE INDEX idx_notifications_scheduled_send_at
+		ON notifications(send_at)
+		WHERE status = 'scheduled';
@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS status_reason,
+DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+ADD COLUMN expires_at timestamp,
+ADD COLUMN status_reason text NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_idempotency_key;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS request_hash,
+DROP COLUMN IF EXISTS idempotency_key;
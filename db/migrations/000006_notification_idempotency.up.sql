@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+ADD COLUMN idempotency_key text,
+ADD COLUMN request_hash text NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_notifications_idempotency_key
+		ON notifications(idempotency_key)
+		WHERE idempotency_key IS NOT NULL;
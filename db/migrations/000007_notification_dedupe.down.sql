@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notifications_dedupe;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS content_hash;
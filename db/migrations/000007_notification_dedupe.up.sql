@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+ADD COLUMN content_hash text NOT NULL DEFAULT '';
+
+UPDATE notifications SET content_hash = encode(sha256(message::bytea), 'hex');
+
+CREATE INDEX idx_notifications_dedupe
+		ON notifications(user_id, type, content_hash, created_at);
@@ -0,0 +1,3 @@
+CREATE INDEX idx_notifications_held
+		ON notifications(user_id, type, created_at)
+		WHERE status = 'held';
@@ -0,0 +1,13 @@
+CREATE TABLE templates (
+id uuid NOT NULL,
+version integer NOT NULL,
+name text NOT NULL,
+body text NOT NULL,
+variables jsonb NOT NULL DEFAULT '[]',
+created_at timestamp NOT NULL DEFAULT NOW(),
+PRIMARY KEY (id, version)
+);
+
+ALTER TABLE notifications
+ADD COLUMN template_id uuid,
+ADD COLUMN template_version integer NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS user_preferences;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS locale;
+
+ALTER TABLE templates
+DROP COLUMN IF EXISTS variants,
+DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,12 @@
+ALTER TABLE templates
+ADD COLUMN locale text NOT NULL DEFAULT 'en',
+ADD COLUMN variants jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE notifications
+ADD COLUMN locale text;
+
+CREATE TABLE user_preferences (
+user_id uuid PRIMARY KEY,
+locale text NOT NULL,
+updated_at timestamp NOT NULL DEFAULT NOW()
+);
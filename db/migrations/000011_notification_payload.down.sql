@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS metadata,
+DROP COLUMN IF EXISTS action_url,
+DROP COLUMN IF EXISTS title;
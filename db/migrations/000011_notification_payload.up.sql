@@ -0,0 +1,4 @@
+ALTER TABLE notifications
+ADD COLUMN title text NOT NULL DEFAULT '',
+ADD COLUMN action_url text NOT NULL DEFAULT '',
+ADD COLUMN metadata jsonb NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+CREATE INDEX idx_notifications_user_created
+		ON notifications(user_id, created_at DESC, id DESC);
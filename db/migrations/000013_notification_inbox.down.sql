@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notifications_unread;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS archived_at,
+DROP COLUMN IF EXISTS read_at;
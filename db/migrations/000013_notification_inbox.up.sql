@@ -0,0 +1,7 @@
+ALTER TABLE notifications
+ADD COLUMN read_at timestamp,
+ADD COLUMN archived_at timestamp;
+
+CREATE INDEX idx_notifications_unread
+		ON notifications(user_id)
+		WHERE status = 'sent' AND read_at IS NULL AND archived_at IS NULL;
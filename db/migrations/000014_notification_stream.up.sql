@@ -0,0 +1,3 @@
+CREATE INDEX idx_notifications_user_sent
+		ON notifications(user_id, sent_at, id)
+		WHERE status = 'sent';
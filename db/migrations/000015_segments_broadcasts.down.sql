@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS broadcasts;
+DROP TABLE IF EXISTS segment_members;
+DROP TABLE IF EXISTS segments;
+DROP TABLE IF EXISTS user_attributes;
@@ -0,0 +1,52 @@
+CREATE TABLE user_attributes (
+user_id uuid PRIMARY KEY,
+attributes jsonb NOT NULL DEFAULT '{}',
+tags text[] NOT NULL DEFAULT '{}',
+updated_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_attributes_attributes
+		ON user_attributes USING gin (attributes);
+CREATE INDEX idx_user_attributes_tags
+		ON user_attributes USING gin (tags);
+
+CREATE TABLE segments (
+id uuid PRIMARY KEY,
+name text NOT NULL,
+attributes jsonb NOT NULL DEFAULT '{}',
+tags text[] NOT NULL DEFAULT '{}',
+static boolean NOT NULL DEFAULT false,
+created_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE segment_members (
+segment_id uuid NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+user_id uuid NOT NULL,
+PRIMARY KEY (segment_id, user_id)
+);
+
+CREATE TABLE broadcasts (
+id uuid PRIMARY KEY,
+segment_id uuid NOT NULL REFERENCES segments(id),
+type text NOT NULL,
+title text NOT NULL DEFAULT '',
+message text NOT NULL,
+action_url text NOT NULL DEFAULT '',
+metadata jsonb NOT NULL DEFAULT '{}',
+status text NOT NULL DEFAULT 'pending',
+last_user_id uuid,
+total integer NOT NULL DEFAULT 0,
+processed integer NOT NULL DEFAULT 0,
+sent integer NOT NULL DEFAULT 0,
+rate_limited integer NOT NULL DEFAULT 0,
+skipped integer NOT NULL DEFAULT 0,
+failed integer NOT NULL DEFAULT 0,
+leased_until timestamp,
+created_at timestamp NOT NULL DEFAULT NOW(),
+updated_at timestamp NOT NULL DEFAULT NOW(),
+completed_at timestamp
+);
+
+CREATE INDEX idx_broadcasts_active
+		ON broadcasts(updated_at)
+		WHERE status IN ('pending', 'running');
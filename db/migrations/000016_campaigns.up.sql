@@ -0,0 +1,28 @@
+CREATE TABLE campaigns (
+id uuid PRIMARY KEY,
+name text NOT NULL,
+segment_id uuid NOT NULL REFERENCES segments(id),
+template_id uuid NOT NULL,
+template_version integer NOT NULL,
+data jsonb NOT NULL DEFAULT '{}',
+start_at timestamp NOT NULL,
+rate_per_minute integer NOT NULL,
+status text NOT NULL DEFAULT 'scheduled',
+last_user_id uuid,
+total integer NOT NULL DEFAULT 0,
+processed integer NOT NULL DEFAULT 0,
+sent integer NOT NULL DEFAULT 0,
+rate_limited integer NOT NULL DEFAULT 0,
+suppressed integer NOT NULL DEFAULT 0,
+failed integer NOT NULL DEFAULT 0,
+next_run_at timestamp NOT NULL,
+leased_until timestamp,
+created_at timestamp NOT NULL DEFAULT NOW(),
+updated_at timestamp NOT NULL DEFAULT NOW(),
+completed_at timestamp,
+FOREIGN KEY (template_id, template_version) REFERENCES templates(id, version)
+);
+
+CREATE INDEX idx_campaigns_due
+		ON campaigns(next_run_at)
+		WHERE status IN ('scheduled', 'running');
@@ -0,0 +1,13 @@
+CREATE TABLE api_keys (
+id uuid PRIMARY KEY,
+name text NOT NULL,
+prefix text NOT NULL,
+key_hash text NOT NULL,
+scopes text[] NOT NULL DEFAULT '{}',
+created_at timestamp NOT NULL DEFAULT NOW(),
+last_used_at timestamp,
+revoked_at timestamp
+);
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash
+		ON api_keys(key_hash);
@@ -0,0 +1,33 @@
+DROP TABLE IF EXISTS rate_limit_rules;
+
+DROP POLICY IF EXISTS tenant_isolation ON notifications;
+DROP POLICY IF EXISTS tenant_isolation ON templates;
+DROP POLICY IF EXISTS tenant_isolation ON api_keys;
+DROP POLICY IF EXISTS tenant_isolation ON broadcasts;
+DROP POLICY IF EXISTS tenant_isolation ON campaigns;
+
+ALTER TABLE notifications NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE templates NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE broadcasts NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE campaigns NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_notifications_idempotency_key;
+CREATE UNIQUE INDEX idx_notifications_idempotency_key
+		ON notifications(idempotency_key)
+		WHERE idempotency_key IS NOT NULL;
+
+ALTER TABLE campaigns
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE broadcasts
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE api_keys
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE templates
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE notifications
+DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,71 @@
+ALTER TABLE notifications
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE templates
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE api_keys
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE broadcasts
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE campaigns
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+-- Existing rows belong to the default tenant; new ones must name theirs.
+ALTER TABLE notifications ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE templates ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE broadcasts ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE campaigns ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- Idempotency keys are chosen by clients, so each tenant has its own.
+DROP INDEX IF EXISTS idx_notifications_idempotency_key;
+CREATE UNIQUE INDEX idx_notifications_idempotency_key
+		ON notifications(tenant_id, idempotency_key)
+		WHERE idempotency_key IS NOT NULL;
+
+CREATE TABLE rate_limit_rules (
+tenant_id text NOT NULL,
+type text NOT NULL,
+max_count integer NOT NULL,
+interval_seconds integer NOT NULL,
+updated_at timestamp NOT NULL DEFAULT NOW(),
+PRIMARY KEY (tenant_id, type)
+);
+
+-- Row-level security backs up the tenant filters of the queries. A
+-- connection with app.tenant_id set only sees and writes rows of that
+-- tenant; one without it, as used by background workers, sees every
+-- tenant. FORCE applies the policies to the table owner as well, though
+-- superusers still bypass them.
+ALTER TABLE notifications ENABLE ROW LEVEL SECURITY;
+ALTER TABLE notifications FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON notifications
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER TABLE templates ENABLE ROW LEVEL SECURITY;
+ALTER TABLE templates FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON templates
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON api_keys
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER TABLE broadcasts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE broadcasts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON broadcasts
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER TABLE campaigns ENABLE ROW LEVEL SECURITY;
+ALTER TABLE campaigns FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON campaigns
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER TABLE rate_limit_rules ENABLE ROW LEVEL SECURITY;
+ALTER TABLE rate_limit_rules FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON rate_limit_rules
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
@@ -0,0 +1,30 @@
+-- The roles are left in place: other databases of the cluster may use
+-- them.
+DROP POLICY tenant_isolation ON notifications;
+CREATE POLICY tenant_isolation ON notifications
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+DROP POLICY tenant_isolation ON templates;
+CREATE POLICY tenant_isolation ON templates
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+DROP POLICY tenant_isolation ON api_keys;
+CREATE POLICY tenant_isolation ON api_keys
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+DROP POLICY tenant_isolation ON broadcasts;
+CREATE POLICY tenant_isolation ON broadcasts
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+DROP POLICY tenant_isolation ON campaigns;
+CREATE POLICY tenant_isolation ON campaigns
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+DROP POLICY tenant_isolation ON rate_limit_rules;
+CREATE POLICY tenant_isolation ON rate_limit_rules
+		USING (COALESCE(current_setting('app.tenant_id', true), '') IN ('', tenant_id));
+
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+		REVOKE SELECT, INSERT, UPDATE, DELETE ON TABLES FROM modak_api, modak_worker;
+REVOKE ALL ON ALL TABLES IN SCHEMA public FROM modak_api, modak_worker;
+REVOKE USAGE ON SCHEMA public FROM modak_api, modak_worker;
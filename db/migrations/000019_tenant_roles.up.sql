@@ -0,0 +1,48 @@
+-- The API acts as modak_api, which the row-level security policies apply
+-- to. The background workers claim work across tenants as modak_worker,
+-- which bypasses them. The application logs in as a role that is a member
+-- of both and inherits neither, see db/init/01-app-role.sh.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'modak_api') THEN
+        CREATE ROLE modak_api NOLOGIN;
+    END IF;
+    IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'modak_worker') THEN
+        CREATE ROLE modak_worker NOLOGIN BYPASSRLS;
+    END IF;
+END
+$$;
+
+GRANT USAGE ON SCHEMA public TO modak_api, modak_worker;
+GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO modak_api, modak_worker;
+ALTER DEFAULT PRIVILEGES IN SCHEMA public
+		GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO modak_api, modak_worker;
+-- The readiness probe reads the migration version; only migrations write it.
+REVOKE INSERT, UPDATE, DELETE ON schema_migrations FROM modak_api, modak_worker;
+
+-- Fail closed: a row is only visible to a connection whose app.tenant_id
+-- is its tenant. Without app.tenant_id set the policies raise an error
+-- instead of showing every tenant.
+DROP POLICY tenant_isolation ON notifications;
+CREATE POLICY tenant_isolation ON notifications
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY tenant_isolation ON templates;
+CREATE POLICY tenant_isolation ON templates
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY tenant_isolation ON api_keys;
+CREATE POLICY tenant_isolation ON api_keys
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY tenant_isolation ON broadcasts;
+CREATE POLICY tenant_isolation ON broadcasts
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY tenant_isolation ON campaigns;
+CREATE POLICY tenant_isolation ON campaigns
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+DROP POLICY tenant_isolation ON rate_limit_rules;
+CREATE POLICY tenant_isolation ON rate_limit_rules
+		USING (tenant_id = current_setting('app.tenant_id'));
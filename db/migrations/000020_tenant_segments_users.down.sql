@@ -0,0 +1,32 @@
+DROP POLICY IF EXISTS tenant_isolation ON user_preferences;
+DROP POLICY IF EXISTS tenant_isolation ON user_attributes;
+DROP POLICY IF EXISTS tenant_isolation ON segment_members;
+DROP POLICY IF EXISTS tenant_isolation ON segments;
+
+ALTER TABLE user_preferences NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE user_attributes NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE segment_members NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+ALTER TABLE segments NO FORCE ROW LEVEL SECURITY, DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_segments_tenant;
+
+-- Fails when a user id exists in several tenants.
+ALTER TABLE user_preferences
+DROP CONSTRAINT user_preferences_pkey,
+ADD PRIMARY KEY (user_id);
+
+ALTER TABLE user_attributes
+DROP CONSTRAINT user_attributes_pkey,
+ADD PRIMARY KEY (user_id);
+
+ALTER TABLE user_preferences
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE user_attributes
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE segment_members
+DROP COLUMN IF EXISTS tenant_id;
+
+ALTER TABLE segments
+DROP COLUMN IF EXISTS tenant_id;
@@ -0,0 +1,51 @@
+-- Segments and the user data they match on belong to a tenant as well, so
+-- that one tenant cannot broadcast to another's users. Existing rows
+-- belong to the default tenant.
+ALTER TABLE segments
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE segment_members
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE user_attributes
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE user_preferences
+ADD COLUMN tenant_id text NOT NULL DEFAULT 'default';
+
+ALTER TABLE segments ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE segment_members ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE user_attributes ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE user_preferences ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- The same user id may exist in several tenants.
+ALTER TABLE user_attributes
+DROP CONSTRAINT user_attributes_pkey,
+ADD PRIMARY KEY (tenant_id, user_id);
+
+ALTER TABLE user_preferences
+DROP CONSTRAINT user_preferences_pkey,
+ADD PRIMARY KEY (tenant_id, user_id);
+
+CREATE INDEX idx_segments_tenant
+		ON segments(tenant_id, id);
+
+ALTER TABLE segments ENABLE ROW LEVEL SECURITY;
+ALTER TABLE segments FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON segments
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+ALTER TABLE segment_members ENABLE ROW LEVEL SECURITY;
+ALTER TABLE segment_members FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON segment_members
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+ALTER TABLE user_attributes ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_attributes FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_attributes
+		USING (tenant_id = current_setting('app.tenant_id'));
+
+ALTER TABLE user_preferences ENABLE ROW LEVEL SECURITY;
+ALTER TABLE user_preferences FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON user_preferences
+		USING (tenant_id = current_setting('app.tenant_id'));
@@ -0,0 +1,5 @@
+UPDATE notifications SET status = 'scheduled' WHERE status = 'sending';
+
+DROP INDEX IF EXISTS idx_notifications_sending_leased_until;
+
+ALTER TABLE notifications DROP COLUMN IF EXISTS leased_until;
@@ -0,0 +1,7 @@
+-- Due scheduled notifications are leased as 'sending' while a worker
+-- delivers them, and come due again when the lease runs out.
+ALTER TABLE notifications ADD COLUMN leased_until timestamp;
+
+CREATE INDEX idx_notifications_sending_leased_until
+		ON notifications(leased_until)
+		WHERE status = 'sending';
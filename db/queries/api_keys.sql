@@ -0,0 +1,39 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, scopes)
+VALUES ($1, $2, $3, $4, $5, sqlc.arg(scopes)::text[])
+RETURNING *;
+
+-- name: GetActiveAPIKeyByHash :one
+-- Not scoped to a tenant: the key is what tells the tenant.
+SELECT *
+FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL;
+
+-- name: ListAPIKeys :many
+SELECT *
+FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at, id;
+
+-- name: RotateAPIKey :one
+UPDATE api_keys
+SET prefix = $3,
+    key_hash = $4
+WHERE tenant_id = $1
+  AND id = $2
+  AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE tenant_id = $1
+  AND id = $2
+  AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = sqlc.arg(last_used_at)::timestamp
+WHERE tenant_id = $1
+  AND id = $2;
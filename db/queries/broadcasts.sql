@@ -0,0 +1,43 @@
+-- name: CreateBroadcast :one
+INSERT INTO broadcasts (id, tenant_id, segment_id, type, title, message, action_url, metadata, total)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetBroadcast :one
+SELECT *
+FROM broadcasts
+WHERE tenant_id = $1
+  AND id = $2;
+
+-- name: ClaimBroadcast :one
+-- Workers serve every tenant, so the claim is not scoped to one.
+UPDATE broadcasts
+SET status = 'running',
+    leased_until = sqlc.arg(leased_until)::timestamp,
+    updated_at = sqlc.arg(now)::timestamp
+WHERE id = (
+  SELECT id
+  FROM broadcasts
+  WHERE status IN ('pending', 'running')
+    AND (leased_until IS NULL OR leased_until < sqlc.arg(now)::timestamp)
+  ORDER BY updated_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateBroadcastProgress :one
+UPDATE broadcasts
+SET status = $2,
+    last_user_id = $3,
+    processed = $4,
+    sent = $5,
+    rate_limited = $6,
+    skipped = $7,
+    failed = $8,
+    completed_at = $9,
+    leased_until = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND leased_until = sqlc.arg(leased_until)::timestamp
+RETURNING *;
@@ -0,0 +1,59 @@
+-- name: CreateCampaign :one
+INSERT INTO campaigns (
+  id, tenant_id, name, segment_id, template_id, template_version, data,
+  start_at, rate_per_minute, total, next_run_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $8)
+RETURNING *;
+
+-- name: GetCampaign :one
+SELECT *
+FROM campaigns
+WHERE tenant_id = $1
+  AND id = $2;
+
+-- name: ClaimCampaign :one
+-- Workers serve every tenant, so the claim is not scoped to one.
+UPDATE campaigns
+SET status = 'running',
+    leased_until = sqlc.arg(leased_until)::timestamp,
+    updated_at = sqlc.arg(now)::timestamp
+WHERE id = (
+  SELECT id
+  FROM campaigns
+  WHERE status IN ('scheduled', 'running')
+    AND next_run_at <= sqlc.arg(now)::timestamp
+    AND (leased_until IS NULL OR leased_until < sqlc.arg(now)::timestamp)
+  ORDER BY next_run_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateCampaignProgress :one
+-- A campaign paused or cancelled while a batch was in flight keeps that
+-- status; only a running campaign is moved to the status of the batch.
+UPDATE campaigns
+SET status = CASE WHEN status = 'running' THEN sqlc.arg(status)::text ELSE status END,
+    completed_at = CASE WHEN status = 'running' THEN sqlc.narg(completed_at)::timestamp ELSE completed_at END,
+    last_user_id = $2,
+    processed = $3,
+    sent = $4,
+    rate_limited = $5,
+    suppressed = $6,
+    failed = $7,
+    next_run_at = $8,
+    leased_until = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND leased_until = sqlc.arg(leased_until)::timestamp
+RETURNING *;
+
+-- name: UpdateCampaignStatus :one
+UPDATE campaigns
+SET status = sqlc.arg(status),
+    updated_at = NOW()
+WHERE tenant_id = $1
+  AND id = $2
+  AND status = ANY(sqlc.arg(from_statuses)::text[])
+RETURNING *;
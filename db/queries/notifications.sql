@@ -1,11 +1,177 @@
--- name: CreateNotification :one
-INSERT INTO notifications (user_id, type, message)
-VALUES ($1, $2, $3)
-RETURNING *;
-
--- name: CountNotificationsInTimeWindow :one
-SELECT COUNT(*) as total
-FROM notifications
-WHERE user_id = $1
-  AND type = $2
-  AND created_at >= $3;
\ No newline at end of file
+-- name: CreateNotification :one
+INSERT INTO notifications (id, tenant_id, user_id, type, message, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING *;
+
+-- name: CountNotificationsInTimeWindow :one
+SELECT COUNT(*) as total
+FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND type = sqlc.arg(type)
+  AND status = 'sent'
+  AND sent_at >= sqlc.arg(since)::timestamp;
+
+-- name: CountNotificationsInTimeWindowByUser :many
+SELECT user_id, COUNT(*) as total
+FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = ANY(sqlc.arg(user_ids)::uuid[])
+  AND type = sqlc.arg(type)
+  AND status = 'sent'
+  AND sent_at >= sqlc.arg(since)::timestamp
+GROUP BY user_id;
+
+-- name: CopyNotifications :copyfrom
+INSERT INTO notifications (id, tenant_id, user_id, type, title, message, action_url, metadata, status, status_reason, sent_at, expires_at, content_hash, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14);
+
+-- name: FindDuplicateNotification :one
+SELECT *
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND type = $3
+  AND content_hash = $4
+  AND status IN ('scheduled', 'sent')
+  AND created_at >= sqlc.arg(since)::timestamp
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetNotification :one
+SELECT *
+FROM notifications
+WHERE tenant_id = $1
+  AND id = $2;
+
+-- name: GetNotificationByIdempotencyKey :one
+SELECT *
+FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND idempotency_key = sqlc.arg(idempotency_key)::text;
+
+-- name: ListHeldNotificationGroups :many
+-- Groups are paged in key order so that those still over their rate limit
+-- do not keep coming back ahead of the rest.
+SELECT tenant_id, user_id, type
+FROM notifications
+WHERE status = 'held'
+  AND (tenant_id, user_id, type) > (sqlc.arg(after_tenant_id)::text, sqlc.arg(after_user_id)::uuid, sqlc.arg(after_type)::text)
+GROUP BY tenant_id, user_id, type
+ORDER BY tenant_id, user_id, type
+LIMIT sqlc.arg(max_rows);
+
+-- name: ClaimHeldNotifications :many
+UPDATE notifications
+SET status = 'digested',
+    status_reason = sqlc.arg(reason)
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND type = sqlc.arg(type)
+  AND status = 'held'
+RETURNING *;
+
+-- name: ReleaseIdempotencyKey :exec
+UPDATE notifications
+SET idempotency_key = NULL
+WHERE tenant_id = $1
+  AND id = $2;
+
+-- name: ClaimDueNotifications :many
+-- Workers serve every tenant, so the claim is not scoped to one. Rows a
+-- worker stopped sending before settling come due again once their lease
+-- has run out.
+UPDATE notifications
+SET status = 'sending',
+    leased_until = sqlc.arg(leased_until)::timestamp
+WHERE id IN (
+  SELECT id
+  FROM notifications
+  WHERE (status = 'scheduled' AND send_at <= sqlc.arg(now)::timestamp)
+     OR (status = 'sending' AND leased_until < sqlc.arg(now)::timestamp)
+  ORDER BY send_at
+  LIMIT sqlc.arg(max_rows)
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateNotificationStatus :one
+UPDATE notifications
+SET status = sqlc.arg(to_status),
+    status_reason = sqlc.arg(reason),
+    leased_until = NULL,
+    sent_at = CASE WHEN sqlc.arg(to_status)::text = 'sent' THEN sqlc.arg(changed_at)::timestamp ELSE sent_at END
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND id = sqlc.arg(id)
+  AND status = sqlc.arg(from_status)
+RETURNING *;
+
+-- name: ListUserNotifications :many
+SELECT *
+FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(types)::text[] IS NULL OR type = ANY(sqlc.narg(types)::text[]))
+  AND (sqlc.narg(statuses)::text[] IS NULL OR status = ANY(sqlc.narg(statuses)::text[]))
+  AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from)::timestamp)
+  AND (sqlc.narg(created_to)::timestamp IS NULL OR created_at < sqlc.narg(created_to)::timestamp)
+  AND (sqlc.narg(after_created_at)::timestamp IS NULL
+    OR (created_at, id) < (sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::uuid))
+  AND (sqlc.narg(read)::boolean IS NULL OR (read_at IS NOT NULL) = sqlc.narg(read)::boolean)
+  AND (sqlc.narg(archived)::boolean IS NULL OR (archived_at IS NOT NULL) = sqlc.narg(archived)::boolean)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(max_rows);
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*) as total
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND status = 'sent'
+  AND read_at IS NULL
+  AND archived_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE tenant_id = $1
+  AND id = $2
+  AND user_id = $3
+  AND status = 'sent'
+RETURNING *;
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND id = ANY(sqlc.arg(ids)::uuid[])
+  AND status = 'sent'
+  AND read_at IS NULL;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND status = 'sent'
+  AND read_at IS NULL;
+
+-- name: SetNotificationArchived :one
+UPDATE notifications
+SET archived_at = CASE WHEN sqlc.arg(archived)::boolean THEN COALESCE(archived_at, NOW()) END
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id)
+  AND status = 'sent'
+RETURNING *;
+
+-- name: ListSentNotificationsAfter :many
+SELECT *
+FROM notifications
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND status = 'sent'
+  AND (sent_at, id) > (sqlc.arg(after_sent_at)::timestamp, sqlc.arg(after_id)::uuid)
+ORDER BY sent_at, id
+LIMIT sqlc.arg(max_rows);
@@ -0,0 +1,25 @@
+-- name: GetRateLimitRule :one
+SELECT *
+FROM rate_limit_rules
+WHERE tenant_id = $1
+  AND type = $2;
+
+-- name: ListRateLimitRules :many
+SELECT *
+FROM rate_limit_rules
+WHERE tenant_id = $1
+ORDER BY type;
+
+-- name: UpsertRateLimitRule :one
+INSERT INTO rate_limit_rules (tenant_id, type, max_count, interval_seconds)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant_id, type) DO UPDATE
+SET max_count = EXCLUDED.max_count,
+    interval_seconds = EXCLUDED.interval_seconds,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteRateLimitRule :execrows
+DELETE FROM rate_limit_rules
+WHERE tenant_id = $1
+  AND type = $2;
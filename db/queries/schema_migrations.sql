@@ -0,0 +1,4 @@
+-- name: GetSchemaVersion :one
+SELECT version, dirty
+FROM schema_migrations
+LIMIT 1;
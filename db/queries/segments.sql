@@ -0,0 +1,50 @@
+-- name: CreateSegment :one
+-- Members of a static segment are stored in the same statement so that a
+-- segment never exists without them.
+WITH segment AS (
+  INSERT INTO segments (id, tenant_id, name, attributes, tags, static)
+  VALUES ($1, $2, $3, $4, sqlc.arg(tags)::text[], $5)
+  RETURNING *
+), members AS (
+  INSERT INTO segment_members (segment_id, tenant_id, user_id)
+  SELECT $1, $2, unnest(sqlc.arg(user_ids)::uuid[])
+)
+SELECT * FROM segment;
+
+-- name: GetSegment :one
+SELECT *
+FROM segments
+WHERE tenant_id = $1
+  AND id = $2;
+
+-- name: ListSegmentMembers :many
+SELECT user_id
+FROM segment_members
+WHERE tenant_id = $1
+  AND segment_id = $2
+  AND user_id > sqlc.arg(after)::uuid
+ORDER BY user_id
+LIMIT $3;
+
+-- name: CountSegmentMembers :one
+SELECT COUNT(*) as total
+FROM segment_members
+WHERE tenant_id = $1
+  AND segment_id = $2;
+
+-- name: ListUsersMatching :many
+SELECT user_id
+FROM user_attributes
+WHERE tenant_id = $1
+  AND attributes @> sqlc.arg(attributes)::jsonb
+  AND tags @> sqlc.arg(tags)::text[]
+  AND user_id > sqlc.arg(after)::uuid
+ORDER BY user_id
+LIMIT $2;
+
+-- name: CountUsersMatching :one
+SELECT COUNT(*) as total
+FROM user_attributes
+WHERE tenant_id = $1
+  AND attributes @> sqlc.arg(attributes)::jsonb
+  AND tags @> sqlc.arg(tags)::text[];
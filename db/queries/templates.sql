@@ -0,0 +1,25 @@
+-- name: CreateTemplate :one
+INSERT INTO templates (id, tenant_id, version, name, body, variables, locale, variants)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetLatestTemplate :one
+SELECT *
+FROM templates
+WHERE tenant_id = $1
+  AND id = $2
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetTemplateVersion :one
+SELECT *
+FROM templates
+WHERE tenant_id = $1
+  AND id = $2
+  AND version = $3;
+
+-- name: ListLatestTemplates :many
+SELECT DISTINCT ON (id) *
+FROM templates
+WHERE tenant_id = $1
+ORDER BY id, version DESC;
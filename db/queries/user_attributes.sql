@@ -0,0 +1,14 @@
+-- name: GetUserAttributes :one
+SELECT *
+FROM user_attributes
+WHERE tenant_id = $1
+  AND user_id = $2;
+
+-- name: UpsertUserAttributes :one
+INSERT INTO user_attributes (tenant_id, user_id, attributes, tags)
+VALUES ($1, $2, $3, sqlc.arg(tags)::text[])
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET attributes = EXCLUDED.attributes,
+    tags = EXCLUDED.tags,
+    updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,13 @@
+-- name: GetUserPreferences :one
+SELECT *
+FROM user_preferences
+WHERE tenant_id = $1
+  AND user_id = $2;
+
+-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (tenant_id, user_id, locale)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET locale = EXCLUDED.locale,
+    updated_at = NOW()
+RETURNING *;
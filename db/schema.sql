@@ -20,6 +20,87 @@ SET default_tablespace = '';
 
 SET default_table_access_method = heap;
 
+--
+-- Name: api_keys; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.api_keys (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    prefix text NOT NULL,
+    key_hash text NOT NULL,
+    scopes text[] DEFAULT '{}'::text[] NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    last_used_at timestamp without time zone,
+    revoked_at timestamp without time zone,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.api_keys FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: broadcasts; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.broadcasts (
+    id uuid NOT NULL,
+    segment_id uuid NOT NULL,
+    type text NOT NULL,
+    title text DEFAULT ''::text NOT NULL,
+    message text NOT NULL,
+    action_url text DEFAULT ''::text NOT NULL,
+    metadata jsonb DEFAULT '{}'::jsonb NOT NULL,
+    status text DEFAULT 'pending'::text NOT NULL,
+    last_user_id uuid,
+    total integer DEFAULT 0 NOT NULL,
+    processed integer DEFAULT 0 NOT NULL,
+    sent integer DEFAULT 0 NOT NULL,
+    rate_limited integer DEFAULT 0 NOT NULL,
+    skipped integer DEFAULT 0 NOT NULL,
+    failed integer DEFAULT 0 NOT NULL,
+    leased_until timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    completed_at timestamp without time zone,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.broadcasts FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: campaigns; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.campaigns (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    segment_id uuid NOT NULL,
+    template_id uuid NOT NULL,
+    template_version integer NOT NULL,
+    data jsonb DEFAULT '{}'::jsonb NOT NULL,
+    start_at timestamp without time zone NOT NULL,
+    rate_per_minute integer NOT NULL,
+    status text DEFAULT 'scheduled'::text NOT NULL,
+    last_user_id uuid,
+    total integer DEFAULT 0 NOT NULL,
+    processed integer DEFAULT 0 NOT NULL,
+    sent integer DEFAULT 0 NOT NULL,
+    rate_limited integer DEFAULT 0 NOT NULL,
+    suppressed integer DEFAULT 0 NOT NULL,
+    failed integer DEFAULT 0 NOT NULL,
+    next_run_at timestamp without time zone NOT NULL,
+    leased_until timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    completed_at timestamp without time zone,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.campaigns FORCE ROW LEVEL SECURITY;
+
+
 --
 -- Name: notifications; Type: TABLE; Schema: public; Owner: -
 --
@@ -29,9 +110,44 @@ CREATE TABLE public.notifications (
     user_id uuid NOT NULL,
     type text NOT NULL,
     message text NOT NULL,
-    created_at timestamp without time zone DEFAULT now() NOT NULL
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    status text DEFAULT 'sent'::text NOT NULL,
+    send_at timestamp without time zone,
+    sent_at timestamp without time zone,
+    expires_at timestamp without time zone,
+    status_reason text DEFAULT ''::text NOT NULL,
+    idempotency_key text,
+    request_hash text DEFAULT ''::text NOT NULL,
+    content_hash text DEFAULT ''::text NOT NULL,
+    template_id uuid,
+    template_version integer DEFAULT 0 NOT NULL,
+    locale text,
+    title text DEFAULT ''::text NOT NULL,
+    action_url text DEFAULT ''::text NOT NULL,
+    metadata jsonb DEFAULT '{}'::jsonb NOT NULL,
+    read_at timestamp without time zone,
+    archived_at timestamp without time zone,
+    tenant_id text NOT NULL,
+    leased_until timestamp without time zone
 );
 
+ALTER TABLE ONLY public.notifications FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: rate_limit_rules; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.rate_limit_rules (
+    tenant_id text NOT NULL,
+    type text NOT NULL,
+    max_count integer NOT NULL,
+    interval_seconds integer NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE ONLY public.rate_limit_rules FORCE ROW LEVEL SECURITY;
+
 
 --
 -- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
@@ -43,6 +159,124 @@ CREATE TABLE public.schema_migrations (
 );
 
 
+--
+-- Name: segment_members; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.segment_members (
+    segment_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.segment_members FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: segments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.segments (
+    id uuid NOT NULL,
+    name text NOT NULL,
+    attributes jsonb DEFAULT '{}'::jsonb NOT NULL,
+    tags text[] DEFAULT '{}'::text[] NOT NULL,
+    static boolean DEFAULT false NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.segments FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: templates; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.templates (
+    id uuid NOT NULL,
+    version integer NOT NULL,
+    name text NOT NULL,
+    body text NOT NULL,
+    variables jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    locale text DEFAULT 'en'::text NOT NULL,
+    variants jsonb DEFAULT '{}'::jsonb NOT NULL,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.templates FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: user_attributes; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_attributes (
+    user_id uuid NOT NULL,
+    attributes jsonb DEFAULT '{}'::jsonb NOT NULL,
+    tags text[] DEFAULT '{}'::text[] NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.user_attributes FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: user_preferences; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.user_preferences (
+    user_id uuid NOT NULL,
+    locale text NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    tenant_id text NOT NULL
+);
+
+ALTER TABLE ONLY public.user_preferences FORCE ROW LEVEL SECURITY;
+
+
+--
+-- Name: api_keys api_keys_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.api_keys
+    ADD CONSTRAINT api_keys_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: broadcasts broadcasts_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.broadcasts
+    ADD CONSTRAINT broadcasts_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: campaigns campaigns_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaigns
+    ADD CONSTRAINT campaigns_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notifications notifications_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notifications
+    ADD CONSTRAINT notifications_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: rate_limit_rules rate_limit_rules_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.rate_limit_rules
+    ADD CONSTRAINT rate_limit_rules_pkey PRIMARY KEY (tenant_id, type);
+
+
 --
 -- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
 --
@@ -51,6 +285,130 @@ ALTER TABLE ONLY public.schema_migrations
     ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
 
 
+--
+-- Name: segment_members segment_members_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.segment_members
+    ADD CONSTRAINT segment_members_pkey PRIMARY KEY (segment_id, user_id);
+
+
+--
+-- Name: segments segments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.segments
+    ADD CONSTRAINT segments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: templates templates_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.templates
+    ADD CONSTRAINT templates_pkey PRIMARY KEY (id, version);
+
+
+--
+-- Name: user_attributes user_attributes_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_attributes
+    ADD CONSTRAINT user_attributes_pkey PRIMARY KEY (tenant_id, user_id);
+
+
+--
+-- Name: user_preferences user_preferences_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.user_preferences
+    ADD CONSTRAINT user_preferences_pkey PRIMARY KEY (tenant_id, user_id);
+
+
+--
+-- Name: idx_api_keys_key_hash; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_api_keys_key_hash ON public.api_keys USING btree (key_hash);
+
+
+--
+-- Name: idx_broadcasts_active; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_broadcasts_active ON public.broadcasts USING btree (updated_at) WHERE (status = ANY (ARRAY['pending'::text, 'running'::text]));
+
+
+--
+-- Name: idx_campaigns_due; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_campaigns_due ON public.campaigns USING btree (next_run_at) WHERE (status = ANY (ARRAY['scheduled'::text, 'running'::text]));
+
+
+--
+-- Name: idx_notifications_dedupe; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_dedupe ON public.notifications USING btree (user_id, type, content_hash, created_at);
+
+
+--
+-- Name: idx_notifications_held; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_held ON public.notifications USING btree (user_id, type, created_at) WHERE (status = 'held'::text);
+
+
+--
+-- Name: idx_notifications_idempotency_key; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_notifications_idempotency_key ON public.notifications USING btree (tenant_id, idempotency_key) WHERE (idempotency_key IS NOT NULL);
+
+
+--
+-- Name: idx_notifications_scheduled_send_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_scheduled_send_at ON public.notifications USING btree (send_at) WHERE (status = 'scheduled'::text);
+
+
+--
+-- Name: idx_notifications_sending_leased_until; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_sending_leased_until ON public.notifications USING btree (leased_until) WHERE (status = 'sending'::text);
+
+
+--
+-- Name: idx_notifications_unread; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_unread ON public.notifications USING btree (user_id) WHERE ((status = 'sent'::text) AND (read_at IS NULL) AND (archived_at IS NULL));
+
+
+--
+-- Name: idx_notifications_user_created; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_user_created ON public.notifications USING btree (user_id, created_at DESC, id DESC);
+
+
+--
+-- Name: idx_notifications_user_sent; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_user_sent ON public.notifications USING btree (user_id, sent_at, id) WHERE (status = 'sent'::text);
+
+
+--
+-- Name: idx_notifications_user_type_sent; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notifications_user_type_sent ON public.notifications USING btree (user_id, type, sent_at) WHERE (status = 'sent'::text);
+
+
 --
 -- Name: idx_notifications_user_type_time; Type: INDEX; Schema: public; Owner: -
 --
@@ -58,6 +416,181 @@ ALTER TABLE ONLY public.schema_migrations
 CREATE INDEX idx_notifications_user_type_time ON public.notifications USING btree (user_id, type, created_at);
 
 
+--
+-- Name: idx_segments_tenant; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_segments_tenant ON public.segments USING btree (tenant_id, id);
+
+
+--
+-- Name: idx_user_attributes_attributes; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_attributes_attributes ON public.user_attributes USING gin (attributes);
+
+
+--
+-- Name: idx_user_attributes_tags; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_user_attributes_tags ON public.user_attributes USING gin (tags);
+
+
+--
+-- Name: broadcasts broadcasts_segment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.broadcasts
+    ADD CONSTRAINT broadcasts_segment_id_fkey FOREIGN KEY (segment_id) REFERENCES public.segments(id);
+
+
+--
+-- Name: campaigns campaigns_segment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaigns
+    ADD CONSTRAINT campaigns_segment_id_fkey FOREIGN KEY (segment_id) REFERENCES public.segments(id);
+
+
+--
+-- Name: campaigns campaigns_template_id_template_version_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.campaigns
+    ADD CONSTRAINT campaigns_template_id_template_version_fkey FOREIGN KEY (template_id, template_version) REFERENCES public.templates(id, version);
+
+
+--
+-- Name: segment_members segment_members_segment_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.segment_members
+    ADD CONSTRAINT segment_members_segment_id_fkey FOREIGN KEY (segment_id) REFERENCES public.segments(id) ON DELETE CASCADE;
+
+
+--
+-- Name: api_keys; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.api_keys ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: api_keys tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.api_keys USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: broadcasts; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.broadcasts ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: broadcasts tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.broadcasts USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: campaigns; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.campaigns ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: campaigns tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.campaigns USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: notifications; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.notifications ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: notifications tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.notifications USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: rate_limit_rules; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.rate_limit_rules ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: rate_limit_rules tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.rate_limit_rules USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: segment_members; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.segment_members ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: segment_members tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.segment_members USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: segments; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.segments ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: segments tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.segments USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: templates; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.templates ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: templates tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.templates USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+
+--
+-- Name: user_attributes; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.user_attributes ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: user_attributes tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.user_attributes USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+--
+-- Name: user_preferences; Type: ROW SECURITY; Schema: public; Owner: -
+--
+
+ALTER TABLE public.user_preferences ENABLE ROW LEVEL SECURITY;
+
+--
+-- Name: user_preferences tenant_isolation; Type: POLICY; Schema: public; Owner: -
+--
+
+CREATE POLICY tenant_isolation ON public.user_preferences USING ((tenant_id = current_setting('app.tenant_id'::text)));
+
+
 --
 -- PostgreSQL database dump complete
 --
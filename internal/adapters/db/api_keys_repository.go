@@ -0,0 +1,122 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// apiKeysQuerier is the subset of *sqlc.Queries used by APIKeyRepository.
+type apiKeysQuerier interface {
+	CreateAPIKey(ctx context.Context, arg sqlc.CreateAPIKeyParams) (sqlc.APIKey, error)
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (sqlc.APIKey, error)
+	ListAPIKeys(ctx context.Context, tenantID string) ([]sqlc.APIKey, error)
+	RotateAPIKey(ctx context.Context, arg sqlc.RotateAPIKeyParams) (sqlc.APIKey, error)
+	RevokeAPIKey(ctx context.Context, arg sqlc.RevokeAPIKeyParams) (int64, error)
+	TouchAPIKey(ctx context.Context, arg sqlc.TouchAPIKeyParams) error
+}
+
+// APIKeyRepository scopes every call to the tenant of its context, except
+// GetActiveByHash, which authenticates requests before their tenant is
+// known.
+type APIKeyRepository struct {
+	q apiKeysQuerier
+}
+
+func NewAPIKeyRepository(q apiKeysQuerier) ports.APIKeyRepository {
+	return &APIKeyRepository{q: q}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, k entity.APIKey) (entity.APIKey, error) {
+	scopes := make([]string, len(k.Scopes))
+	for i, s := range k.Scopes {
+		scopes[i] = string(s)
+	}
+
+	row, err := r.q.CreateAPIKey(ctx, sqlc.CreateAPIKeyParams{
+		ID:       k.ID,
+		TenantID: tenantOf(ctx),
+		Name:     k.Name,
+		Prefix:   k.Prefix,
+		KeyHash:  k.Hash,
+		Scopes:   scopes,
+	})
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	return toAPIKeyEntity(row), nil
+}
+
+func (r *APIKeyRepository) GetActiveByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	row, err := r.q.GetActiveAPIKeyByHash(ctx, hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.APIKey{}, errs.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	return toAPIKeyEntity(row), nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context) ([]entity.APIKey, error) {
+	rows, err := r.q.ListAPIKeys(ctx, tenantOf(ctx))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]entity.APIKey, len(rows))
+	for i, row := range rows {
+		keys[i] = toAPIKeyEntity(row)
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) Rotate(ctx context.Context, id uuid.UUID, prefix, hash string) (entity.APIKey, error) {
+	row, err := r.q.RotateAPIKey(ctx, sqlc.RotateAPIKeyParams{TenantID: tenantOf(ctx), ID: id, Prefix: prefix, KeyHash: hash})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.APIKey{}, errs.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+	return toAPIKeyEntity(row), nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
+	revoked, err := r.q.RevokeAPIKey(ctx, sqlc.RevokeAPIKeyParams{TenantID: tenantOf(ctx), ID: id})
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return errs.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return r.q.TouchAPIKey(ctx, sqlc.TouchAPIKeyParams{TenantID: tenantOf(ctx), ID: id, LastUsedAt: usedAt})
+}
+
+func toAPIKeyEntity(row sqlc.APIKey) entity.APIKey {
+	scopes := make([]entity.Scope, len(row.Scopes))
+	for i, s := range row.Scopes {
+		scopes[i] = entity.Scope(s)
+	}
+	return entity.APIKey{
+		ID:         row.ID,
+		TenantID:   entity.TenantID(row.TenantID),
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Hash:       row.KeyHash,
+		Scopes:     scopes,
+		CreatedAt:  row.CreatedAt,
+		LastUsedAt: row.LastUsedAt,
+		RevokedAt:  row.RevokedAt,
+	}
+}
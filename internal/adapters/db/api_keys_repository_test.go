@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAPIKeyQueries struct{ mock.Mock }
+
+func (m *mockAPIKeyQueries) CreateAPIKey(ctx context.Context, arg sqlc.CreateAPIKeyParams) (sqlc.APIKey, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyQueries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (sqlc.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	return args.Get(0).(sqlc.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyQueries) ListAPIKeys(ctx context.Context, tenantID string) ([]sqlc.APIKey, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]sqlc.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyQueries) RotateAPIKey(ctx context.Context, arg sqlc.RotateAPIKeyParams) (sqlc.APIKey, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.APIKey), args.Error(1)
+}
+
+func (m *mockAPIKeyQueries) RevokeAPIKey(ctx context.Context, arg sqlc.RevokeAPIKeyParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockAPIKeyQueries) TouchAPIKey(ctx context.Context, arg sqlc.TouchAPIKeyParams) error {
+	return m.Called(ctx, arg).Error(0)
+}
+
+func TestAPIKeyRepositoryCreate(t *testing.T) {
+	mq := new(mockAPIKeyQueries)
+	repo := NewAPIKeyRepository(mq)
+
+	k := entity.APIKey{ID: uuid.New(), Name: "backend", Prefix: "mk_abcdefgh", Hash: "hash", Scopes: []entity.Scope{entity.ScopeNotificationsSend}}
+	mq.On("CreateAPIKey", mock.Anything, sqlc.CreateAPIKeyParams{
+		ID:       k.ID,
+		TenantID: "shop",
+		Name:     "backend",
+		Prefix:   "mk_abcdefgh",
+		KeyHash:  "hash",
+		Scopes:   []string{"notifications:send"},
+	}).Return(sqlc.APIKey{ID: k.ID, TenantID: "shop", Name: "backend", KeyHash: "hash", Scopes: []string{"notifications:send"}}, nil)
+
+	saved, err := repo.Create(entity.WithTenant(defaultTenant(), "shop"), k)
+	require.NoError(t, err)
+	require.Equal(t, []entity.Scope{entity.ScopeNotificationsSend}, saved.Scopes)
+	require.Equal(t, entity.TenantID("shop"), saved.TenantID)
+}
+
+func TestAPIKeyRepositoryGetActiveByHashNotFound(t *testing.T) {
+	mq := new(mockAPIKeyQueries)
+	repo := NewAPIKeyRepository(mq)
+	mq.On("GetActiveAPIKeyByHash", mock.Anything, "hash").Return(sqlc.APIKey{}, pgx.ErrNoRows)
+
+	_, err := repo.GetActiveByHash(defaultTenant(), "hash")
+	require.ErrorIs(t, err, errs.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyRepositoryRevokeUnknown(t *testing.T) {
+	mq := new(mockAPIKeyQueries)
+	repo := NewAPIKeyRepository(mq)
+	id := uuid.New()
+	mq.On("RevokeAPIKey", mock.Anything, sqlc.RevokeAPIKeyParams{TenantID: "default", ID: id}).Return(int64(0), nil)
+
+	require.ErrorIs(t, repo.Revoke(defaultTenant(), id), errs.ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyRepositoryTouch(t *testing.T) {
+	mq := new(mockAPIKeyQueries)
+	repo := NewAPIKeyRepository(mq)
+	id, now := uuid.New(), time.Now()
+	mq.On("TouchAPIKey", mock.Anything, sqlc.TouchAPIKeyParams{TenantID: "shop", ID: id, LastUsedAt: now}).Return(nil)
+
+	require.NoError(t, repo.Touch(entity.WithTenant(defaultTenant(), "shop"), id, now))
+	mq.AssertExpectations(t)
+}
@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// broadcastsQuerier is the subset of *sqlc.Queries used by
+// BroadcastRepository.
+type broadcastsQuerier interface {
+	CreateBroadcast(ctx context.Context, arg sqlc.CreateBroadcastParams) (sqlc.Broadcast, error)
+	GetBroadcast(ctx context.Context, arg sqlc.GetBroadcastParams) (sqlc.Broadcast, error)
+	ClaimBroadcast(ctx context.Context, arg sqlc.ClaimBroadcastParams) (sqlc.Broadcast, error)
+	UpdateBroadcastProgress(ctx context.Context, arg sqlc.UpdateBroadcastProgressParams) (sqlc.Broadcast, error)
+}
+
+// BroadcastRepository scopes Create and Get to the tenant of their
+// context. Workers claim and update broadcasts of every tenant.
+type BroadcastRepository struct {
+	q broadcastsQuerier
+}
+
+func NewBroadcastRepository(q broadcastsQuerier) ports.BroadcastRepository {
+	return &BroadcastRepository{q: q}
+}
+
+func (r *BroadcastRepository) Create(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	rawMetadata, err := marshalMetadata(b.Metadata)
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+
+	row, err := r.q.CreateBroadcast(ctx, sqlc.CreateBroadcastParams{
+		ID:        b.ID,
+		TenantID:  tenantOf(ctx),
+		SegmentID: b.SegmentID,
+		Type:      string(b.Type),
+		Title:     b.Title,
+		Message:   b.Message,
+		ActionURL: b.ActionURL,
+		Metadata:  rawMetadata,
+		Total:     int32(b.Total),
+	})
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+	return toBroadcastEntity(row), nil
+}
+
+func (r *BroadcastRepository) Get(ctx context.Context, id uuid.UUID) (entity.Broadcast, error) {
+	row, err := r.q.GetBroadcast(ctx, sqlc.GetBroadcastParams{TenantID: tenantOf(ctx), ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Broadcast{}, errs.ErrBroadcastNotFound
+	}
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+	return toBroadcastEntity(row), nil
+}
+
+func (r *BroadcastRepository) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Broadcast, error) {
+	row, err := r.q.ClaimBroadcast(ctx, sqlc.ClaimBroadcastParams{Now: now, LeasedUntil: leasedUntil})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Broadcast{}, errs.ErrBroadcastNotFound
+	}
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+	return toBroadcastEntity(row), nil
+}
+
+func (r *BroadcastRepository) SaveProgress(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	if b.LeasedUntil == nil {
+		return entity.Broadcast{}, errs.ErrBroadcastNotFound
+	}
+	var lastUserID *uuid.UUID
+	if b.LastUserID != uuid.Nil {
+		lastUserID = &b.LastUserID
+	}
+
+	row, err := r.q.UpdateBroadcastProgress(ctx, sqlc.UpdateBroadcastProgressParams{
+		ID:          b.ID,
+		Status:      string(b.Status),
+		LastUserID:  lastUserID,
+		Processed:   int32(b.Processed),
+		Sent:        int32(b.Sent),
+		RateLimited: int32(b.RateLimited),
+		Skipped:     int32(b.Skipped),
+		Failed:      int32(b.Failed),
+		CompletedAt: b.CompletedAt,
+		LeasedUntil: *b.LeasedUntil,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Broadcast{}, errs.ErrBroadcastNotFound
+	}
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+	return toBroadcastEntity(row), nil
+}
+
+func toBroadcastEntity(row sqlc.Broadcast) entity.Broadcast {
+	b := entity.Broadcast{
+		ID:          row.ID,
+		TenantID:    entity.TenantID(row.TenantID),
+		SegmentID:   row.SegmentID,
+		Type:        entity.NotificationType(row.Type),
+		Title:       row.Title,
+		Message:     row.Message,
+		ActionURL:   row.ActionURL,
+		Status:      entity.BroadcastStatus(row.Status),
+		Total:       int(row.Total),
+		Processed:   int(row.Processed),
+		Sent:        int(row.Sent),
+		RateLimited: int(row.RateLimited),
+		Skipped:     int(row.Skipped),
+		Failed:      int(row.Failed),
+		LeasedUntil: row.LeasedUntil,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		CompletedAt: row.CompletedAt,
+	}
+	if row.LastUserID != nil {
+		b.LastUserID = *row.LastUserID
+	}
+	// metadata is only written by Create, always as a JSON object of strings.
+	_ = json.Unmarshal(row.Metadata, &b.Metadata)
+	return b
+}
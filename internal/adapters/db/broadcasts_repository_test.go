@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBroadcastQueries struct{ mock.Mock }
+
+func (m *mockBroadcastQueries) CreateBroadcast(ctx context.Context, arg sqlc.CreateBroadcastParams) (sqlc.Broadcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Broadcast), args.Error(1)
+}
+
+func (m *mockBroadcastQueries) GetBroadcast(ctx context.Context, arg sqlc.GetBroadcastParams) (sqlc.Broadcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Broadcast), args.Error(1)
+}
+
+func (m *mockBroadcastQueries) ClaimBroadcast(ctx context.Context, arg sqlc.ClaimBroadcastParams) (sqlc.Broadcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Broadcast), args.Error(1)
+}
+
+func (m *mockBroadcastQueries) UpdateBroadcastProgress(ctx context.Context, arg sqlc.UpdateBroadcastProgressParams) (sqlc.Broadcast, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Broadcast), args.Error(1)
+}
+
+func TestBroadcastRepositoryClaimNone(t *testing.T) {
+	mq := new(mockBroadcastQueries)
+	repo := NewBroadcastRepository(mq)
+
+	now := time.Now()
+	mq.On("ClaimBroadcast", mock.Anything, sqlc.ClaimBroadcastParams{Now: now, LeasedUntil: now.Add(time.Minute)}).Return(sqlc.Broadcast{}, pgx.ErrNoRows)
+
+	_, err := repo.Claim(defaultTenant(), now, now.Add(time.Minute))
+	require.ErrorIs(t, err, errs.ErrBroadcastNotFound)
+}
+
+func TestBroadcastRepositorySaveProgress(t *testing.T) {
+	mq := new(mockBroadcastQueries)
+	repo := NewBroadcastRepository(mq)
+
+	lease := time.Now().Add(time.Minute)
+	last := uuid.New()
+	b := entity.Broadcast{ID: uuid.New(), Status: entity.BroadcastRunning, LastUserID: last, Processed: 3, Sent: 2, RateLimited: 1, LeasedUntil: &lease}
+	mq.On("UpdateBroadcastProgress", mock.Anything, sqlc.UpdateBroadcastProgressParams{
+		ID:          b.ID,
+		Status:      "running",
+		LastUserID:  &last,
+		Processed:   3,
+		Sent:        2,
+		RateLimited: 1,
+		LeasedUntil: lease,
+	}).Return(sqlc.Broadcast{ID: b.ID, Status: "running", LastUserID: &last, Processed: 3, Metadata: []byte("{}")}, nil)
+
+	saved, err := repo.SaveProgress(defaultTenant(), b)
+	require.NoError(t, err)
+	require.Equal(t, last, saved.LastUserID)
+	require.Nil(t, saved.LeasedUntil)
+
+	mq.AssertExpectations(t)
+}
+
+func TestBroadcastRepositorySaveProgressLeaseLost(t *testing.T) {
+	mq := new(mockBroadcastQueries)
+	repo := NewBroadcastRepository(mq)
+
+	lease := time.Now()
+	mq.On("UpdateBroadcastProgress", mock.Anything, mock.Anything).Return(sqlc.Broadcast{}, pgx.ErrNoRows)
+
+	_, err := repo.SaveProgress(defaultTenant(), entity.Broadcast{ID: uuid.New(), LeasedUntil: &lease})
+	require.ErrorIs(t, err, errs.ErrBroadcastNotFound)
+}
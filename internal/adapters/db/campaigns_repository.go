@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// campaignsQuerier is the subset of *sqlc.Queries used by
+// CampaignRepository.
+type campaignsQuerier interface {
+	CreateCampaign(ctx context.Context, arg sqlc.CreateCampaignParams) (sqlc.Campaign, error)
+	GetCampaign(ctx context.Context, arg sqlc.GetCampaignParams) (sqlc.Campaign, error)
+	ClaimCampaign(ctx context.Context, arg sqlc.ClaimCampaignParams) (sqlc.Campaign, error)
+	UpdateCampaignProgress(ctx context.Context, arg sqlc.UpdateCampaignProgressParams) (sqlc.Campaign, error)
+	UpdateCampaignStatus(ctx context.Context, arg sqlc.UpdateCampaignStatusParams) (sqlc.Campaign, error)
+}
+
+// CampaignRepository scopes Create, Get and UpdateStatus to the tenant of
+// their context. Workers claim and update campaigns of every tenant.
+type CampaignRepository struct {
+	q campaignsQuerier
+}
+
+func NewCampaignRepository(q campaignsQuerier) ports.CampaignRepository {
+	return &CampaignRepository{q: q}
+}
+
+func (r *CampaignRepository) Create(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	data := c.Data
+	if data == nil {
+		data = map[string]any{}
+	}
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+
+	row, err := r.q.CreateCampaign(ctx, sqlc.CreateCampaignParams{
+		ID:              c.ID,
+		TenantID:        tenantOf(ctx),
+		Name:            c.Name,
+		SegmentID:       c.SegmentID,
+		TemplateID:      c.TemplateID,
+		TemplateVersion: int32(c.TemplateVersion),
+		Data:            rawData,
+		StartAt:         c.StartAt,
+		RatePerMinute:   int32(c.RatePerMinute),
+		Total:           int32(c.Total),
+	})
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+	return toCampaignEntity(row), nil
+}
+
+func (r *CampaignRepository) Get(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	row, err := r.q.GetCampaign(ctx, sqlc.GetCampaignParams{TenantID: tenantOf(ctx), ID: id})
+	return r.result(row, err)
+}
+
+func (r *CampaignRepository) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Campaign, error) {
+	row, err := r.q.ClaimCampaign(ctx, sqlc.ClaimCampaignParams{Now: now, LeasedUntil: leasedUntil})
+	return r.result(row, err)
+}
+
+func (r *CampaignRepository) SaveProgress(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	if c.LeasedUntil == nil {
+		return entity.Campaign{}, errs.ErrCampaignNotFound
+	}
+	var lastUserID *uuid.UUID
+	if c.LastUserID != uuid.Nil {
+		lastUserID = &c.LastUserID
+	}
+
+	row, err := r.q.UpdateCampaignProgress(ctx, sqlc.UpdateCampaignProgressParams{
+		ID:          c.ID,
+		LastUserID:  lastUserID,
+		Processed:   int32(c.Processed),
+		Sent:        int32(c.Sent),
+		RateLimited: int32(c.RateLimited),
+		Suppressed:  int32(c.Suppressed),
+		Failed:      int32(c.Failed),
+		NextRunAt:   c.NextRunAt,
+		Status:      string(c.Status),
+		CompletedAt: c.CompletedAt,
+		LeasedUntil: *c.LeasedUntil,
+	})
+	return r.result(row, err)
+}
+
+func (r *CampaignRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from []entity.CampaignStatus, to entity.CampaignStatus) (entity.Campaign, error) {
+	fromStatuses := make([]string, len(from))
+	for i, status := range from {
+		fromStatuses[i] = string(status)
+	}
+
+	row, err := r.q.UpdateCampaignStatus(ctx, sqlc.UpdateCampaignStatusParams{
+		TenantID:     tenantOf(ctx),
+		ID:           id,
+		Status:       string(to),
+		FromStatuses: fromStatuses,
+	})
+	return r.result(row, err)
+}
+
+func (r *CampaignRepository) result(row sqlc.Campaign, err error) (entity.Campaign, error) {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Campaign{}, errs.ErrCampaignNotFound
+	}
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+	return toCampaignEntity(row), nil
+}
+
+func toCampaignEntity(row sqlc.Campaign) entity.Campaign {
+	c := entity.Campaign{
+		ID:              row.ID,
+		TenantID:        entity.TenantID(row.TenantID),
+		Name:            row.Name,
+		SegmentID:       row.SegmentID,
+		TemplateID:      row.TemplateID,
+		TemplateVersion: int(row.TemplateVersion),
+		StartAt:         row.StartAt,
+		RatePerMinute:   int(row.RatePerMinute),
+		Status:          entity.CampaignStatus(row.Status),
+		Total:           int(row.Total),
+		Processed:       int(row.Processed),
+		Sent:            int(row.Sent),
+		RateLimited:     int(row.RateLimited),
+		Suppressed:      int(row.Suppressed),
+		Failed:          int(row.Failed),
+		NextRunAt:       row.NextRunAt,
+		LeasedUntil:     row.LeasedUntil,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		CompletedAt:     row.CompletedAt,
+	}
+	if row.LastUserID != nil {
+		c.LastUserID = *row.LastUserID
+	}
+	// data is only written by Create, always as a JSON object.
+	_ = json.Unmarshal(row.Data, &c.Data)
+	return c
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockCampaignQueries struct{ mock.Mock }
+
+func (m *mockCampaignQueries) CreateCampaign(ctx context.Context, arg sqlc.CreateCampaignParams) (sqlc.Campaign, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Campaign), args.Error(1)
+}
+
+func (m *mockCampaignQueries) GetCampaign(ctx context.Context, arg sqlc.GetCampaignParams) (sqlc.Campaign, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Campaign), args.Error(1)
+}
+
+func (m *mockCampaignQueries) ClaimCampaign(ctx context.Context, arg sqlc.ClaimCampaignParams) (sqlc.Campaign, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Campaign), args.Error(1)
+}
+
+func (m *mockCampaignQueries) UpdateCampaignProgress(ctx context.Context, arg sqlc.UpdateCampaignProgressParams) (sqlc.Campaign, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Campaign), args.Error(1)
+}
+
+func (m *mockCampaignQueries) UpdateCampaignStatus(ctx context.Context, arg sqlc.UpdateCampaignStatusParams) (sqlc.Campaign, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Campaign), args.Error(1)
+}
+
+func TestCampaignRepositoryCreate(t *testing.T) {
+	mq := new(mockCampaignQueries)
+	repo := NewCampaignRepository(mq)
+
+	start := time.Now().Add(time.Hour)
+	c := entity.Campaign{
+		ID:              uuid.New(),
+		Name:            "spring sale",
+		SegmentID:       uuid.New(),
+		TemplateID:      uuid.New(),
+		TemplateVersion: 2,
+		Data:            map[string]any{"discount": "20%"},
+		StartAt:         start,
+		RatePerMinute:   600,
+		Total:           1000,
+	}
+	mq.On("CreateCampaign", mock.Anything, sqlc.CreateCampaignParams{
+		ID:              c.ID,
+		TenantID:        "default",
+		Name:            c.Name,
+		SegmentID:       c.SegmentID,
+		TemplateID:      c.TemplateID,
+		TemplateVersion: 2,
+		Data:            []byte(`{"discount":"20%"}`),
+		StartAt:         start,
+		RatePerMinute:   600,
+		Total:           1000,
+	}).Return(sqlc.Campaign{ID: c.ID, Status: "scheduled", Data: []byte(`{"discount":"20%"}`), NextRunAt: start}, nil)
+
+	saved, err := repo.Create(defaultTenant(), c)
+	require.NoError(t, err)
+	require.Equal(t, entity.CampaignScheduled, saved.Status)
+	require.Equal(t, "20%", saved.Data["discount"])
+	require.Equal(t, start, saved.NextRunAt)
+}
+
+func TestCampaignRepositoryClaimNone(t *testing.T) {
+	mq := new(mockCampaignQueries)
+	repo := NewCampaignRepository(mq)
+
+	now := time.Now()
+	mq.On("ClaimCampaign", mock.Anything, sqlc.ClaimCampaignParams{Now: now, LeasedUntil: now.Add(time.Minute)}).Return(sqlc.Campaign{}, pgx.ErrNoRows)
+
+	_, err := repo.Claim(defaultTenant(), now, now.Add(time.Minute))
+	require.ErrorIs(t, err, errs.ErrCampaignNotFound)
+}
+
+func TestCampaignRepositorySaveProgressLeaseLost(t *testing.T) {
+	mq := new(mockCampaignQueries)
+	repo := NewCampaignRepository(mq)
+
+	lease := time.Now()
+	mq.On("UpdateCampaignProgress", mock.Anything, mock.Anything).Return(sqlc.Campaign{}, pgx.ErrNoRows)
+
+	_, err := repo.SaveProgress(defaultTenant(), entity.Campaign{ID: uuid.New(), LeasedUntil: &lease})
+	require.ErrorIs(t, err, errs.ErrCampaignNotFound)
+}
+
+func TestCampaignRepositoryUpdateStatus(t *testing.T) {
+	mq := new(mockCampaignQueries)
+	repo := NewCampaignRepository(mq)
+
+	id := uuid.New()
+	mq.On("UpdateCampaignStatus", mock.Anything, sqlc.UpdateCampaignStatusParams{
+		TenantID:     "shop",
+		ID:           id,
+		Status:       "paused",
+		FromStatuses: []string{"scheduled", "running"},
+	}).Return(sqlc.Campaign{ID: id, Status: "paused", Data: []byte("{}")}, nil)
+
+	saved, err := repo.UpdateStatus(entity.WithTenant(defaultTenant(), "shop"), id,
+		[]entity.CampaignStatus{entity.CampaignScheduled, entity.CampaignRunning}, entity.CampaignPaused)
+	require.NoError(t, err)
+	require.Equal(t, entity.CampaignPaused, saved.Status)
+}
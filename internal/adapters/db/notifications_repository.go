@@ -2,21 +2,51 @@ package db
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/ports"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// uniqueViolation is the Postgres error code raised when an insert clashes
+// with a unique index.
+const uniqueViolation = "23505"
+
 // notificationsQuerier is a minimal interface implemented by *sqlc.Queries
 // that allows the repository to be tested with simple mocks.
 type notificationsQuerier interface {
 	CreateNotification(ctx context.Context, arg sqlc.CreateNotificationParams) (sqlc.Notification, error)
+	CopyNotifications(ctx context.Context, arg []sqlc.CopyNotificationsParams) (int64, error)
 	CountNotificationsInTimeWindow(ctx context.Context, arg sqlc.CountNotificationsInTimeWindowParams) (int64, error)
+	CountNotificationsInTimeWindowByUser(ctx context.Context, arg sqlc.CountNotificationsInTimeWindowByUserParams) ([]sqlc.CountNotificationsInTimeWindowByUserRow, error)
+	FindDuplicateNotification(ctx context.Context, arg sqlc.FindDuplicateNotificationParams) (sqlc.Notification, error)
+	GetNotification(ctx context.Context, arg sqlc.GetNotificationParams) (sqlc.Notification, error)
+	GetNotificationByIdempotencyKey(ctx context.Context, arg sqlc.GetNotificationByIdempotencyKeyParams) (sqlc.Notification, error)
+	ReleaseIdempotencyKey(ctx context.Context, arg sqlc.ReleaseIdempotencyKeyParams) error
+	ListHeldNotificationGroups(ctx context.Context, arg sqlc.ListHeldNotificationGroupsParams) ([]sqlc.ListHeldNotificationGroupsRow, error)
+	ClaimHeldNotifications(ctx context.Context, arg sqlc.ClaimHeldNotificationsParams) ([]sqlc.Notification, error)
+	ClaimDueNotifications(ctx context.Context, arg sqlc.ClaimDueNotificationsParams) ([]sqlc.Notification, error)
+	ListUserNotifications(ctx context.Context, arg sqlc.ListUserNotificationsParams) ([]sqlc.Notification, error)
+	UpdateNotificationStatus(ctx context.Context, arg sqlc.UpdateNotificationStatusParams) (sqlc.Notification, error)
+	ListSentNotificationsAfter(ctx context.Context, arg sqlc.ListSentNotificationsAfterParams) ([]sqlc.Notification, error)
+	CountUnreadNotifications(ctx context.Context, arg sqlc.CountUnreadNotificationsParams) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg sqlc.MarkNotificationReadParams) (sqlc.Notification, error)
+	MarkNotificationsRead(ctx context.Context, arg sqlc.MarkNotificationsReadParams) (int64, error)
+	MarkAllNotificationsRead(ctx context.Context, arg sqlc.MarkAllNotificationsReadParams) (int64, error)
+	SetNotificationArchived(ctx context.Context, arg sqlc.SetNotificationArchivedParams) (sqlc.Notification, error)
 }
 
+// NotificationRepository scopes every call to the tenant of its context,
+// except ClaimDue and ListHeldGroups, which background workers use to find
+// work across tenants.
 type NotificationRepository struct {
 	q notificationsQuerier
 }
@@ -26,32 +56,376 @@ func NewNotificationRepository(q notificationsQuerier) ports.NotificationReposit
 }
 
 func (r *NotificationRepository) Create(ctx context.Context, n entity.Notification) (entity.Notification, error) {
+	rawMetadata, err := marshalMetadata(n.Metadata)
+	if err != nil {
+		return entity.Notification{}, err
+	}
+
 	row, err := r.q.CreateNotification(ctx, sqlc.CreateNotificationParams{
-		UserID:  n.UserID,
-		Type:    string(n.Type),
-		Message: n.Message,
+		ID:              n.ID,
+		TenantID:        tenantOf(ctx),
+		UserID:          n.UserID,
+		Type:            string(n.Type),
+		Title:           n.Title,
+		Message:         n.Message,
+		ActionURL:       n.ActionURL,
+		Metadata:        rawMetadata,
+		Status:          string(n.Status),
+		SendAt:          n.SendAt,
+		SentAt:          n.SentAt,
+		ExpiresAt:       n.ExpiresAt,
+		StatusReason:    n.StatusReason,
+		IdempotencyKey:  nullableString(n.IdempotencyKey),
+		RequestHash:     n.RequestHash,
+		ContentHash:     n.ContentHash(),
+		TemplateID:      n.TemplateID,
+		TemplateVersion: int32(n.TemplateVersion),
+		Locale:          nullableString(n.Locale),
 	})
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			logging.FromContext(ctx).Debug("notification already stored", "constraint", pgErr.ConstraintName)
+			return entity.Notification{}, errs.ErrNotificationExists
+		}
 		return entity.Notification{}, err
 	}
 
-	return entity.Notification{
-		ID:        row.ID,
-		UserID:    row.UserID,
-		Type:      entity.NotificationType(row.Type),
-		Message:   row.Message,
-		CreatedAt: row.CreatedAt,
-	}, nil
+	logging.FromContext(ctx).Debug("notification inserted", "status", row.Status)
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) CreateMany(ctx context.Context, ns []entity.Notification) error {
+	tenant := tenantOf(ctx)
+	rows := make([]sqlc.CopyNotificationsParams, 0, len(ns))
+	for _, n := range ns {
+		rawMetadata, err := marshalMetadata(n.Metadata)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, sqlc.CopyNotificationsParams{
+			ID:           n.ID,
+			TenantID:     tenant,
+			UserID:       n.UserID,
+			Type:         string(n.Type),
+			Title:        n.Title,
+			Message:      n.Message,
+			ActionURL:    n.ActionURL,
+			Metadata:     rawMetadata,
+			Status:       string(n.Status),
+			StatusReason: n.StatusReason,
+			SentAt:       n.SentAt,
+			ExpiresAt:    n.ExpiresAt,
+			ContentHash:  n.ContentHash(),
+			CreatedAt:    n.CreatedAt,
+		})
+	}
+
+	copied, err := r.q.CopyNotifications(ctx, rows)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return errs.ErrNotificationExists
+	}
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Debug("notifications copied", "rows", copied)
+	return nil
 }
 
 func (r *NotificationRepository) CountInTimeWindow(ctx context.Context, userID uuid.UUID, notifType entity.NotificationType, since time.Time) (int, error) {
 	count, err := r.q.CountNotificationsInTimeWindow(ctx, sqlc.CountNotificationsInTimeWindowParams{
-		UserID:    userID,
-		Type:      string(notifType),
-		CreatedAt: since,
+		TenantID: tenantOf(ctx),
+		UserID:   userID,
+		Type:     string(notifType),
+		Since:    since,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *NotificationRepository) CountInTimeWindowByUser(ctx context.Context, userIDs []uuid.UUID, notifType entity.NotificationType, since time.Time) (map[uuid.UUID]int, error) {
+	rows, err := r.q.CountNotificationsInTimeWindowByUser(ctx, sqlc.CountNotificationsInTimeWindowByUserParams{
+		TenantID: tenantOf(ctx),
+		UserIDs:  userIDs,
+		Type:     string(notifType),
+		Since:    since,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserID] = int(row.Total)
+	}
+	return counts, nil
+}
+
+func (r *NotificationRepository) FindDuplicate(ctx context.Context, n entity.Notification, since time.Time) (entity.Notification, error) {
+	row, err := r.q.FindDuplicateNotification(ctx, sqlc.FindDuplicateNotificationParams{
+		TenantID:    tenantOf(ctx),
+		UserID:      n.UserID,
+		Type:        string(n.Type),
+		ContentHash: n.ContentHash(),
+		Since:       since,
+	})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (entity.Notification, error) {
+	row, err := r.q.GetNotification(ctx, sqlc.GetNotificationParams{TenantID: tenantOf(ctx), ID: id})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) GetByIdempotencyKey(ctx context.Context, key string) (entity.Notification, error) {
+	row, err := r.q.GetNotificationByIdempotencyKey(ctx, sqlc.GetNotificationByIdempotencyKeyParams{
+		TenantID:       tenantOf(ctx),
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) ReleaseIdempotencyKey(ctx context.Context, id uuid.UUID) error {
+	return r.q.ReleaseIdempotencyKey(ctx, sqlc.ReleaseIdempotencyKeyParams{TenantID: tenantOf(ctx), ID: id})
+}
+
+func (r *NotificationRepository) ClaimDue(ctx context.Context, now, leasedUntil time.Time, limit int) ([]entity.Notification, error) {
+	rows, err := r.q.ClaimDueNotifications(ctx, sqlc.ClaimDueNotificationsParams{
+		LeasedUntil: leasedUntil,
+		Now:         now,
+		MaxRows:     int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Notification, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toEntity(row))
+	}
+	return out, nil
+}
+
+func (r *NotificationRepository) ListHeldGroups(ctx context.Context, after entity.DigestGroup, limit int) ([]entity.DigestGroup, error) {
+	rows, err := r.q.ListHeldNotificationGroups(ctx, sqlc.ListHeldNotificationGroupsParams{
+		AfterTenantID: string(after.TenantID),
+		AfterUserID:   after.UserID,
+		AfterType:     string(after.Type),
+		MaxRows:       int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.DigestGroup, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, entity.DigestGroup{
+			TenantID: entity.TenantID(row.TenantID),
+			UserID:   row.UserID,
+			Type:     entity.NotificationType(row.Type),
+		})
+	}
+	return out, nil
+}
+
+func (r *NotificationRepository) ClaimHeld(ctx context.Context, g entity.DigestGroup, reason string) ([]entity.Notification, error) {
+	rows, err := r.q.ClaimHeldNotifications(ctx, sqlc.ClaimHeldNotificationsParams{
+		TenantID: tenantOf(ctx),
+		UserID:   g.UserID,
+		Type:     string(g.Type),
+		Reason:   reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Notification, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toEntity(row))
+	}
+	return out, nil
+}
+
+func (r *NotificationRepository) List(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	arg := sqlc.ListUserNotificationsParams{
+		TenantID:    tenantOf(ctx),
+		UserID:      filter.UserID,
+		CreatedFrom: filter.From,
+		CreatedTo:   filter.To,
+		Read:        filter.Read,
+		Archived:    filter.Archived,
+		MaxRows:     int32(filter.Limit),
+	}
+	for _, t := range filter.Types {
+		arg.Types = append(arg.Types, string(t))
+	}
+	for _, s := range filter.Statuses {
+		arg.Statuses = append(arg.Statuses, string(s))
+	}
+	if filter.After != nil {
+		arg.AfterCreatedAt = &filter.After.CreatedAt
+		arg.AfterID = &filter.After.ID
+	}
+
+	rows, err := r.q.ListUserNotifications(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Notification, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toEntity(row))
+	}
+	return out, nil
+}
+
+func (r *NotificationRepository) ListSentAfter(ctx context.Context, last entity.Notification, limit int) ([]entity.Notification, error) {
+	if last.SentAt == nil {
+		return nil, nil
+	}
+
+	rows, err := r.q.ListSentNotificationsAfter(ctx, sqlc.ListSentNotificationsAfterParams{
+		TenantID:    tenantOf(ctx),
+		UserID:      last.UserID,
+		AfterSentAt: *last.SentAt,
+		AfterID:     last.ID,
+		MaxRows:     int32(limit),
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Notification, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toEntity(row))
+	}
+	return out, nil
+}
+
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := r.q.CountUnreadNotifications(ctx, sqlc.CountUnreadNotificationsParams{TenantID: tenantOf(ctx), UserID: userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID) (entity.Notification, error) {
+	row, err := r.q.MarkNotificationRead(ctx, sqlc.MarkNotificationReadParams{TenantID: tenantOf(ctx), ID: id, UserID: userID})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) MarkManyRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	count, err := r.q.MarkNotificationsRead(ctx, sqlc.MarkNotificationsReadParams{TenantID: tenantOf(ctx), UserID: userID, Ids: ids})
 	if err != nil {
 		return 0, err
 	}
 	return int(count), nil
 }
+
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := r.q.MarkAllNotificationsRead(ctx, sqlc.MarkAllNotificationsReadParams{TenantID: tenantOf(ctx), UserID: userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func (r *NotificationRepository) SetArchived(ctx context.Context, userID, id uuid.UUID, archived bool) (entity.Notification, error) {
+	row, err := r.q.SetNotificationArchived(ctx, sqlc.SetNotificationArchivedParams{
+		TenantID: tenantOf(ctx),
+		Archived: archived,
+		ID:       id,
+		UserID:   userID,
+	})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	return toEntity(row), nil
+}
+
+func (r *NotificationRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.NotificationStatus, reason string, at time.Time) (entity.Notification, error) {
+	row, err := r.q.UpdateNotificationStatus(ctx, sqlc.UpdateNotificationStatusParams{
+		TenantID:   tenantOf(ctx),
+		ID:         id,
+		FromStatus: string(from),
+		ToStatus:   string(to),
+		Reason:     reason,
+		ChangedAt:  at,
+	})
+	if err != nil {
+		return entity.Notification{}, mapNotFound(err)
+	}
+	logging.FromContext(ctx).Debug("notification status changed", "from", from, "to", to)
+	return toEntity(row), nil
+}
+
+func toEntity(row sqlc.Notification) entity.Notification {
+	n := entity.Notification{
+		ID:              row.ID,
+		TenantID:        entity.TenantID(row.TenantID),
+		UserID:          row.UserID,
+		Type:            entity.NotificationType(row.Type),
+		Title:           row.Title,
+		Message:         row.Message,
+		ActionURL:       row.ActionURL,
+		Status:          entity.NotificationStatus(row.Status),
+		StatusReason:    row.StatusReason,
+		SendAt:          row.SendAt,
+		SentAt:          row.SentAt,
+		ExpiresAt:       row.ExpiresAt,
+		ReadAt:          row.ReadAt,
+		ArchivedAt:      row.ArchivedAt,
+		RequestHash:     row.RequestHash,
+		TemplateID:      row.TemplateID,
+		TemplateVersion: int(row.TemplateVersion),
+		CreatedAt:       row.CreatedAt,
+	}
+	if row.IdempotencyKey != nil {
+		n.IdempotencyKey = *row.IdempotencyKey
+	}
+	if row.Locale != nil {
+		n.Locale = *row.Locale
+	}
+	// metadata is only written by Create, always as a JSON object of strings.
+	_ = json.Unmarshal(row.Metadata, &n.Metadata)
+	return n
+}
+
+func nullableString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func mapNotFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errs.ErrNotificationNotFound
+	}
+	return err
+}
+
+// marshalMetadata encodes metadata for the jsonb column, storing an empty
+// object rather than null when there is none.
+func marshalMetadata(metadata map[string]string) ([]byte, error) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return json.Marshal(metadata)
+}
@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -24,6 +27,92 @@ func (m *mockQueries) CountNotificationsInTimeWindow(ctx context.Context, arg sq
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockQueries) CountNotificationsInTimeWindowByUser(ctx context.Context, arg sqlc.CountNotificationsInTimeWindowByUserParams) ([]sqlc.CountNotificationsInTimeWindowByUserRow, error) {
+	args := m.Called(ctx, arg)
+	rows, _ := args.Get(0).([]sqlc.CountNotificationsInTimeWindowByUserRow)
+	return rows, args.Error(1)
+}
+
+func (m *mockQueries) CopyNotifications(ctx context.Context, arg []sqlc.CopyNotificationsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockQueries) FindDuplicateNotification(ctx context.Context, arg sqlc.FindDuplicateNotificationParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) GetNotification(ctx context.Context, arg sqlc.GetNotificationParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) GetNotificationByIdempotencyKey(ctx context.Context, arg sqlc.GetNotificationByIdempotencyKeyParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) ReleaseIdempotencyKey(ctx context.Context, arg sqlc.ReleaseIdempotencyKeyParams) error {
+	args := m.Called(ctx, arg)
+	return args.Error(0)
+}
+
+func (m *mockQueries) ListHeldNotificationGroups(ctx context.Context, arg sqlc.ListHeldNotificationGroupsParams) ([]sqlc.ListHeldNotificationGroupsRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlc.ListHeldNotificationGroupsRow), args.Error(1)
+}
+
+func (m *mockQueries) ClaimHeldNotifications(ctx context.Context, arg sqlc.ClaimHeldNotificationsParams) ([]sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) ListUserNotifications(ctx context.Context, arg sqlc.ListUserNotificationsParams) ([]sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) CountUnreadNotifications(ctx context.Context, arg sqlc.CountUnreadNotificationsParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockQueries) MarkNotificationRead(ctx context.Context, arg sqlc.MarkNotificationReadParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) MarkNotificationsRead(ctx context.Context, arg sqlc.MarkNotificationsReadParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockQueries) MarkAllNotificationsRead(ctx context.Context, arg sqlc.MarkAllNotificationsReadParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockQueries) SetNotificationArchived(ctx context.Context, arg sqlc.SetNotificationArchivedParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) ListSentNotificationsAfter(ctx context.Context, arg sqlc.ListSentNotificationsAfterParams) ([]sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) ClaimDueNotifications(ctx context.Context, arg sqlc.ClaimDueNotificationsParams) ([]sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).([]sqlc.Notification), args.Error(1)
+}
+
+func (m *mockQueries) UpdateNotificationStatus(ctx context.Context, arg sqlc.UpdateNotificationStatusParams) (sqlc.Notification, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Notification), args.Error(1)
+}
+
 func TestNotificationRepositoryCreate(t *testing.T) {
 	uid := uuid.New()
 	createdAt := time.Now().UTC().Round(time.Second)
@@ -31,25 +120,71 @@ func TestNotificationRepositoryCreate(t *testing.T) {
 	mq := new(mockQueries)
 	repo := NewNotificationRepository(mq)
 
-	input := entity.Notification{UserID: uid, Type: entity.Status, Message: "test"}
-	out := sqlc.Notification{ID: uuid.New(), UserID: uid, Type: string(entity.Status), Message: "test", CreatedAt: createdAt}
+	input := entity.Notification{UserID: uid, Type: entity.Status, Message: "test", Status: entity.StatusSent, SentAt: &createdAt}
+	out := sqlc.Notification{ID: uuid.New(), TenantID: "shop", UserID: uid, Type: string(entity.Status), Message: "test", Status: string(entity.StatusSent), SentAt: &createdAt, CreatedAt: createdAt}
 
 	mq.On("CreateNotification", mock.Anything, sqlc.CreateNotificationParams{
-		UserID:  uid,
-		Type:    string(entity.Status),
-		Message: "test",
+		TenantID:    "shop",
+		UserID:      uid,
+		Type:        string(entity.Status),
+		Message:     "test",
+		Status:      string(entity.StatusSent),
+		SentAt:      &createdAt,
+		ContentHash: input.ContentHash(),
+		Metadata:    []byte(`{}`),
 	}).Return(out, nil)
 
-	saved, err := repo.Create(context.Background(), input)
+	saved, err := repo.Create(entity.WithTenant(defaultTenant(), "shop"), input)
 	require.NoError(t, err)
+	require.Equal(t, entity.TenantID("shop"), saved.TenantID)
 	require.Equal(t, uid, saved.UserID)
 	require.Equal(t, entity.Status, saved.Type)
 	require.Equal(t, "test", saved.Message)
+	require.Equal(t, entity.StatusSent, saved.Status)
 	require.WithinDuration(t, createdAt, saved.CreatedAt, time.Second)
 
 	mq.AssertExpectations(t)
 }
 
+func TestNotificationRepositoryCreateStructuredPayload(t *testing.T) {
+	uid := uuid.New()
+	metadata := []byte(`{"order_id":"42"}`)
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	input := entity.Notification{
+		UserID:    uid,
+		Type:      entity.Status,
+		Title:     "Order shipped",
+		Message:   "Your order is on its way",
+		ActionURL: "https://example.com/orders/42",
+		Metadata:  map[string]string{"order_id": "42"},
+		Status:    entity.StatusSent,
+	}
+
+	mq.On("CreateNotification", mock.Anything, mock.MatchedBy(func(arg sqlc.CreateNotificationParams) bool {
+		return arg.Title == input.Title && arg.ActionURL == input.ActionURL && string(arg.Metadata) == string(metadata)
+	})).Return(sqlc.Notification{
+		ID:        uuid.New(),
+		UserID:    uid,
+		Type:      string(entity.Status),
+		Title:     input.Title,
+		Message:   input.Message,
+		ActionURL: input.ActionURL,
+		Metadata:  metadata,
+		Status:    string(entity.StatusSent),
+	}, nil)
+
+	saved, err := repo.Create(defaultTenant(), input)
+	require.NoError(t, err)
+	require.Equal(t, input.Title, saved.Title)
+	require.Equal(t, input.ActionURL, saved.ActionURL)
+	require.Equal(t, input.Metadata, saved.Metadata)
+
+	mq.AssertExpectations(t)
+}
+
 func TestNotificationRepositoryCountInTimeWindow(t *testing.T) {
 	uid := uuid.New()
 	since := time.Now().Add(-time.Minute)
@@ -58,14 +193,323 @@ func TestNotificationRepositoryCountInTimeWindow(t *testing.T) {
 	repo := NewNotificationRepository(mq)
 
 	mq.On("CountNotificationsInTimeWindow", mock.Anything, sqlc.CountNotificationsInTimeWindowParams{
-		UserID:    uid,
-		Type:      string(entity.Status),
-		CreatedAt: since,
+		TenantID: "default",
+		UserID:   uid,
+		Type:     string(entity.Status),
+		Since:    since,
 	}).Return(int64(42), nil)
 
-	count, err := repo.CountInTimeWindow(context.Background(), uid, entity.Status, since)
+	count, err := repo.CountInTimeWindow(defaultTenant(), uid, entity.Status, since)
 	require.NoError(t, err)
 	require.Equal(t, 42, count)
 
 	mq.AssertExpectations(t)
 }
+
+func TestNotificationRepositoryCountInTimeWindowByUser(t *testing.T) {
+	busy, idle := uuid.New(), uuid.New()
+	since := time.Now().Add(-time.Minute)
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("CountNotificationsInTimeWindowByUser", mock.Anything, sqlc.CountNotificationsInTimeWindowByUserParams{
+		TenantID: "default",
+		UserIDs:  []uuid.UUID{busy, idle},
+		Type:     string(entity.News),
+		Since:    since,
+	}).Return([]sqlc.CountNotificationsInTimeWindowByUserRow{{UserID: busy, Total: 3}}, nil)
+
+	counts, err := repo.CountInTimeWindowByUser(defaultTenant(), []uuid.UUID{busy, idle}, entity.News, since)
+	require.NoError(t, err)
+	require.Equal(t, map[uuid.UUID]int{busy: 3}, counts)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryCreateMany(t *testing.T) {
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	now := time.Now()
+	ns := []entity.Notification{
+		{ID: uuid.New(), UserID: uuid.New(), Type: entity.News, Message: "hello", Status: entity.StatusSent, SentAt: &now, CreatedAt: now},
+		{ID: uuid.New(), UserID: uuid.New(), Type: entity.News, Message: "hello", Status: entity.StatusHeld, Metadata: map[string]string{"k": "v"}, CreatedAt: now},
+	}
+	mq.On("CopyNotifications", mock.Anything, []sqlc.CopyNotificationsParams{
+		{ID: ns[0].ID, TenantID: "default", UserID: ns[0].UserID, Type: "news", Message: "hello", Metadata: []byte("{}"), Status: "sent", SentAt: &now, ContentHash: ns[0].ContentHash(), CreatedAt: now},
+		{ID: ns[1].ID, TenantID: "default", UserID: ns[1].UserID, Type: "news", Message: "hello", Metadata: []byte(`{"k":"v"}`), Status: "held", ContentHash: ns[1].ContentHash(), CreatedAt: now},
+	}).Return(int64(2), nil)
+
+	require.NoError(t, repo.CreateMany(defaultTenant(), ns))
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryCreateManyDuplicate(t *testing.T) {
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("CopyNotifications", mock.Anything, mock.Anything).Return(int64(0), &pgconn.PgError{Code: uniqueViolation})
+
+	err := repo.CreateMany(defaultTenant(), []entity.Notification{{ID: uuid.New(), UserID: uuid.New(), Type: entity.News, Message: "hello"}})
+	require.ErrorIs(t, err, errs.ErrNotificationExists)
+}
+
+func TestNotificationRepositoryGetByIDNotFound(t *testing.T) {
+	id := uuid.New()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("GetNotification", mock.Anything, sqlc.GetNotificationParams{TenantID: "default", ID: id}).Return(sqlc.Notification{}, pgx.ErrNoRows)
+
+	_, err := repo.GetByID(defaultTenant(), id)
+	require.ErrorIs(t, err, errs.ErrNotificationNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryClaimDue(t *testing.T) {
+	now := time.Now()
+	sendAt := now.Add(-time.Minute)
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	rows := []sqlc.Notification{
+		{ID: uuid.New(), UserID: uuid.New(), Type: string(entity.News), Message: "a", Status: string(entity.StatusSending), SendAt: &sendAt},
+		{ID: uuid.New(), UserID: uuid.New(), Type: string(entity.Status), Message: "b", Status: string(entity.StatusSending), SendAt: &sendAt},
+	}
+	mq.On("ClaimDueNotifications", mock.Anything, sqlc.ClaimDueNotificationsParams{LeasedUntil: now.Add(time.Minute), Now: now, MaxRows: 10}).Return(rows, nil)
+
+	due, err := repo.ClaimDue(defaultTenant(), now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 2)
+	require.Equal(t, rows[0].ID, due[0].ID)
+	require.Equal(t, entity.StatusSending, due[1].Status)
+	require.Equal(t, &sendAt, due[1].SendAt)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryUpdateStatus(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("UpdateNotificationStatus", mock.Anything, sqlc.UpdateNotificationStatusParams{
+		TenantID:   "default",
+		ID:         id,
+		FromStatus: string(entity.StatusScheduled),
+		ToStatus:   string(entity.StatusCanceled),
+		Reason:     "cancelled by client",
+		ChangedAt:  now,
+	}).Return(sqlc.Notification{ID: id, Status: string(entity.StatusCanceled)}, nil)
+
+	updated, err := repo.UpdateStatus(defaultTenant(), id, entity.StatusScheduled, entity.StatusCanceled, "cancelled by client", now)
+	require.NoError(t, err)
+	require.Equal(t, entity.StatusCanceled, updated.Status)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryUpdateStatusNotFound(t *testing.T) {
+	id := uuid.New()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("UpdateNotificationStatus", mock.Anything, mock.AnythingOfType("sqlc.UpdateNotificationStatusParams")).Return(sqlc.Notification{}, pgx.ErrNoRows)
+
+	_, err := repo.UpdateStatus(defaultTenant(), id, entity.StatusScheduled, entity.StatusSent, "", time.Now())
+	require.ErrorIs(t, err, errs.ErrNotificationNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryCreateDuplicate(t *testing.T) {
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	key := "retry-1"
+	mq.On("CreateNotification", mock.Anything, mock.MatchedBy(func(arg sqlc.CreateNotificationParams) bool {
+		return arg.IdempotencyKey != nil && *arg.IdempotencyKey == key
+	})).Return(sqlc.Notification{}, &pgconn.PgError{Code: uniqueViolation})
+
+	_, err := repo.Create(defaultTenant(), entity.Notification{UserID: uuid.New(), Type: entity.Status, Message: "test", IdempotencyKey: key})
+	require.ErrorIs(t, err, errs.ErrNotificationExists)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryGetByIdempotencyKey(t *testing.T) {
+	key := "retry-1"
+	id := uuid.New()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("GetNotificationByIdempotencyKey", mock.Anything, sqlc.GetNotificationByIdempotencyKeyParams{TenantID: "default", IdempotencyKey: key}).Return(sqlc.Notification{ID: id, IdempotencyKey: &key, RequestHash: "abc"}, nil)
+
+	n, err := repo.GetByIdempotencyKey(defaultTenant(), key)
+	require.NoError(t, err)
+	require.Equal(t, id, n.ID)
+	require.Equal(t, key, n.IdempotencyKey)
+	require.Equal(t, "abc", n.RequestHash)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryFindDuplicate(t *testing.T) {
+	uid := uuid.New()
+	since := time.Now().Add(-time.Minute)
+	n := entity.Notification{UserID: uid, Type: entity.News, Message: "breaking"}
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("FindDuplicateNotification", mock.Anything, sqlc.FindDuplicateNotificationParams{
+		TenantID:    "default",
+		UserID:      uid,
+		Type:        string(entity.News),
+		ContentHash: n.ContentHash(),
+		Since:       since,
+	}).Return(sqlc.Notification{}, pgx.ErrNoRows)
+
+	_, err := repo.FindDuplicate(defaultTenant(), n, since)
+	require.ErrorIs(t, err, errs.ErrNotificationNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryListHeldGroups(t *testing.T) {
+	uid := uuid.New()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	after := entity.DigestGroup{TenantID: "default", UserID: uuid.New(), Type: entity.News}
+	mq.On("ListHeldNotificationGroups", mock.Anything, sqlc.ListHeldNotificationGroupsParams{
+		AfterTenantID: "default",
+		AfterUserID:   after.UserID,
+		AfterType:     string(entity.News),
+		MaxRows:       5,
+	}).Return([]sqlc.ListHeldNotificationGroupsRow{{TenantID: "shop", UserID: uid, Type: string(entity.Marketing)}}, nil)
+
+	groups, err := repo.ListHeldGroups(defaultTenant(), after, 5)
+	require.NoError(t, err)
+	require.Equal(t, []entity.DigestGroup{{TenantID: "shop", UserID: uid, Type: entity.Marketing}}, groups)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryClaimHeld(t *testing.T) {
+	uid := uuid.New()
+	g := entity.DigestGroup{UserID: uid, Type: entity.Marketing}
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("ClaimHeldNotifications", mock.Anything, sqlc.ClaimHeldNotificationsParams{
+		TenantID: "shop",
+		UserID:   uid,
+		Type:     string(entity.Marketing),
+		Reason:   "rolled up",
+	}).Return([]sqlc.Notification{{ID: uuid.New(), UserID: uid, Type: string(entity.Marketing), Status: string(entity.StatusDigested)}}, nil)
+
+	claimed, err := repo.ClaimHeld(entity.WithTenant(defaultTenant(), "shop"), g, "rolled up")
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	require.Equal(t, entity.StatusDigested, claimed[0].Status)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryList(t *testing.T) {
+	uid := uuid.New()
+	from := time.Now().Add(-time.Hour)
+	cursor := entity.NotificationCursor{CreatedAt: time.Now(), ID: uuid.New()}
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("ListUserNotifications", mock.Anything, sqlc.ListUserNotificationsParams{
+		TenantID:       "default",
+		UserID:         uid,
+		Types:          []string{string(entity.News)},
+		Statuses:       []string{string(entity.StatusSent), string(entity.StatusHeld)},
+		CreatedFrom:    &from,
+		AfterCreatedAt: &cursor.CreatedAt,
+		AfterID:        &cursor.ID,
+		MaxRows:        11,
+	}).Return([]sqlc.Notification{{ID: uuid.New(), UserID: uid, Type: string(entity.News), Status: string(entity.StatusSent)}}, nil)
+
+	out, err := repo.List(defaultTenant(), entity.NotificationFilter{
+		UserID:   uid,
+		Types:    []entity.NotificationType{entity.News},
+		Statuses: []entity.NotificationStatus{entity.StatusSent, entity.StatusHeld},
+		From:     &from,
+		After:    &cursor,
+		Limit:    11,
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryMarkReadNotFound(t *testing.T) {
+	uid, id := uuid.New(), uuid.New()
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("MarkNotificationRead", mock.Anything, sqlc.MarkNotificationReadParams{TenantID: "default", ID: id, UserID: uid}).Return(sqlc.Notification{}, pgx.ErrNoRows)
+
+	_, err := repo.MarkRead(defaultTenant(), uid, id)
+	require.ErrorIs(t, err, errs.ErrNotificationNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryMarkManyRead(t *testing.T) {
+	uid := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("MarkNotificationsRead", mock.Anything, sqlc.MarkNotificationsReadParams{TenantID: "default", UserID: uid, Ids: ids}).Return(int64(2), nil)
+
+	count, err := repo.MarkManyRead(defaultTenant(), uid, ids)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	mq.AssertExpectations(t)
+}
+
+func TestNotificationRepositoryListSentAfter(t *testing.T) {
+	uid := uuid.New()
+	sentAt := time.Now()
+	last := entity.Notification{ID: uuid.New(), UserID: uid, SentAt: &sentAt}
+
+	mq := new(mockQueries)
+	repo := NewNotificationRepository(mq)
+
+	mq.On("ListSentNotificationsAfter", mock.Anything, sqlc.ListSentNotificationsAfterParams{
+		TenantID:    "default",
+		UserID:      uid,
+		AfterSentAt: sentAt,
+		AfterID:     last.ID,
+		MaxRows:     50,
+	}).Return([]sqlc.Notification{{ID: uuid.New(), UserID: uid, Status: string(entity.StatusSent)}}, nil)
+
+	out, err := repo.ListSentAfter(defaultTenant(), last, 50)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+
+	mq.AssertExpectations(t)
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/jackc/pgx/v5"
+)
+
+// rateLimitRulesQuerier is the subset of *sqlc.Queries used by
+// RateLimitRuleRepository.
+type rateLimitRulesQuerier interface {
+	GetRateLimitRule(ctx context.Context, arg sqlc.GetRateLimitRuleParams) (sqlc.RateLimitRule, error)
+	ListRateLimitRules(ctx context.Context, tenantID string) ([]sqlc.RateLimitRule, error)
+	UpsertRateLimitRule(ctx context.Context, arg sqlc.UpsertRateLimitRuleParams) (sqlc.RateLimitRule, error)
+	DeleteRateLimitRule(ctx context.Context, arg sqlc.DeleteRateLimitRuleParams) (int64, error)
+}
+
+// RateLimitRuleRepository scopes every call to the tenant of its context.
+type RateLimitRuleRepository struct {
+	q rateLimitRulesQuerier
+}
+
+func NewRateLimitRuleRepository(q rateLimitRulesQuerier) ports.RateLimitRuleRepository {
+	return &RateLimitRuleRepository{q: q}
+}
+
+func (r *RateLimitRuleRepository) Get(ctx context.Context, notifType entity.NotificationType) (entity.RateLimitRule, error) {
+	row, err := r.q.GetRateLimitRule(ctx, sqlc.GetRateLimitRuleParams{
+		TenantID: tenantOf(ctx),
+		Type:     string(notifType),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.RateLimitRule{}, errs.ErrRateLimitRuleNotFound
+	}
+	if err != nil {
+		return entity.RateLimitRule{}, err
+	}
+	return toRateLimitRuleEntity(row), nil
+}
+
+func (r *RateLimitRuleRepository) List(ctx context.Context) ([]entity.RateLimitRule, error) {
+	rows, err := r.q.ListRateLimitRules(ctx, tenantOf(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.RateLimitRule, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, toRateLimitRuleEntity(row))
+	}
+	return out, nil
+}
+
+func (r *RateLimitRuleRepository) Upsert(ctx context.Context, rule entity.RateLimitRule) (entity.RateLimitRule, error) {
+	row, err := r.q.UpsertRateLimitRule(ctx, sqlc.UpsertRateLimitRuleParams{
+		TenantID:        tenantOf(ctx),
+		Type:            string(rule.Type),
+		MaxCount:        int32(rule.Limit),
+		IntervalSeconds: int32(rule.Interval / time.Second),
+	})
+	if err != nil {
+		return entity.RateLimitRule{}, err
+	}
+	return toRateLimitRuleEntity(row), nil
+}
+
+func (r *RateLimitRuleRepository) Delete(ctx context.Context, notifType entity.NotificationType) error {
+	deleted, err := r.q.DeleteRateLimitRule(ctx, sqlc.DeleteRateLimitRuleParams{
+		TenantID: tenantOf(ctx),
+		Type:     string(notifType),
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errs.ErrRateLimitRuleNotFound
+	}
+	return nil
+}
+
+func toRateLimitRuleEntity(row sqlc.RateLimitRule) entity.RateLimitRule {
+	return entity.RateLimitRule{
+		Type: entity.NotificationType(row.Type),
+		RateLimit: entity.RateLimit{
+			Limit:    int(row.MaxCount),
+			Interval: time.Duration(row.IntervalSeconds) * time.Second,
+		},
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRateLimitRuleQueries struct{ mock.Mock }
+
+func (m *mockRateLimitRuleQueries) GetRateLimitRule(ctx context.Context, arg sqlc.GetRateLimitRuleParams) (sqlc.RateLimitRule, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.RateLimitRule), args.Error(1)
+}
+
+func (m *mockRateLimitRuleQueries) ListRateLimitRules(ctx context.Context, tenantID string) ([]sqlc.RateLimitRule, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]sqlc.RateLimitRule), args.Error(1)
+}
+
+func (m *mockRateLimitRuleQueries) UpsertRateLimitRule(ctx context.Context, arg sqlc.UpsertRateLimitRuleParams) (sqlc.RateLimitRule, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.RateLimitRule), args.Error(1)
+}
+
+func (m *mockRateLimitRuleQueries) DeleteRateLimitRule(ctx context.Context, arg sqlc.DeleteRateLimitRuleParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestRateLimitRuleRepositoryGetNotFound(t *testing.T) {
+	mq := new(mockRateLimitRuleQueries)
+	repo := NewRateLimitRuleRepository(mq)
+
+	mq.On("GetRateLimitRule", mock.Anything, sqlc.GetRateLimitRuleParams{TenantID: "shop", Type: "news"}).Return(sqlc.RateLimitRule{}, pgx.ErrNoRows)
+
+	_, err := repo.Get(entity.WithTenant(defaultTenant(), "shop"), entity.News)
+	require.ErrorIs(t, err, errs.ErrRateLimitRuleNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestRateLimitRuleRepositoryUpsert(t *testing.T) {
+	mq := new(mockRateLimitRuleQueries)
+	repo := NewRateLimitRuleRepository(mq)
+
+	mq.On("UpsertRateLimitRule", mock.Anything, sqlc.UpsertRateLimitRuleParams{
+		TenantID:        "shop",
+		Type:            "status",
+		MaxCount:        10,
+		IntervalSeconds: 3600,
+	}).Return(sqlc.RateLimitRule{TenantID: "shop", Type: "status", MaxCount: 10, IntervalSeconds: 3600}, nil)
+
+	rule, err := repo.Upsert(entity.WithTenant(defaultTenant(), "shop"), entity.RateLimitRule{
+		Type:      entity.Status,
+		RateLimit: entity.RateLimit{Limit: 10, Interval: time.Hour},
+	})
+	require.NoError(t, err)
+	require.Equal(t, entity.RateLimit{Limit: 10, Interval: time.Hour}, rule.RateLimit)
+
+	mq.AssertExpectations(t)
+}
+
+func TestRateLimitRuleRepositoryDeleteUnknown(t *testing.T) {
+	mq := new(mockRateLimitRuleQueries)
+	repo := NewRateLimitRuleRepository(mq)
+
+	mq.On("DeleteRateLimitRule", mock.Anything, sqlc.DeleteRateLimitRuleParams{TenantID: "default", Type: "news"}).Return(int64(0), nil)
+
+	require.ErrorIs(t, repo.Delete(defaultTenant(), entity.News), errs.ErrRateLimitRuleNotFound)
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+)
+
+// SchemaVersion is the migration in db/migrations the queries are written
+// against. It must be bumped with every new migration.
+const SchemaVersion = 21
+
+// schemaQuerier is the subset of *sqlc.Queries used by SchemaCheck.
+type schemaQuerier interface {
+	GetSchemaVersion(ctx context.Context) (sqlc.SchemaMigration, error)
+}
+
+// SchemaCheck verifies that the database is migrated to SchemaVersion.
+type SchemaCheck struct {
+	q schemaQuerier
+}
+
+func NewSchemaCheck(q schemaQuerier) *SchemaCheck {
+	return &SchemaCheck{q: q}
+}
+
+// Check fails when the database is not at SchemaVersion, either behind or
+// ahead of it, or when the last migration did not complete.
+func (c *SchemaCheck) Check(ctx context.Context) error {
+	row, err := c.q.GetSchemaVersion(entity.WithAllTenants(ctx))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("no migrations applied, want version %d", SchemaVersion)
+	}
+	if err != nil {
+		return err
+	}
+	if row.Dirty {
+		return fmt.Errorf("migration %d is dirty", row.Version)
+	}
+	if row.Version != SchemaVersion {
+		return fmt.Errorf("schema version is %d, want %d", row.Version, SchemaVersion)
+	}
+	return nil
+}
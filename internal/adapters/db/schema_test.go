@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSchemaQueries struct{ mock.Mock }
+
+func (m *mockSchemaQueries) GetSchemaVersion(ctx context.Context) (sqlc.SchemaMigration, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(sqlc.SchemaMigration), args.Error(1)
+}
+
+func TestSchemaVersionIsLatestMigration(t *testing.T) {
+	entries, err := os.ReadDir("../../../db/migrations")
+	require.NoError(t, err)
+
+	var latest int64
+	for _, e := range entries {
+		version, _, ok := strings.Cut(e.Name(), "_")
+		require.True(t, ok, e.Name())
+		v, err := strconv.ParseInt(version, 10, 64)
+		require.NoError(t, err, e.Name())
+		latest = max(latest, v)
+	}
+	require.Equal(t, latest, int64(SchemaVersion), "bump SchemaVersion along with the migrations")
+}
+
+func TestSchemaCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		row     sqlc.SchemaMigration
+		err     error
+		wantErr string
+	}{
+		{name: "current", row: sqlc.SchemaMigration{Version: SchemaVersion}},
+		{name: "behind", row: sqlc.SchemaMigration{Version: SchemaVersion - 1}, wantErr: fmt.Sprintf("schema version is %d, want %d", SchemaVersion-1, SchemaVersion)},
+		{name: "ahead", row: sqlc.SchemaMigration{Version: SchemaVersion + 1}, wantErr: fmt.Sprintf("schema version is %d, want %d", SchemaVersion+1, SchemaVersion)},
+		{name: "dirty", row: sqlc.SchemaMigration{Version: SchemaVersion, Dirty: true}, wantErr: fmt.Sprintf("migration %d is dirty", SchemaVersion)},
+		{name: "not migrated", err: pgx.ErrNoRows, wantErr: fmt.Sprintf("no migrations applied, want version %d", SchemaVersion)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mq := new(mockSchemaQueries)
+			mq.On("GetSchemaVersion", mock.Anything).Return(tt.row, tt.err)
+
+			err := NewSchemaCheck(mq).Check(context.Background())
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
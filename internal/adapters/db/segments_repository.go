@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// segmentsQuerier is the subset of *sqlc.Queries used by SegmentRepository.
+type segmentsQuerier interface {
+	CreateSegment(ctx context.Context, arg sqlc.CreateSegmentParams) (sqlc.CreateSegmentRow, error)
+	GetSegment(ctx context.Context, arg sqlc.GetSegmentParams) (sqlc.Segment, error)
+	CountSegmentMembers(ctx context.Context, arg sqlc.CountSegmentMembersParams) (int64, error)
+	ListSegmentMembers(ctx context.Context, arg sqlc.ListSegmentMembersParams) ([]uuid.UUID, error)
+	CountUsersMatching(ctx context.Context, arg sqlc.CountUsersMatchingParams) (int64, error)
+	ListUsersMatching(ctx context.Context, arg sqlc.ListUsersMatchingParams) ([]uuid.UUID, error)
+}
+
+type SegmentRepository struct {
+	q segmentsQuerier
+}
+
+func NewSegmentRepository(q segmentsQuerier) ports.SegmentRepository {
+	return &SegmentRepository{q: q}
+}
+
+func (r *SegmentRepository) Create(ctx context.Context, s entity.Segment) (entity.Segment, error) {
+	rawAttributes, err := marshalMetadata(s.Attributes)
+	if err != nil {
+		return entity.Segment{}, err
+	}
+	userIDs := s.UserIDs
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+
+	row, err := r.q.CreateSegment(ctx, sqlc.CreateSegmentParams{
+		ID:         s.ID,
+		TenantID:   tenantOf(ctx),
+		Name:       s.Name,
+		Attributes: rawAttributes,
+		Static:     s.Static,
+		Tags:       nonNilTags(s.Tags),
+		UserIDs:    userIDs,
+	})
+	if err != nil {
+		return entity.Segment{}, err
+	}
+
+	saved, err := toSegmentEntity(sqlc.Segment(row))
+	if err != nil {
+		return entity.Segment{}, err
+	}
+	saved.UserIDs = s.UserIDs
+	return saved, nil
+}
+
+func (r *SegmentRepository) Get(ctx context.Context, id uuid.UUID) (entity.Segment, error) {
+	row, err := r.q.GetSegment(ctx, sqlc.GetSegmentParams{TenantID: tenantOf(ctx), ID: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Segment{}, errs.ErrSegmentNotFound
+	}
+	if err != nil {
+		return entity.Segment{}, err
+	}
+	return toSegmentEntity(row)
+}
+
+func (r *SegmentRepository) Count(ctx context.Context, s entity.Segment) (int, error) {
+	if s.Static {
+		count, err := r.q.CountSegmentMembers(ctx, sqlc.CountSegmentMembersParams{TenantID: tenantOf(ctx), SegmentID: s.ID})
+		return int(count), err
+	}
+
+	rawAttributes, err := marshalMetadata(s.Attributes)
+	if err != nil {
+		return 0, err
+	}
+	count, err := r.q.CountUsersMatching(ctx, sqlc.CountUsersMatchingParams{
+		TenantID:   tenantOf(ctx),
+		Attributes: rawAttributes,
+		Tags:       nonNilTags(s.Tags),
+	})
+	return int(count), err
+}
+
+func (r *SegmentRepository) ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	if s.Static {
+		return r.q.ListSegmentMembers(ctx, sqlc.ListSegmentMembersParams{
+			TenantID:  tenantOf(ctx),
+			SegmentID: s.ID,
+			After:     after,
+			Limit:     int32(limit),
+		})
+	}
+
+	rawAttributes, err := marshalMetadata(s.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	return r.q.ListUsersMatching(ctx, sqlc.ListUsersMatchingParams{
+		TenantID:   tenantOf(ctx),
+		Attributes: rawAttributes,
+		Tags:       nonNilTags(s.Tags),
+		After:      after,
+		Limit:      int32(limit),
+	})
+}
+
+func toSegmentEntity(row sqlc.Segment) (entity.Segment, error) {
+	var attributes map[string]string
+	if err := json.Unmarshal(row.Attributes, &attributes); err != nil {
+		return entity.Segment{}, err
+	}
+	return entity.Segment{
+		ID:         row.ID,
+		Name:       row.Name,
+		Static:     row.Static,
+		Attributes: attributes,
+		Tags:       row.Tags,
+		CreatedAt:  row.CreatedAt,
+	}, nil
+}
+
+// nonNilTags passes an empty array rather than NULL, which would match
+// nothing in array containment.
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockSegmentQueries struct{ mock.Mock }
+
+func (m *mockSegmentQueries) CreateSegment(ctx context.Context, arg sqlc.CreateSegmentParams) (sqlc.CreateSegmentRow, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.CreateSegmentRow), args.Error(1)
+}
+
+func (m *mockSegmentQueries) GetSegment(ctx context.Context, arg sqlc.GetSegmentParams) (sqlc.Segment, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Segment), args.Error(1)
+}
+
+func (m *mockSegmentQueries) CountSegmentMembers(ctx context.Context, arg sqlc.CountSegmentMembersParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockSegmentQueries) ListSegmentMembers(ctx context.Context, arg sqlc.ListSegmentMembersParams) ([]uuid.UUID, error) {
+	args := m.Called(ctx, arg)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func (m *mockSegmentQueries) CountUsersMatching(ctx context.Context, arg sqlc.CountUsersMatchingParams) (int64, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockSegmentQueries) ListUsersMatching(ctx context.Context, arg sqlc.ListUsersMatchingParams) ([]uuid.UUID, error) {
+	args := m.Called(ctx, arg)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func TestSegmentRepositoryCreateStatic(t *testing.T) {
+	mq := new(mockSegmentQueries)
+	repo := NewSegmentRepository(mq)
+
+	s := entity.Segment{ID: uuid.New(), Name: "pilot", Static: true, UserIDs: []uuid.UUID{uuid.New(), uuid.New()}}
+	mq.On("CreateSegment", mock.Anything, sqlc.CreateSegmentParams{
+		ID:         s.ID,
+		TenantID:   "default",
+		Name:       "pilot",
+		Attributes: []byte("{}"),
+		Static:     true,
+		Tags:       []string{},
+		UserIDs:    s.UserIDs,
+	}).Return(sqlc.CreateSegmentRow{ID: s.ID, Name: "pilot", Attributes: []byte("{}"), Static: true}, nil)
+
+	saved, err := repo.Create(defaultTenant(), s)
+	require.NoError(t, err)
+	require.True(t, saved.Static)
+	require.Equal(t, s.UserIDs, saved.UserIDs)
+
+	mq.AssertExpectations(t)
+}
+
+func TestSegmentRepositoryGetNotFound(t *testing.T) {
+	mq := new(mockSegmentQueries)
+	repo := NewSegmentRepository(mq)
+
+	id := uuid.New()
+	mq.On("GetSegment", mock.Anything, sqlc.GetSegmentParams{TenantID: "default", ID: id}).Return(sqlc.Segment{}, pgx.ErrNoRows)
+
+	_, err := repo.Get(defaultTenant(), id)
+	require.ErrorIs(t, err, errs.ErrSegmentNotFound)
+}
+
+func TestSegmentRepositoryListMembers(t *testing.T) {
+	mq := new(mockSegmentQueries)
+	repo := NewSegmentRepository(mq)
+
+	after, next := uuid.New(), uuid.New()
+	static := entity.Segment{ID: uuid.New(), Static: true}
+	mq.On("ListSegmentMembers", mock.Anything, sqlc.ListSegmentMembersParams{TenantID: "shop", SegmentID: static.ID, After: after, Limit: 50}).Return([]uuid.UUID{next}, nil)
+
+	predicate := entity.Segment{ID: uuid.New(), Attributes: map[string]string{"plan": "pro"}, Tags: []string{"beta"}}
+	mq.On("ListUsersMatching", mock.Anything, sqlc.ListUsersMatchingParams{
+		TenantID:   "shop",
+		Attributes: []byte(`{"plan":"pro"}`),
+		Tags:       []string{"beta"},
+		After:      after,
+		Limit:      50,
+	}).Return([]uuid.UUID{next}, nil)
+
+	ctx := entity.WithTenant(defaultTenant(), "shop")
+	ids, err := repo.ListMembers(ctx, static, after, 50)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{next}, ids)
+
+	ids, err = repo.ListMembers(ctx, predicate, after, 50)
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{next}, ids)
+
+	mq.AssertExpectations(t)
+}
+
+func TestSegmentRepositoryCountMatchingWithoutTags(t *testing.T) {
+	mq := new(mockSegmentQueries)
+	repo := NewSegmentRepository(mq)
+
+	mq.On("CountUsersMatching", mock.Anything, sqlc.CountUsersMatchingParams{
+		TenantID:   "default",
+		Attributes: []byte(`{"plan":"pro"}`),
+		Tags:       []string{},
+	}).Return(int64(12), nil)
+
+	count, err := repo.Count(defaultTenant(), entity.Segment{Attributes: map[string]string{"plan": "pro"}})
+	require.NoError(t, err)
+	require.Equal(t, 12, count)
+}
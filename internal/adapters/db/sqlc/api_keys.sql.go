@@ -0,0 +1,192 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: api_keys.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (id, tenant_id, name, prefix, key_hash, scopes)
+VALUES ($1, $2, $3, $4, $5, $6::text[])
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant_id
+`
+
+type CreateAPIKeyParams struct {
+	ID       uuid.UUID
+	TenantID string
+	Name     string
+	Prefix   string
+	KeyHash  string
+	Scopes   []string
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (APIKey, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.ID,
+		arg.TenantID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+	)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant_id
+FROM api_keys
+WHERE key_hash = $1
+  AND revoked_at IS NULL
+`
+
+// Not scoped to a tenant: the key is what tells the tenant.
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (APIKey, error) {
+	row := q.db.QueryRow(ctx, getActiveAPIKeyByHash, keyHash)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant_id
+FROM api_keys
+WHERE tenant_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context, tenantID string) ([]APIKey, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []APIKey
+	for rows.Next() {
+		var i APIKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = NOW()
+WHERE tenant_id = $1
+  AND id = $2
+  AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, arg.TenantID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rotateAPIKey = `-- name: RotateAPIKey :one
+UPDATE api_keys
+SET prefix = $3,
+    key_hash = $4
+WHERE tenant_id = $1
+  AND id = $2
+  AND revoked_at IS NULL
+RETURNING id, name, prefix, key_hash, scopes, created_at, last_used_at, revoked_at, tenant_id
+`
+
+type RotateAPIKeyParams struct {
+	TenantID string
+	ID       uuid.UUID
+	Prefix   string
+	KeyHash  string
+}
+
+func (q *Queries) RotateAPIKey(ctx context.Context, arg RotateAPIKeyParams) (APIKey, error) {
+	row := q.db.QueryRow(ctx, rotateAPIKey,
+		arg.TenantID,
+		arg.ID,
+		arg.Prefix,
+		arg.KeyHash,
+	)
+	var i APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = $3::timestamp
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type TouchAPIKeyParams struct {
+	TenantID   string
+	ID         uuid.UUID
+	LastUsedAt time.Time
+}
+
+func (q *Queries) TouchAPIKey(ctx context.Context, arg TouchAPIKeyParams) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, arg.TenantID, arg.ID, arg.LastUsedAt)
+	return err
+}
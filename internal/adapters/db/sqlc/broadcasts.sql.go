@@ -0,0 +1,229 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: broadcasts.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimBroadcast = `-- name: ClaimBroadcast :one
+UPDATE broadcasts
+SET status = 'running',
+    leased_until = $1::timestamp,
+    updated_at = $2::timestamp
+WHERE id = (
+  SELECT id
+  FROM broadcasts
+  WHERE status IN ('pending', 'running')
+    AND (leased_until IS NULL OR leased_until < $2::timestamp)
+  ORDER BY updated_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, segment_id, type, title, message, action_url, metadata, status, last_user_id, total, processed, sent, rate_limited, skipped, failed, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type ClaimBroadcastParams struct {
+	LeasedUntil time.Time
+	Now         time.Time
+}
+
+// Workers serve every tenant, so the claim is not scoped to one.
+func (q *Queries) ClaimBroadcast(ctx context.Context, arg ClaimBroadcastParams) (Broadcast, error) {
+	row := q.db.QueryRow(ctx, claimBroadcast, arg.LeasedUntil, arg.Now)
+	var i Broadcast
+	err := row.Scan(
+		&i.ID,
+		&i.SegmentID,
+		&i.Type,
+		&i.Title,
+		&i.Message,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Skipped,
+		&i.Failed,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const createBroadcast = `-- name: CreateBroadcast :one
+INSERT INTO broadcasts (id, tenant_id, segment_id, type, title, message, action_url, metadata, total)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, segment_id, type, title, message, action_url, metadata, status, last_user_id, total, processed, sent, rate_limited, skipped, failed, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type CreateBroadcastParams struct {
+	ID        uuid.UUID
+	TenantID  string
+	SegmentID uuid.UUID
+	Type      string
+	Title     string
+	Message   string
+	ActionURL string
+	Metadata  []byte
+	Total     int32
+}
+
+func (q *Queries) CreateBroadcast(ctx context.Context, arg CreateBroadcastParams) (Broadcast, error) {
+	row := q.db.QueryRow(ctx, createBroadcast,
+		arg.ID,
+		arg.TenantID,
+		arg.SegmentID,
+		arg.Type,
+		arg.Title,
+		arg.Message,
+		arg.ActionURL,
+		arg.Metadata,
+		arg.Total,
+	)
+	var i Broadcast
+	err := row.Scan(
+		&i.ID,
+		&i.SegmentID,
+		&i.Type,
+		&i.Title,
+		&i.Message,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Skipped,
+		&i.Failed,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getBroadcast = `-- name: GetBroadcast :one
+SELECT id, segment_id, type, title, message, action_url, metadata, status, last_user_id, total, processed, sent, rate_limited, skipped, failed, leased_until, created_at, updated_at, completed_at, tenant_id
+FROM broadcasts
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type GetBroadcastParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetBroadcast(ctx context.Context, arg GetBroadcastParams) (Broadcast, error) {
+	row := q.db.QueryRow(ctx, getBroadcast, arg.TenantID, arg.ID)
+	var i Broadcast
+	err := row.Scan(
+		&i.ID,
+		&i.SegmentID,
+		&i.Type,
+		&i.Title,
+		&i.Message,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Skipped,
+		&i.Failed,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const updateBroadcastProgress = `-- name: UpdateBroadcastProgress :one
+UPDATE broadcasts
+SET status = $2,
+    last_user_id = $3,
+    processed = $4,
+    sent = $5,
+    rate_limited = $6,
+    skipped = $7,
+    failed = $8,
+    completed_at = $9,
+    leased_until = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND leased_until = $10::timestamp
+RETURNING id, segment_id, type, title, message, action_url, metadata, status, last_user_id, total, processed, sent, rate_limited, skipped, failed, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type UpdateBroadcastProgressParams struct {
+	ID          uuid.UUID
+	Status      string
+	LastUserID  *uuid.UUID
+	Processed   int32
+	Sent        int32
+	RateLimited int32
+	Skipped     int32
+	Failed      int32
+	CompletedAt *time.Time
+	LeasedUntil time.Time
+}
+
+func (q *Queries) UpdateBroadcastProgress(ctx context.Context, arg UpdateBroadcastProgressParams) (Broadcast, error) {
+	row := q.db.QueryRow(ctx, updateBroadcastProgress,
+		arg.ID,
+		arg.Status,
+		arg.LastUserID,
+		arg.Processed,
+		arg.Sent,
+		arg.RateLimited,
+		arg.Skipped,
+		arg.Failed,
+		arg.CompletedAt,
+		arg.LeasedUntil,
+	)
+	var i Broadcast
+	err := row.Scan(
+		&i.ID,
+		&i.SegmentID,
+		&i.Type,
+		&i.Title,
+		&i.Message,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Skipped,
+		&i.Failed,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
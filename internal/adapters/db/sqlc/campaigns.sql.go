@@ -0,0 +1,300 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: campaigns.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const claimCampaign = `-- name: ClaimCampaign :one
+UPDATE campaigns
+SET status = 'running',
+    leased_until = $1::timestamp,
+    updated_at = $2::timestamp
+WHERE id = (
+  SELECT id
+  FROM campaigns
+  WHERE status IN ('scheduled', 'running')
+    AND next_run_at <= $2::timestamp
+    AND (leased_until IS NULL OR leased_until < $2::timestamp)
+  ORDER BY next_run_at
+  LIMIT 1
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, name, segment_id, template_id, template_version, data, start_at, rate_per_minute, status, last_user_id, total, processed, sent, rate_limited, suppressed, failed, next_run_at, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type ClaimCampaignParams struct {
+	LeasedUntil time.Time
+	Now         time.Time
+}
+
+// Workers serve every tenant, so the claim is not scoped to one.
+func (q *Queries) ClaimCampaign(ctx context.Context, arg ClaimCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, claimCampaign, arg.LeasedUntil, arg.Now)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SegmentID,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Data,
+		&i.StartAt,
+		&i.RatePerMinute,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Suppressed,
+		&i.Failed,
+		&i.NextRunAt,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const createCampaign = `-- name: CreateCampaign :one
+INSERT INTO campaigns (
+  id, tenant_id, name, segment_id, template_id, template_version, data,
+  start_at, rate_per_minute, total, next_run_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $8)
+RETURNING id, name, segment_id, template_id, template_version, data, start_at, rate_per_minute, status, last_user_id, total, processed, sent, rate_limited, suppressed, failed, next_run_at, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type CreateCampaignParams struct {
+	ID              uuid.UUID
+	TenantID        string
+	Name            string
+	SegmentID       uuid.UUID
+	TemplateID      uuid.UUID
+	TemplateVersion int32
+	Data            []byte
+	StartAt         time.Time
+	RatePerMinute   int32
+	Total           int32
+}
+
+func (q *Queries) CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, createCampaign,
+		arg.ID,
+		arg.TenantID,
+		arg.Name,
+		arg.SegmentID,
+		arg.TemplateID,
+		arg.TemplateVersion,
+		arg.Data,
+		arg.StartAt,
+		arg.RatePerMinute,
+		arg.Total,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SegmentID,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Data,
+		&i.StartAt,
+		&i.RatePerMinute,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Suppressed,
+		&i.Failed,
+		&i.NextRunAt,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getCampaign = `-- name: GetCampaign :one
+SELECT id, name, segment_id, template_id, template_version, data, start_at, rate_per_minute, status, last_user_id, total, processed, sent, rate_limited, suppressed, failed, next_run_at, leased_until, created_at, updated_at, completed_at, tenant_id
+FROM campaigns
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type GetCampaignParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetCampaign(ctx context.Context, arg GetCampaignParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, getCampaign, arg.TenantID, arg.ID)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SegmentID,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Data,
+		&i.StartAt,
+		&i.RatePerMinute,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Suppressed,
+		&i.Failed,
+		&i.NextRunAt,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const updateCampaignProgress = `-- name: UpdateCampaignProgress :one
+UPDATE campaigns
+SET status = CASE WHEN status = 'running' THEN $9::text ELSE status END,
+    completed_at = CASE WHEN status = 'running' THEN $10::timestamp ELSE completed_at END,
+    last_user_id = $2,
+    processed = $3,
+    sent = $4,
+    rate_limited = $5,
+    suppressed = $6,
+    failed = $7,
+    next_run_at = $8,
+    leased_until = NULL,
+    updated_at = NOW()
+WHERE id = $1
+  AND leased_until = $11::timestamp
+RETURNING id, name, segment_id, template_id, template_version, data, start_at, rate_per_minute, status, last_user_id, total, processed, sent, rate_limited, suppressed, failed, next_run_at, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type UpdateCampaignProgressParams struct {
+	ID          uuid.UUID
+	LastUserID  *uuid.UUID
+	Processed   int32
+	Sent        int32
+	RateLimited int32
+	Suppressed  int32
+	Failed      int32
+	NextRunAt   time.Time
+	Status      string
+	CompletedAt *time.Time
+	LeasedUntil time.Time
+}
+
+// A campaign paused or cancelled while a batch was in flight keeps that
+// status; only a running campaign is moved to the status of the batch.
+func (q *Queries) UpdateCampaignProgress(ctx context.Context, arg UpdateCampaignProgressParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, updateCampaignProgress,
+		arg.ID,
+		arg.LastUserID,
+		arg.Processed,
+		arg.Sent,
+		arg.RateLimited,
+		arg.Suppressed,
+		arg.Failed,
+		arg.NextRunAt,
+		arg.Status,
+		arg.CompletedAt,
+		arg.LeasedUntil,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SegmentID,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Data,
+		&i.StartAt,
+		&i.RatePerMinute,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Suppressed,
+		&i.Failed,
+		&i.NextRunAt,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const updateCampaignStatus = `-- name: UpdateCampaignStatus :one
+UPDATE campaigns
+SET status = $3,
+    updated_at = NOW()
+WHERE tenant_id = $1
+  AND id = $2
+  AND status = ANY($4::text[])
+RETURNING id, name, segment_id, template_id, template_version, data, start_at, rate_per_minute, status, last_user_id, total, processed, sent, rate_limited, suppressed, failed, next_run_at, leased_until, created_at, updated_at, completed_at, tenant_id
+`
+
+type UpdateCampaignStatusParams struct {
+	TenantID     string
+	ID           uuid.UUID
+	Status       string
+	FromStatuses []string
+}
+
+func (q *Queries) UpdateCampaignStatus(ctx context.Context, arg UpdateCampaignStatusParams) (Campaign, error) {
+	row := q.db.QueryRow(ctx, updateCampaignStatus,
+		arg.TenantID,
+		arg.ID,
+		arg.Status,
+		arg.FromStatuses,
+	)
+	var i Campaign
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.SegmentID,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Data,
+		&i.StartAt,
+		&i.RatePerMinute,
+		&i.Status,
+		&i.LastUserID,
+		&i.Total,
+		&i.Processed,
+		&i.Sent,
+		&i.RateLimited,
+		&i.Suppressed,
+		&i.Failed,
+		&i.NextRunAt,
+		&i.LeasedUntil,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompletedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
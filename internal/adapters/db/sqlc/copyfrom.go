@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: copyfrom.go
+
+package sqlc
+
+import (
+	"context"
+)
+
+// iteratorForCopyNotifications implements pgx.CopyFromSource.
+type iteratorForCopyNotifications struct {
+	rows                 []CopyNotificationsParams
+	skippedFirstNextCall bool
+}
+
+func (r *iteratorForCopyNotifications) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	if !r.skippedFirstNextCall {
+		r.skippedFirstNextCall = true
+		return true
+	}
+	r.rows = r.rows[1:]
+	return len(r.rows) > 0
+}
+
+func (r iteratorForCopyNotifications) Values() ([]interface{}, error) {
+	return []interface{}{
+		r.rows[0].ID,
+		r.rows[0].TenantID,
+		r.rows[0].UserID,
+		r.rows[0].Type,
+		r.rows[0].Title,
+		r.rows[0].Message,
+		r.rows[0].ActionURL,
+		r.rows[0].Metadata,
+		r.rows[0].Status,
+		r.rows[0].StatusReason,
+		r.rows[0].SentAt,
+		r.rows[0].ExpiresAt,
+		r.rows[0].ContentHash,
+		r.rows[0].CreatedAt,
+	}, nil
+}
+
+func (r iteratorForCopyNotifications) Err() error {
+	return nil
+}
+
+func (q *Queries) CopyNotifications(ctx context.Context, arg []CopyNotificationsParams) (int64, error) {
+	return q.db.CopyFrom(ctx, []string{"notifications"}, []string{"id", "tenant_id", "user_id", "type", "title", "message", "action_url", "metadata", "status", "status_reason", "sent_at", "expires_at", "content_hash", "created_at"}, &iteratorForCopyNotifications{rows: arg})
+}
@@ -10,15 +10,144 @@ import (
 	"github.com/google/uuid"
 )
 
+type APIKey struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	TenantID   string
+}
+
+type Broadcast struct {
+	ID          uuid.UUID
+	SegmentID   uuid.UUID
+	Type        string
+	Title       string
+	Message     string
+	ActionURL   string
+	Metadata    []byte
+	Status      string
+	LastUserID  *uuid.UUID
+	Total       int32
+	Processed   int32
+	Sent        int32
+	RateLimited int32
+	Skipped     int32
+	Failed      int32
+	LeasedUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+	TenantID    string
+}
+
+type Campaign struct {
+	ID              uuid.UUID
+	Name            string
+	SegmentID       uuid.UUID
+	TemplateID      uuid.UUID
+	TemplateVersion int32
+	Data            []byte
+	StartAt         time.Time
+	RatePerMinute   int32
+	Status          string
+	LastUserID      *uuid.UUID
+	Total           int32
+	Processed       int32
+	Sent            int32
+	RateLimited     int32
+	Suppressed      int32
+	Failed          int32
+	NextRunAt       time.Time
+	LeasedUntil     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CompletedAt     *time.Time
+	TenantID        string
+}
+
 type Notification struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Type      string
-	Message   string
-	CreatedAt time.Time
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	Type            string
+	Message         string
+	CreatedAt       time.Time
+	Status          string
+	SendAt          *time.Time
+	SentAt          *time.Time
+	ExpiresAt       *time.Time
+	StatusReason    string
+	IdempotencyKey  *string
+	RequestHash     string
+	ContentHash     string
+	TemplateID      *uuid.UUID
+	TemplateVersion int32
+	Locale          *string
+	Title           string
+	ActionURL       string
+	Metadata        []byte
+	ReadAt          *time.Time
+	ArchivedAt      *time.Time
+	TenantID        string
+	LeasedUntil     *time.Time
+}
+
+type RateLimitRule struct {
+	TenantID        string
+	Type            string
+	MaxCount        int32
+	IntervalSeconds int32
+	UpdatedAt       time.Time
 }
 
 type SchemaMigration struct {
 	Version int64
 	Dirty   bool
 }
+
+type Segment struct {
+	ID         uuid.UUID
+	Name       string
+	Attributes []byte
+	Tags       []string
+	Static     bool
+	CreatedAt  time.Time
+	TenantID   string
+}
+
+type SegmentMember struct {
+	SegmentID uuid.UUID
+	UserID    uuid.UUID
+	TenantID  string
+}
+
+type Template struct {
+	ID        uuid.UUID
+	Version   int32
+	Name      string
+	Body      string
+	Variables []byte
+	CreatedAt time.Time
+	Locale    string
+	Variants  []byte
+	TenantID  string
+}
+
+type UserAttribute struct {
+	UserID     uuid.UUID
+	Attributes []byte
+	Tags       []string
+	UpdatedAt  time.Time
+	TenantID   string
+}
+
+type UserPreference struct {
+	UserID    uuid.UUID
+	Locale    string
+	UpdatedAt time.Time
+	TenantID  string
+}
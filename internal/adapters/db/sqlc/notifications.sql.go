@@ -12,41 +12,880 @@ import (
 	"github.com/google/uuid"
 )
 
+const claimDueNotifications = `-- name: ClaimDueNotifications :many
+UPDATE notifications
+SET status = 'sending',
+    leased_until = $1::timestamp
+WHERE id IN (
+  SELECT id
+  FROM notifications
+  WHERE (status = 'scheduled' AND send_at <= $2::timestamp)
+     OR (status = 'sending' AND leased_until < $2::timestamp)
+  ORDER BY send_at
+  LIMIT $3
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+`
+
+type ClaimDueNotificationsParams struct {
+	LeasedUntil time.Time
+	Now         time.Time
+	MaxRows     int32
+}
+
+// Workers serve every tenant, so the claim is not scoped to one. Rows a
+// worker stopped sending before settling come due again once their lease
+// has run out.
+func (q *Queries) ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, claimDueNotifications, arg.LeasedUntil, arg.Now, arg.MaxRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Message,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SendAt,
+			&i.SentAt,
+			&i.ExpiresAt,
+			&i.StatusReason,
+			&i.IdempotencyKey,
+			&i.RequestHash,
+			&i.ContentHash,
+			&i.TemplateID,
+			&i.TemplateVersion,
+			&i.Locale,
+			&i.Title,
+			&i.ActionURL,
+			&i.Metadata,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.TenantID,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimHeldNotifications = `-- name: ClaimHeldNotifications :many
+UPDATE notifications
+SET status = 'digested',
+    status_reason = $1
+WHERE tenant_id = $2
+  AND user_id = $3
+  AND type = $4
+  AND status = 'held'
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+`
+
+type ClaimHeldNotificationsParams struct {
+	Reason   string
+	TenantID string
+	UserID   uuid.UUID
+	Type     string
+}
+
+func (q *Queries) ClaimHeldNotifications(ctx context.Context, arg ClaimHeldNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, claimHeldNotifications,
+		arg.Reason,
+		arg.TenantID,
+		arg.UserID,
+		arg.Type,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Message,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SendAt,
+			&i.SentAt,
+			&i.ExpiresAt,
+			&i.StatusReason,
+			&i.IdempotencyKey,
+			&i.RequestHash,
+			&i.ContentHash,
+			&i.TemplateID,
+			&i.TemplateVersion,
+			&i.Locale,
+			&i.Title,
+			&i.ActionURL,
+			&i.Metadata,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.TenantID,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+type CopyNotificationsParams struct {
+	ID           uuid.UUID
+	TenantID     string
+	UserID       uuid.UUID
+	Type         string
+	Title        string
+	Message      string
+	ActionURL    string
+	Metadata     []byte
+	Status       string
+	StatusReason string
+	SentAt       *time.Time
+	ExpiresAt    *time.Time
+	ContentHash  string
+	CreatedAt    time.Time
+}
+
 const countNotificationsInTimeWindow = `-- name: CountNotificationsInTimeWindow :one
 SELECT COUNT(*) as total
 FROM notifications
-WHERE user_id = $1
-  AND type = $2
-  AND created_at >= $3
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND type = $3
+  AND status = 'sent'
+  AND sent_at >= $4::timestamp
 `
 
 type CountNotificationsInTimeWindowParams struct {
-	UserID    uuid.UUID
-	Type      string
-	CreatedAt time.Time
+	TenantID string
+	UserID   uuid.UUID
+	Type     string
+	Since    time.Time
 }
 
 func (q *Queries) CountNotificationsInTimeWindow(ctx context.Context, arg CountNotificationsInTimeWindowParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countNotificationsInTimeWindow, arg.UserID, arg.Type, arg.CreatedAt)
+	row := q.db.QueryRow(ctx, countNotificationsInTimeWindow,
+		arg.TenantID,
+		arg.UserID,
+		arg.Type,
+		arg.Since,
+	)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countNotificationsInTimeWindowByUser = `-- name: CountNotificationsInTimeWindowByUser :many
+SELECT user_id, COUNT(*) as total
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = ANY($2::uuid[])
+  AND type = $3
+  AND status = 'sent'
+  AND sent_at >= $4::timestamp
+GROUP BY user_id
+`
+
+type CountNotificationsInTimeWindowByUserParams struct {
+	TenantID string
+	UserIDs  []uuid.UUID
+	Type     string
+	Since    time.Time
+}
+
+type CountNotificationsInTimeWindowByUserRow struct {
+	UserID uuid.UUID
+	Total  int64
+}
+
+func (q *Queries) CountNotificationsInTimeWindowByUser(ctx context.Context, arg CountNotificationsInTimeWindowByUserParams) ([]CountNotificationsInTimeWindowByUserRow, error) {
+	rows, err := q.db.Query(ctx, countNotificationsInTimeWindowByUser,
+		arg.TenantID,
+		arg.UserIDs,
+		arg.Type,
+		arg.Since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountNotificationsInTimeWindowByUserRow
+	for rows.Next() {
+		var i CountNotificationsInTimeWindowByUserRow
+		if err := rows.Scan(&i.UserID, &i.Total); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countUnreadNotifications = `-- name: CountUnreadNotifications :one
+SELECT COUNT(*) as total
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND status = 'sent'
+  AND read_at IS NULL
+  AND archived_at IS NULL
+`
+
+type CountUnreadNotificationsParams struct {
+	TenantID string
+	UserID   uuid.UUID
+}
+
+func (q *Queries) CountUnreadNotifications(ctx context.Context, arg CountUnreadNotificationsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnreadNotifications, arg.TenantID, arg.UserID)
 	var total int64
 	err := row.Scan(&total)
 	return total, err
 }
 
 const createNotification = `-- name: CreateNotification :one
-INSERT INTO notifications (user_id, type, message)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, type, message, created_at
+INSERT INTO notifications (id, tenant_id, user_id, type, message, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
 `
 
 type CreateNotificationParams struct {
-	UserID  uuid.UUID
-	Type    string
-	Message string
+	ID              uuid.UUID
+	TenantID        string
+	UserID          uuid.UUID
+	Type            string
+	Message         string
+	Status          string
+	SendAt          *time.Time
+	SentAt          *time.Time
+	ExpiresAt       *time.Time
+	StatusReason    string
+	IdempotencyKey  *string
+	RequestHash     string
+	ContentHash     string
+	TemplateID      *uuid.UUID
+	TemplateVersion int32
+	Locale          *string
+	Title           string
+	ActionURL       string
+	Metadata        []byte
 }
 
 func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error) {
-	row := q.db.QueryRow(ctx, createNotification, arg.UserID, arg.Type, arg.Message)
+	row := q.db.QueryRow(ctx, createNotification,
+		arg.ID,
+		arg.TenantID,
+		arg.UserID,
+		arg.Type,
+		arg.Message,
+		arg.Status,
+		arg.SendAt,
+		arg.SentAt,
+		arg.ExpiresAt,
+		arg.StatusReason,
+		arg.IdempotencyKey,
+		arg.RequestHash,
+		arg.ContentHash,
+		arg.TemplateID,
+		arg.TemplateVersion,
+		arg.Locale,
+		arg.Title,
+		arg.ActionURL,
+		arg.Metadata,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const findDuplicateNotification = `-- name: FindDuplicateNotification :one
+SELECT id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND type = $3
+  AND content_hash = $4
+  AND status IN ('scheduled', 'sent')
+  AND created_at >= $5::timestamp
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type FindDuplicateNotificationParams struct {
+	TenantID    string
+	UserID      uuid.UUID
+	Type        string
+	ContentHash string
+	Since       time.Time
+}
+
+func (q *Queries) FindDuplicateNotification(ctx context.Context, arg FindDuplicateNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, findDuplicateNotification,
+		arg.TenantID,
+		arg.UserID,
+		arg.Type,
+		arg.ContentHash,
+		arg.Since,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const getNotification = `-- name: GetNotification :one
+SELECT id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+FROM notifications
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type GetNotificationParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetNotification(ctx context.Context, arg GetNotificationParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, getNotification, arg.TenantID, arg.ID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const getNotificationByIdempotencyKey = `-- name: GetNotificationByIdempotencyKey :one
+SELECT id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+FROM notifications
+WHERE tenant_id = $1
+  AND idempotency_key = $2::text
+`
+
+type GetNotificationByIdempotencyKeyParams struct {
+	TenantID       string
+	IdempotencyKey string
+}
+
+func (q *Queries) GetNotificationByIdempotencyKey(ctx context.Context, arg GetNotificationByIdempotencyKeyParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, getNotificationByIdempotencyKey, arg.TenantID, arg.IdempotencyKey)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const listHeldNotificationGroups = `-- name: ListHeldNotificationGroups :many
+SELECT tenant_id, user_id, type
+FROM notifications
+WHERE status = 'held'
+  AND (tenant_id, user_id, type) > ($1::text, $2::uuid, $3::text)
+GROUP BY tenant_id, user_id, type
+ORDER BY tenant_id, user_id, type
+LIMIT $4
+`
+
+type ListHeldNotificationGroupsParams struct {
+	AfterTenantID string
+	AfterUserID   uuid.UUID
+	AfterType     string
+	MaxRows       int32
+}
+
+type ListHeldNotificationGroupsRow struct {
+	TenantID string
+	UserID   uuid.UUID
+	Type     string
+}
+
+// Groups are paged in key order so that those still over their rate limit
+// do not keep coming back ahead of the rest.
+func (q *Queries) ListHeldNotificationGroups(ctx context.Context, arg ListHeldNotificationGroupsParams) ([]ListHeldNotificationGroupsRow, error) {
+	rows, err := q.db.Query(ctx, listHeldNotificationGroups,
+		arg.AfterTenantID,
+		arg.AfterUserID,
+		arg.AfterType,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListHeldNotificationGroupsRow
+	for rows.Next() {
+		var i ListHeldNotificationGroupsRow
+		if err := rows.Scan(&i.TenantID, &i.UserID, &i.Type); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSentNotificationsAfter = `-- name: ListSentNotificationsAfter :many
+SELECT id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND status = 'sent'
+  AND (sent_at, id) > ($3::timestamp, $4::uuid)
+ORDER BY sent_at, id
+LIMIT $5
+`
+
+type ListSentNotificationsAfterParams struct {
+	TenantID    string
+	UserID      uuid.UUID
+	AfterSentAt time.Time
+	AfterID     uuid.UUID
+	MaxRows     int32
+}
+
+func (q *Queries) ListSentNotificationsAfter(ctx context.Context, arg ListSentNotificationsAfterParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, listSentNotificationsAfter,
+		arg.TenantID,
+		arg.UserID,
+		arg.AfterSentAt,
+		arg.AfterID,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Message,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SendAt,
+			&i.SentAt,
+			&i.ExpiresAt,
+			&i.StatusReason,
+			&i.IdempotencyKey,
+			&i.RequestHash,
+			&i.ContentHash,
+			&i.TemplateID,
+			&i.TemplateVersion,
+			&i.Locale,
+			&i.Title,
+			&i.ActionURL,
+			&i.Metadata,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.TenantID,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserNotifications = `-- name: ListUserNotifications :many
+SELECT id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+FROM notifications
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND ($3::text[] IS NULL OR type = ANY($3::text[]))
+  AND ($4::text[] IS NULL OR status = ANY($4::text[]))
+  AND ($5::timestamp IS NULL OR created_at >= $5::timestamp)
+  AND ($6::timestamp IS NULL OR created_at < $6::timestamp)
+  AND ($7::timestamp IS NULL
+    OR (created_at, id) < ($7::timestamp, $8::uuid))
+  AND ($9::boolean IS NULL OR (read_at IS NOT NULL) = $9::boolean)
+  AND ($10::boolean IS NULL OR (archived_at IS NOT NULL) = $10::boolean)
+ORDER BY created_at DESC, id DESC
+LIMIT $11
+`
+
+type ListUserNotificationsParams struct {
+	TenantID       string
+	UserID         uuid.UUID
+	Types          []string
+	Statuses       []string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	AfterCreatedAt *time.Time
+	AfterID        *uuid.UUID
+	Read           *bool
+	Archived       *bool
+	MaxRows        int32
+}
+
+func (q *Queries) ListUserNotifications(ctx context.Context, arg ListUserNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, listUserNotifications,
+		arg.TenantID,
+		arg.UserID,
+		arg.Types,
+		arg.Statuses,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Read,
+		arg.Archived,
+		arg.MaxRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Type,
+			&i.Message,
+			&i.CreatedAt,
+			&i.Status,
+			&i.SendAt,
+			&i.SentAt,
+			&i.ExpiresAt,
+			&i.StatusReason,
+			&i.IdempotencyKey,
+			&i.RequestHash,
+			&i.ContentHash,
+			&i.TemplateID,
+			&i.TemplateVersion,
+			&i.Locale,
+			&i.Title,
+			&i.ActionURL,
+			&i.Metadata,
+			&i.ReadAt,
+			&i.ArchivedAt,
+			&i.TenantID,
+			&i.LeasedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllNotificationsRead = `-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND status = 'sent'
+  AND read_at IS NULL
+`
+
+type MarkAllNotificationsReadParams struct {
+	TenantID string
+	UserID   uuid.UUID
+}
+
+func (q *Queries) MarkAllNotificationsRead(ctx context.Context, arg MarkAllNotificationsReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markAllNotificationsRead, arg.TenantID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :one
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE tenant_id = $1
+  AND id = $2
+  AND user_id = $3
+  AND status = 'sent'
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+`
+
+type MarkNotificationReadParams struct {
+	TenantID string
+	ID       uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, markNotificationRead, arg.TenantID, arg.ID, arg.UserID)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const markNotificationsRead = `-- name: MarkNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE tenant_id = $1
+  AND user_id = $2
+  AND id = ANY($3::uuid[])
+  AND status = 'sent'
+  AND read_at IS NULL
+`
+
+type MarkNotificationsReadParams struct {
+	TenantID string
+	UserID   uuid.UUID
+	Ids      []uuid.UUID
+}
+
+func (q *Queries) MarkNotificationsRead(ctx context.Context, arg MarkNotificationsReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationsRead, arg.TenantID, arg.UserID, arg.Ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseIdempotencyKey = `-- name: ReleaseIdempotencyKey :exec
+UPDATE notifications
+SET idempotency_key = NULL
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type ReleaseIdempotencyKeyParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) error {
+	_, err := q.db.Exec(ctx, releaseIdempotencyKey, arg.TenantID, arg.ID)
+	return err
+}
+
+const setNotificationArchived = `-- name: SetNotificationArchived :one
+UPDATE notifications
+SET archived_at = CASE WHEN $1::boolean THEN COALESCE(archived_at, NOW()) END
+WHERE tenant_id = $2
+  AND id = $3
+  AND user_id = $4
+  AND status = 'sent'
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+`
+
+type SetNotificationArchivedParams struct {
+	Archived bool
+	TenantID string
+	ID       uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) SetNotificationArchived(ctx context.Context, arg SetNotificationArchivedParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, setNotificationArchived,
+		arg.Archived,
+		arg.TenantID,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Type,
+		&i.Message,
+		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
+	)
+	return i, err
+}
+
+const updateNotificationStatus = `-- name: UpdateNotificationStatus :one
+UPDATE notifications
+SET status = $1,
+    status_reason = $2,
+    leased_until = NULL,
+    sent_at = CASE WHEN $1::text = 'sent' THEN $3::timestamp ELSE sent_at END
+WHERE tenant_id = $4
+  AND id = $5
+  AND status = $6
+RETURNING id, user_id, type, message, created_at, status, send_at, sent_at, expires_at, status_reason, idempotency_key, request_hash, content_hash, template_id, template_version, locale, title, action_url, metadata, read_at, archived_at, tenant_id, leased_until
+`
+
+type UpdateNotificationStatusParams struct {
+	ToStatus   string
+	Reason     string
+	ChangedAt  time.Time
+	TenantID   string
+	ID         uuid.UUID
+	FromStatus string
+}
+
+func (q *Queries) UpdateNotificationStatus(ctx context.Context, arg UpdateNotificationStatusParams) (Notification, error) {
+	row := q.db.QueryRow(ctx, updateNotificationStatus,
+		arg.ToStatus,
+		arg.Reason,
+		arg.ChangedAt,
+		arg.TenantID,
+		arg.ID,
+		arg.FromStatus,
+	)
 	var i Notification
 	err := row.Scan(
 		&i.ID,
@@ -54,6 +893,24 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		&i.Type,
 		&i.Message,
 		&i.CreatedAt,
+		&i.Status,
+		&i.SendAt,
+		&i.SentAt,
+		&i.ExpiresAt,
+		&i.StatusReason,
+		&i.IdempotencyKey,
+		&i.RequestHash,
+		&i.ContentHash,
+		&i.TemplateID,
+		&i.TemplateVersion,
+		&i.Locale,
+		&i.Title,
+		&i.ActionURL,
+		&i.Metadata,
+		&i.ReadAt,
+		&i.ArchivedAt,
+		&i.TenantID,
+		&i.LeasedUntil,
 	)
 	return i, err
 }
@@ -0,0 +1,122 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: rate_limit_rules.sql
+
+package sqlc
+
+import (
+	"context"
+)
+
+const deleteRateLimitRule = `-- name: DeleteRateLimitRule :execrows
+DELETE FROM rate_limit_rules
+WHERE tenant_id = $1
+  AND type = $2
+`
+
+type DeleteRateLimitRuleParams struct {
+	TenantID string
+	Type     string
+}
+
+func (q *Queries) DeleteRateLimitRule(ctx context.Context, arg DeleteRateLimitRuleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteRateLimitRule, arg.TenantID, arg.Type)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getRateLimitRule = `-- name: GetRateLimitRule :one
+SELECT tenant_id, type, max_count, interval_seconds, updated_at
+FROM rate_limit_rules
+WHERE tenant_id = $1
+  AND type = $2
+`
+
+type GetRateLimitRuleParams struct {
+	TenantID string
+	Type     string
+}
+
+func (q *Queries) GetRateLimitRule(ctx context.Context, arg GetRateLimitRuleParams) (RateLimitRule, error) {
+	row := q.db.QueryRow(ctx, getRateLimitRule, arg.TenantID, arg.Type)
+	var i RateLimitRule
+	err := row.Scan(
+		&i.TenantID,
+		&i.Type,
+		&i.MaxCount,
+		&i.IntervalSeconds,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const listRateLimitRules = `-- name: ListRateLimitRules :many
+SELECT tenant_id, type, max_count, interval_seconds, updated_at
+FROM rate_limit_rules
+WHERE tenant_id = $1
+ORDER BY type
+`
+
+func (q *Queries) ListRateLimitRules(ctx context.Context, tenantID string) ([]RateLimitRule, error) {
+	rows, err := q.db.Query(ctx, listRateLimitRules, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RateLimitRule
+	for rows.Next() {
+		var i RateLimitRule
+		if err := rows.Scan(
+			&i.TenantID,
+			&i.Type,
+			&i.MaxCount,
+			&i.IntervalSeconds,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertRateLimitRule = `-- name: UpsertRateLimitRule :one
+INSERT INTO rate_limit_rules (tenant_id, type, max_count, interval_seconds)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant_id, type) DO UPDATE
+SET max_count = EXCLUDED.max_count,
+    interval_seconds = EXCLUDED.interval_seconds,
+    updated_at = NOW()
+RETURNING tenant_id, type, max_count, interval_seconds, updated_at
+`
+
+type UpsertRateLimitRuleParams struct {
+	TenantID        string
+	Type            string
+	MaxCount        int32
+	IntervalSeconds int32
+}
+
+func (q *Queries) UpsertRateLimitRule(ctx context.Context, arg UpsertRateLimitRuleParams) (RateLimitRule, error) {
+	row := q.db.QueryRow(ctx, upsertRateLimitRule,
+		arg.TenantID,
+		arg.Type,
+		arg.MaxCount,
+		arg.IntervalSeconds,
+	)
+	var i RateLimitRule
+	err := row.Scan(
+		&i.TenantID,
+		&i.Type,
+		&i.MaxCount,
+		&i.IntervalSeconds,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,224 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: segments.sql
+
+package sqlc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countSegmentMembers = `-- name: CountSegmentMembers :one
+SELECT COUNT(*) as total
+FROM segment_members
+WHERE tenant_id = $1
+  AND segment_id = $2
+`
+
+type CountSegmentMembersParams struct {
+	TenantID  string
+	SegmentID uuid.UUID
+}
+
+func (q *Queries) CountSegmentMembers(ctx context.Context, arg CountSegmentMembersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSegmentMembers, arg.TenantID, arg.SegmentID)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const countUsersMatching = `-- name: CountUsersMatching :one
+SELECT COUNT(*) as total
+FROM user_attributes
+WHERE tenant_id = $1
+  AND attributes @> $2::jsonb
+  AND tags @> $3::text[]
+`
+
+type CountUsersMatchingParams struct {
+	TenantID   string
+	Attributes []byte
+	Tags       []string
+}
+
+func (q *Queries) CountUsersMatching(ctx context.Context, arg CountUsersMatchingParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsersMatching, arg.TenantID, arg.Attributes, arg.Tags)
+	var total int64
+	err := row.Scan(&total)
+	return total, err
+}
+
+const createSegment = `-- name: CreateSegment :one
+WITH segment AS (
+  INSERT INTO segments (id, tenant_id, name, attributes, tags, static)
+  VALUES ($1, $2, $3, $4, $6::text[], $5)
+  RETURNING id, name, attributes, tags, static, created_at, tenant_id
+), members AS (
+  INSERT INTO segment_members (segment_id, tenant_id, user_id)
+  SELECT $1, $2, unnest($7::uuid[])
+)
+SELECT id, name, attributes, tags, static, created_at, tenant_id FROM segment
+`
+
+type CreateSegmentParams struct {
+	ID         uuid.UUID
+	TenantID   string
+	Name       string
+	Attributes []byte
+	Static     bool
+	Tags       []string
+	UserIDs    []uuid.UUID
+}
+
+type CreateSegmentRow struct {
+	ID         uuid.UUID
+	Name       string
+	Attributes []byte
+	Tags       []string
+	Static     bool
+	CreatedAt  time.Time
+	TenantID   string
+}
+
+// Members of a static segment are stored in the same statement so that a
+// segment never exists without them.
+func (q *Queries) CreateSegment(ctx context.Context, arg CreateSegmentParams) (CreateSegmentRow, error) {
+	row := q.db.QueryRow(ctx, createSegment,
+		arg.ID,
+		arg.TenantID,
+		arg.Name,
+		arg.Attributes,
+		arg.Static,
+		arg.Tags,
+		arg.UserIDs,
+	)
+	var i CreateSegmentRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Attributes,
+		&i.Tags,
+		&i.Static,
+		&i.CreatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getSegment = `-- name: GetSegment :one
+SELECT id, name, attributes, tags, static, created_at, tenant_id
+FROM segments
+WHERE tenant_id = $1
+  AND id = $2
+`
+
+type GetSegmentParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetSegment(ctx context.Context, arg GetSegmentParams) (Segment, error) {
+	row := q.db.QueryRow(ctx, getSegment, arg.TenantID, arg.ID)
+	var i Segment
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Attributes,
+		&i.Tags,
+		&i.Static,
+		&i.CreatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listSegmentMembers = `-- name: ListSegmentMembers :many
+SELECT user_id
+FROM segment_members
+WHERE tenant_id = $1
+  AND segment_id = $2
+  AND user_id > $4::uuid
+ORDER BY user_id
+LIMIT $3
+`
+
+type ListSegmentMembersParams struct {
+	TenantID  string
+	SegmentID uuid.UUID
+	Limit     int32
+	After     uuid.UUID
+}
+
+func (q *Queries) ListSegmentMembers(ctx context.Context, arg ListSegmentMembersParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listSegmentMembers,
+		arg.TenantID,
+		arg.SegmentID,
+		arg.Limit,
+		arg.After,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersMatching = `-- name: ListUsersMatching :many
+SELECT user_id
+FROM user_attributes
+WHERE tenant_id = $1
+  AND attributes @> $3::jsonb
+  AND tags @> $4::text[]
+  AND user_id > $5::uuid
+ORDER BY user_id
+LIMIT $2
+`
+
+type ListUsersMatchingParams struct {
+	TenantID   string
+	Limit      int32
+	Attributes []byte
+	Tags       []string
+	After      uuid.UUID
+}
+
+func (q *Queries) ListUsersMatching(ctx context.Context, arg ListUsersMatchingParams) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listUsersMatching,
+		arg.TenantID,
+		arg.Limit,
+		arg.Attributes,
+		arg.Tags,
+		arg.After,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,154 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: templates.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createTemplate = `-- name: CreateTemplate :one
+INSERT INTO templates (id, tenant_id, version, name, body, variables, locale, variants)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, version, name, body, variables, created_at, locale, variants, tenant_id
+`
+
+type CreateTemplateParams struct {
+	ID        uuid.UUID
+	TenantID  string
+	Version   int32
+	Name      string
+	Body      string
+	Variables []byte
+	Locale    string
+	Variants  []byte
+}
+
+func (q *Queries) CreateTemplate(ctx context.Context, arg CreateTemplateParams) (Template, error) {
+	row := q.db.QueryRow(ctx, createTemplate,
+		arg.ID,
+		arg.TenantID,
+		arg.Version,
+		arg.Name,
+		arg.Body,
+		arg.Variables,
+		arg.Locale,
+		arg.Variants,
+	)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.Version,
+		&i.Name,
+		&i.Body,
+		&i.Variables,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.Variants,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getLatestTemplate = `-- name: GetLatestTemplate :one
+SELECT id, version, name, body, variables, created_at, locale, variants, tenant_id
+FROM templates
+WHERE tenant_id = $1
+  AND id = $2
+ORDER BY version DESC
+LIMIT 1
+`
+
+type GetLatestTemplateParams struct {
+	TenantID string
+	ID       uuid.UUID
+}
+
+func (q *Queries) GetLatestTemplate(ctx context.Context, arg GetLatestTemplateParams) (Template, error) {
+	row := q.db.QueryRow(ctx, getLatestTemplate, arg.TenantID, arg.ID)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.Version,
+		&i.Name,
+		&i.Body,
+		&i.Variables,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.Variants,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const getTemplateVersion = `-- name: GetTemplateVersion :one
+SELECT id, version, name, body, variables, created_at, locale, variants, tenant_id
+FROM templates
+WHERE tenant_id = $1
+  AND id = $2
+  AND version = $3
+`
+
+type GetTemplateVersionParams struct {
+	TenantID string
+	ID       uuid.UUID
+	Version  int32
+}
+
+func (q *Queries) GetTemplateVersion(ctx context.Context, arg GetTemplateVersionParams) (Template, error) {
+	row := q.db.QueryRow(ctx, getTemplateVersion, arg.TenantID, arg.ID, arg.Version)
+	var i Template
+	err := row.Scan(
+		&i.ID,
+		&i.Version,
+		&i.Name,
+		&i.Body,
+		&i.Variables,
+		&i.CreatedAt,
+		&i.Locale,
+		&i.Variants,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const listLatestTemplates = `-- name: ListLatestTemplates :many
+SELECT DISTINCT ON (id) id, version, name, body, variables, created_at, locale, variants, tenant_id
+FROM templates
+WHERE tenant_id = $1
+ORDER BY id, version DESC
+`
+
+func (q *Queries) ListLatestTemplates(ctx context.Context, tenantID string) ([]Template, error) {
+	rows, err := q.db.Query(ctx, listLatestTemplates, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Template
+	for rows.Next() {
+		var i Template
+		if err := rows.Scan(
+			&i.ID,
+			&i.Version,
+			&i.Name,
+			&i.Body,
+			&i.Variables,
+			&i.CreatedAt,
+			&i.Locale,
+			&i.Variants,
+			&i.TenantID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,72 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: user_attributes.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserAttributes = `-- name: GetUserAttributes :one
+SELECT user_id, attributes, tags, updated_at, tenant_id
+FROM user_attributes
+WHERE tenant_id = $1
+  AND user_id = $2
+`
+
+type GetUserAttributesParams struct {
+	TenantID string
+	UserID   uuid.UUID
+}
+
+func (q *Queries) GetUserAttributes(ctx context.Context, arg GetUserAttributesParams) (UserAttribute, error) {
+	row := q.db.QueryRow(ctx, getUserAttributes, arg.TenantID, arg.UserID)
+	var i UserAttribute
+	err := row.Scan(
+		&i.UserID,
+		&i.Attributes,
+		&i.Tags,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const upsertUserAttributes = `-- name: UpsertUserAttributes :one
+INSERT INTO user_attributes (tenant_id, user_id, attributes, tags)
+VALUES ($1, $2, $3, $4::text[])
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET attributes = EXCLUDED.attributes,
+    tags = EXCLUDED.tags,
+    updated_at = NOW()
+RETURNING user_id, attributes, tags, updated_at, tenant_id
+`
+
+type UpsertUserAttributesParams struct {
+	TenantID   string
+	UserID     uuid.UUID
+	Attributes []byte
+	Tags       []string
+}
+
+func (q *Queries) UpsertUserAttributes(ctx context.Context, arg UpsertUserAttributesParams) (UserAttribute, error) {
+	row := q.db.QueryRow(ctx, upsertUserAttributes,
+		arg.TenantID,
+		arg.UserID,
+		arg.Attributes,
+		arg.Tags,
+	)
+	var i UserAttribute
+	err := row.Scan(
+		&i.UserID,
+		&i.Attributes,
+		&i.Tags,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: user_preferences.sql
+
+package sqlc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const getUserPreferences = `-- name: GetUserPreferences :one
+SELECT user_id, locale, updated_at, tenant_id
+FROM user_preferences
+WHERE tenant_id = $1
+  AND user_id = $2
+`
+
+type GetUserPreferencesParams struct {
+	TenantID string
+	UserID   uuid.UUID
+}
+
+func (q *Queries) GetUserPreferences(ctx context.Context, arg GetUserPreferencesParams) (UserPreference, error) {
+	row := q.db.QueryRow(ctx, getUserPreferences, arg.TenantID, arg.UserID)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Locale,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
+
+const upsertUserPreferences = `-- name: UpsertUserPreferences :one
+INSERT INTO user_preferences (tenant_id, user_id, locale)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE
+SET locale = EXCLUDED.locale,
+    updated_at = NOW()
+RETURNING user_id, locale, updated_at, tenant_id
+`
+
+type UpsertUserPreferencesParams struct {
+	TenantID string
+	UserID   uuid.UUID
+	Locale   string
+}
+
+func (q *Queries) UpsertUserPreferences(ctx context.Context, arg UpsertUserPreferencesParams) (UserPreference, error) {
+	row := q.db.QueryRow(ctx, upsertUserPreferences, arg.TenantID, arg.UserID, arg.Locale)
+	var i UserPreference
+	err := row.Scan(
+		&i.UserID,
+		&i.Locale,
+		&i.UpdatedAt,
+		&i.TenantID,
+	)
+	return i, err
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// templatesQuerier is the subset of *sqlc.Queries used by TemplateRepository.
+type templatesQuerier interface {
+	CreateTemplate(ctx context.Context, arg sqlc.CreateTemplateParams) (sqlc.Template, error)
+	GetLatestTemplate(ctx context.Context, arg sqlc.GetLatestTemplateParams) (sqlc.Template, error)
+	GetTemplateVersion(ctx context.Context, arg sqlc.GetTemplateVersionParams) (sqlc.Template, error)
+	ListLatestTemplates(ctx context.Context, tenantID string) ([]sqlc.Template, error)
+}
+
+// templateVariable is the JSON shape of a declared variable in the
+// templates.variables column.
+type templateVariable struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+}
+
+// TemplateRepository scopes every call to the tenant of its context.
+type TemplateRepository struct {
+	q templatesQuerier
+}
+
+func NewTemplateRepository(q templatesQuerier) ports.TemplateRepository {
+	return &TemplateRepository{q: q}
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	vars := make([]templateVariable, 0, len(t.Variables))
+	for _, v := range t.Variables {
+		vars = append(vars, templateVariable{Name: v.Name, Required: v.Required})
+	}
+	rawVars, err := json.Marshal(vars)
+	if err != nil {
+		return entity.Template{}, err
+	}
+	variants := t.Variants
+	if variants == nil {
+		variants = map[string]string{}
+	}
+	rawVariants, err := json.Marshal(variants)
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	row, err := r.q.CreateTemplate(ctx, sqlc.CreateTemplateParams{
+		ID:        t.ID,
+		TenantID:  tenantOf(ctx),
+		Version:   int32(t.Version),
+		Name:      t.Name,
+		Body:      t.Body,
+		Variables: rawVars,
+		Locale:    t.Locale,
+		Variants:  rawVariants,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return entity.Template{}, errs.ErrTemplateConflict
+		}
+		return entity.Template{}, err
+	}
+
+	return toTemplateEntity(row)
+}
+
+func (r *TemplateRepository) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	var (
+		row sqlc.Template
+		err error
+	)
+	if version == 0 {
+		row, err = r.q.GetLatestTemplate(ctx, sqlc.GetLatestTemplateParams{TenantID: tenantOf(ctx), ID: id})
+	} else {
+		row, err = r.q.GetTemplateVersion(ctx, sqlc.GetTemplateVersionParams{
+			TenantID: tenantOf(ctx),
+			ID:       id,
+			Version:  int32(version),
+		})
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.Template{}, errs.ErrTemplateNotFound
+	}
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	return toTemplateEntity(row)
+}
+
+func (r *TemplateRepository) List(ctx context.Context) ([]entity.Template, error) {
+	rows, err := r.q.ListLatestTemplates(ctx, tenantOf(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]entity.Template, 0, len(rows))
+	for _, row := range rows {
+		t, err := toTemplateEntity(row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func toTemplateEntity(row sqlc.Template) (entity.Template, error) {
+	var vars []templateVariable
+	if err := json.Unmarshal(row.Variables, &vars); err != nil {
+		return entity.Template{}, err
+	}
+	var variants map[string]string
+	if err := json.Unmarshal(row.Variants, &variants); err != nil {
+		return entity.Template{}, err
+	}
+
+	t := entity.Template{
+		ID:        row.ID,
+		Version:   int(row.Version),
+		Name:      row.Name,
+		Body:      row.Body,
+		Locale:    row.Locale,
+		Variants:  variants,
+		Variables: make([]entity.TemplateVariable, 0, len(vars)),
+		CreatedAt: row.CreatedAt,
+	}
+	for _, v := range vars {
+		t.Variables = append(t.Variables, entity.TemplateVariable{Name: v.Name, Required: v.Required})
+	}
+	return t, nil
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTemplateQueries struct{ mock.Mock }
+
+func (m *mockTemplateQueries) CreateTemplate(ctx context.Context, arg sqlc.CreateTemplateParams) (sqlc.Template, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Template), args.Error(1)
+}
+
+func (m *mockTemplateQueries) GetLatestTemplate(ctx context.Context, arg sqlc.GetLatestTemplateParams) (sqlc.Template, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Template), args.Error(1)
+}
+
+func (m *mockTemplateQueries) GetTemplateVersion(ctx context.Context, arg sqlc.GetTemplateVersionParams) (sqlc.Template, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.Template), args.Error(1)
+}
+
+func (m *mockTemplateQueries) ListLatestTemplates(ctx context.Context, tenantID string) ([]sqlc.Template, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).([]sqlc.Template), args.Error(1)
+}
+
+func TestTemplateRepositoryCreate(t *testing.T) {
+	id := uuid.New()
+	vars := []byte(`[{"name":"name","required":true}]`)
+	variants := []byte(`{"pt":"Oi {{.name}}"}`)
+
+	mq := new(mockTemplateQueries)
+	repo := NewTemplateRepository(mq)
+
+	mq.On("CreateTemplate", mock.Anything, sqlc.CreateTemplateParams{
+		ID:        id,
+		TenantID:  "default",
+		Version:   1,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: vars,
+		Locale:    "en",
+		Variants:  variants,
+	}).Return(sqlc.Template{ID: id, Version: 1, Name: "welcome", Body: "Hi {{.name}}", Variables: vars, Locale: "en", Variants: variants}, nil)
+
+	tmpl, err := repo.Create(defaultTenant(), entity.Template{
+		ID:        id,
+		Version:   1,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Locale:    "en",
+		Variants:  map[string]string{"pt": "Oi {{.name}}"},
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []entity.TemplateVariable{{Name: "name", Required: true}}, tmpl.Variables)
+	require.Equal(t, map[string]string{"pt": "Oi {{.name}}"}, tmpl.Variants)
+
+	mq.AssertExpectations(t)
+}
+
+func TestTemplateRepositoryCreateConflict(t *testing.T) {
+	mq := new(mockTemplateQueries)
+	repo := NewTemplateRepository(mq)
+
+	mq.On("CreateTemplate", mock.Anything, mock.Anything).Return(sqlc.Template{}, &pgconn.PgError{Code: uniqueViolation})
+
+	_, err := repo.Create(defaultTenant(), entity.Template{ID: uuid.New(), Version: 2, Name: "welcome", Body: "Hi"})
+	require.ErrorIs(t, err, errs.ErrTemplateConflict)
+
+	mq.AssertExpectations(t)
+}
+
+func TestTemplateRepositoryGetVersion(t *testing.T) {
+	id := uuid.New()
+
+	mq := new(mockTemplateQueries)
+	repo := NewTemplateRepository(mq)
+
+	mq.On("GetTemplateVersion", mock.Anything, sqlc.GetTemplateVersionParams{TenantID: "shop", ID: id, Version: 3}).Return(sqlc.Template{}, pgx.ErrNoRows)
+
+	_, err := repo.Get(entity.WithTenant(defaultTenant(), "shop"), id, 3)
+	require.ErrorIs(t, err, errs.ErrTemplateNotFound)
+
+	mq.AssertExpectations(t)
+}
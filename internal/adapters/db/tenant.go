@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// errNoTenant fails queries whose context acts for no tenant, which is a
+// bug: a call that forgot entity.WithTenant would otherwise find nothing.
+var errNoTenant = errors.New("context acts for no tenant")
+
+// Roles the migrations create. apiRole is subject to the row-level
+// security policies; workerRole bypasses them.
+const (
+	apiRole    = "modak_api"
+	workerRole = "modak_worker"
+)
+
+// IsolateTenants makes every connection taken from a pool built with cfg
+// act as apiRole, with app.tenant_id set to the tenant of the context it
+// is acquired with, so that the row-level security policies only let it
+// reach that tenant's rows. Only contexts from entity.WithAllTenants act
+// as workerRole and reach every tenant; a context that names no tenant
+// fails to acquire a connection.
+//
+// The pool must log in as a role that is a member of both, and should
+// not inherit their privileges, so that a connection that skipped this
+// can do nothing. This backs up the tenant filters of the queries, at the
+// cost of one round trip per acquisition.
+func IsolateTenants(cfg *pgxpool.Config) {
+	cfg.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		role := apiRole
+		tenant, ok := entity.TenantFromContext(ctx)
+		switch {
+		case entity.ActsForAllTenants(ctx):
+			role = workerRole
+		case !ok:
+			return true, errNoTenant
+		}
+		if _, err := conn.Exec(ctx, "SELECT set_config('role', $1, false), set_config('app.tenant_id', $2, false)", role, string(tenant)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+// Ping checks that pool reaches the database. It reads no tenant's rows,
+// so it acts for all of them.
+func Ping(ctx context.Context, pool *pgxpool.Pool) error {
+	return pool.Ping(entity.WithAllTenants(ctx))
+}
+
+// tenantOf returns the tenant repositories scope a call with ctx to, which
+// is empty, and so matches no row, when ctx acts for no tenant.
+func tenantOf(ctx context.Context) string {
+	return string(entity.TenantOf(ctx))
+}
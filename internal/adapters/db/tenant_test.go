@@ -0,0 +1,20 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultTenant returns a context acting for entity.DefaultTenant.
+func defaultTenant() context.Context {
+	return entity.WithTenant(context.Background(), entity.DefaultTenant)
+}
+
+func TestTenantOfIsEmptyWithoutATenant(t *testing.T) {
+	require.Equal(t, "shop", tenantOf(entity.WithTenant(context.Background(), "shop")))
+	require.Empty(t, tenantOf(context.Background()))
+	require.Empty(t, tenantOf(entity.WithAllTenants(context.Background())))
+}
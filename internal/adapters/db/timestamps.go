@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StoreTimesInUTC makes every connection of a pool built with cfg store
+// times in UTC. The schema's timestamp columns carry no zone, and pgx
+// would otherwise keep the clock reading of a time.Time in the host's
+// zone and drop the zone, shifting the instant it stores by the host's
+// offset. The session runs in UTC too, so that the NOW() defaults of the
+// columns agree with the times the service writes.
+func StoreTimesInUTC(cfg *pgxpool.Config) {
+	cfg.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	cfg.AfterConnect = func(_ context.Context, conn *pgx.Conn) error {
+		conn.TypeMap().RegisterType(&pgtype.Type{Name: "timestamp", OID: pgtype.TimestampOID, Codec: &utcTimestampCodec{}})
+		return nil
+	}
+}
+
+// utcTimestampCodec encodes timestamps as pgtype.TimestampCodec does,
+// after converting them to UTC.
+type utcTimestampCodec struct {
+	pgtype.TimestampCodec
+}
+
+func (c *utcTimestampCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	next := c.TimestampCodec.PlanEncode(m, oid, format, value)
+	if next == nil {
+		return nil
+	}
+	return utcTimestampPlan{next: next}
+}
+
+type utcTimestampPlan struct {
+	next pgtype.EncodePlan
+}
+
+func (p utcTimestampPlan) Encode(value any, buf []byte) ([]byte, error) {
+	ts, err := value.(pgtype.TimestampValuer).TimestampValue()
+	if err != nil {
+		return nil, err
+	}
+	ts.Time = ts.Time.UTC()
+	return p.next.Encode(ts, buf)
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUTCTimestampCodecEncodesTheInstant(t *testing.T) {
+	m := pgtype.NewMap()
+	m.RegisterType(&pgtype.Type{Name: "timestamp", OID: pgtype.TimestampOID, Codec: &utcTimestampCodec{}})
+
+	sent := time.Date(2026, 3, 10, 12, 0, 0, 0, time.FixedZone("BRT", -3*60*60))
+	want := sent.UTC()
+	for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+		for _, value := range []any{sent, &sent} {
+			buf, err := m.Encode(pgtype.TimestampOID, format, value, nil)
+			require.NoError(t, err)
+
+			var got time.Time
+			require.NoError(t, m.Scan(pgtype.TimestampOID, format, buf, &got))
+			require.True(t, want.Equal(got), "format %d: got %s, want %s", format, got, want)
+		}
+	}
+}
+
+func TestStoreTimesInUTCSetsTheSessionZone(t *testing.T) {
+	cfg, err := pgxpool.ParseConfig("postgres://localhost/modak")
+	require.NoError(t, err)
+	StoreTimesInUTC(cfg)
+
+	require.Equal(t, "UTC", cfg.ConnConfig.RuntimeParams["timezone"])
+	require.NotNil(t, cfg.AfterConnect)
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceQueries makes every query run on a pool built with cfg a span,
+// named after its sqlc query such as CreateNotification, in the trace of
+// the context it runs with.
+func TraceQueries(cfg *pgxpool.Config) {
+	cfg.ConnConfig.Tracer = queryTracer{}
+}
+
+type queryTracer struct{}
+
+var dbSystem = attribute.String("db.system", "postgresql")
+
+func (queryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = tracing.Tracer().Start(ctx, queryName(data.SQL), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystem, attribute.String("db.query.text", data.SQL)))
+	return ctx
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	err := data.Err
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Repositories report missing rows as not found; the query worked.
+		err = nil
+	}
+	if err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	tracing.End(span, err)
+}
+
+func (queryTracer) TraceCopyFromStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	ctx, _ = tracing.Tracer().Start(ctx, "COPY "+strings.Join(data.TableName, "."), trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(dbSystem, attribute.StringSlice("db.copy.columns", data.ColumnNames)))
+	return ctx
+}
+
+func (queryTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+	tracing.End(span, data.Err)
+}
+
+// queryName returns the name sqlc gives a query in the comment it starts
+// with, or else the first keyword of the statement, such as SELECT.
+func queryName(sql string) string {
+	if rest, ok := strings.CutPrefix(sql, "-- name: "); ok {
+		if name, _, ok := strings.Cut(rest, " "); ok {
+			return name
+		}
+	}
+	if fields := strings.Fields(sql); len(fields) > 0 {
+		return strings.ToUpper(fields[0])
+	}
+	return "query"
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// userAttributesQuerier is the subset of *sqlc.Queries used by
+// UserAttributesRepository.
+type userAttributesQuerier interface {
+	GetUserAttributes(ctx context.Context, arg sqlc.GetUserAttributesParams) (sqlc.UserAttribute, error)
+	UpsertUserAttributes(ctx context.Context, arg sqlc.UpsertUserAttributesParams) (sqlc.UserAttribute, error)
+}
+
+type UserAttributesRepository struct {
+	q userAttributesQuerier
+}
+
+func NewUserAttributesRepository(q userAttributesQuerier) ports.UserAttributesRepository {
+	return &UserAttributesRepository{q: q}
+}
+
+func (r *UserAttributesRepository) Get(ctx context.Context, userID uuid.UUID) (entity.UserAttributes, error) {
+	row, err := r.q.GetUserAttributes(ctx, sqlc.GetUserAttributesParams{TenantID: tenantOf(ctx), UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.UserAttributes{}, errs.ErrUserAttributesNotFound
+	}
+	if err != nil {
+		return entity.UserAttributes{}, err
+	}
+	return toUserAttributesEntity(row)
+}
+
+func (r *UserAttributesRepository) Upsert(ctx context.Context, a entity.UserAttributes) (entity.UserAttributes, error) {
+	rawAttributes, err := marshalMetadata(a.Attributes)
+	if err != nil {
+		return entity.UserAttributes{}, err
+	}
+
+	row, err := r.q.UpsertUserAttributes(ctx, sqlc.UpsertUserAttributesParams{
+		TenantID:   tenantOf(ctx),
+		UserID:     a.UserID,
+		Attributes: rawAttributes,
+		Tags:       nonNilTags(a.Tags),
+	})
+	if err != nil {
+		return entity.UserAttributes{}, err
+	}
+	return toUserAttributesEntity(row)
+}
+
+func toUserAttributesEntity(row sqlc.UserAttribute) (entity.UserAttributes, error) {
+	var attributes map[string]string
+	if err := json.Unmarshal(row.Attributes, &attributes); err != nil {
+		return entity.UserAttributes{}, err
+	}
+	return entity.UserAttributes{
+		UserID:     row.UserID,
+		Attributes: attributes,
+		Tags:       row.Tags,
+		UpdatedAt:  row.UpdatedAt,
+	}, nil
+}
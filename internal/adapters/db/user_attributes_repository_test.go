@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserAttributesQueries struct{ mock.Mock }
+
+func (m *mockUserAttributesQueries) GetUserAttributes(ctx context.Context, arg sqlc.GetUserAttributesParams) (sqlc.UserAttribute, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.UserAttribute), args.Error(1)
+}
+
+func (m *mockUserAttributesQueries) UpsertUserAttributes(ctx context.Context, arg sqlc.UpsertUserAttributesParams) (sqlc.UserAttribute, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.UserAttribute), args.Error(1)
+}
+
+func TestUserAttributesRepositoryGetNotFound(t *testing.T) {
+	uid := uuid.New()
+
+	mq := new(mockUserAttributesQueries)
+	repo := NewUserAttributesRepository(mq)
+
+	mq.On("GetUserAttributes", mock.Anything, sqlc.GetUserAttributesParams{TenantID: "default", UserID: uid}).Return(sqlc.UserAttribute{}, pgx.ErrNoRows)
+
+	_, err := repo.Get(defaultTenant(), uid)
+	require.ErrorIs(t, err, errs.ErrUserAttributesNotFound)
+}
+
+func TestUserAttributesRepositoryUpsert(t *testing.T) {
+	uid := uuid.New()
+
+	mq := new(mockUserAttributesQueries)
+	repo := NewUserAttributesRepository(mq)
+
+	mq.On("UpsertUserAttributes", mock.Anything, sqlc.UpsertUserAttributesParams{
+		TenantID:   "shop",
+		UserID:     uid,
+		Attributes: []byte(`{"plan":"pro"}`),
+		Tags:       []string{},
+	}).Return(sqlc.UserAttribute{UserID: uid, Attributes: []byte(`{"plan":"pro"}`), Tags: []string{}}, nil)
+
+	a, err := repo.Upsert(entity.WithTenant(defaultTenant(), "shop"), entity.UserAttributes{UserID: uid, Attributes: map[string]string{"plan": "pro"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"plan": "pro"}, a.Attributes)
+
+	mq.AssertExpectations(t)
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// userPreferencesQuerier is the subset of *sqlc.Queries used by
+// UserPreferencesRepository.
+type userPreferencesQuerier interface {
+	GetUserPreferences(ctx context.Context, arg sqlc.GetUserPreferencesParams) (sqlc.UserPreference, error)
+	UpsertUserPreferences(ctx context.Context, arg sqlc.UpsertUserPreferencesParams) (sqlc.UserPreference, error)
+}
+
+type UserPreferencesRepository struct {
+	q userPreferencesQuerier
+}
+
+func NewUserPreferencesRepository(q userPreferencesQuerier) ports.UserPreferencesRepository {
+	return &UserPreferencesRepository{q: q}
+}
+
+func (r *UserPreferencesRepository) Get(ctx context.Context, userID uuid.UUID) (entity.UserPreferences, error) {
+	row, err := r.q.GetUserPreferences(ctx, sqlc.GetUserPreferencesParams{TenantID: tenantOf(ctx), UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return entity.UserPreferences{}, errs.ErrUserPreferencesNotFound
+	}
+	if err != nil {
+		return entity.UserPreferences{}, err
+	}
+	return toUserPreferencesEntity(row), nil
+}
+
+func (r *UserPreferencesRepository) Upsert(ctx context.Context, p entity.UserPreferences) (entity.UserPreferences, error) {
+	row, err := r.q.UpsertUserPreferences(ctx, sqlc.UpsertUserPreferencesParams{
+		TenantID: tenantOf(ctx),
+		UserID:   p.UserID,
+		Locale:   p.Locale,
+	})
+	if err != nil {
+		return entity.UserPreferences{}, err
+	}
+	return toUserPreferencesEntity(row), nil
+}
+
+func toUserPreferencesEntity(row sqlc.UserPreference) entity.UserPreferences {
+	return entity.UserPreferences{
+		UserID:    row.UserID,
+		Locale:    row.Locale,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/db/sqlc"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUserPreferencesQueries struct{ mock.Mock }
+
+func (m *mockUserPreferencesQueries) GetUserPreferences(ctx context.Context, arg sqlc.GetUserPreferencesParams) (sqlc.UserPreference, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.UserPreference), args.Error(1)
+}
+
+func (m *mockUserPreferencesQueries) UpsertUserPreferences(ctx context.Context, arg sqlc.UpsertUserPreferencesParams) (sqlc.UserPreference, error) {
+	args := m.Called(ctx, arg)
+	return args.Get(0).(sqlc.UserPreference), args.Error(1)
+}
+
+func TestUserPreferencesRepositoryGetNotFound(t *testing.T) {
+	uid := uuid.New()
+
+	mq := new(mockUserPreferencesQueries)
+	repo := NewUserPreferencesRepository(mq)
+
+	mq.On("GetUserPreferences", mock.Anything, sqlc.GetUserPreferencesParams{TenantID: "default", UserID: uid}).Return(sqlc.UserPreference{}, pgx.ErrNoRows)
+
+	_, err := repo.Get(defaultTenant(), uid)
+	require.ErrorIs(t, err, errs.ErrUserPreferencesNotFound)
+
+	mq.AssertExpectations(t)
+}
+
+func TestUserPreferencesRepositoryUpsert(t *testing.T) {
+	uid := uuid.New()
+
+	mq := new(mockUserPreferencesQueries)
+	repo := NewUserPreferencesRepository(mq)
+
+	mq.On("UpsertUserPreferences", mock.Anything, sqlc.UpsertUserPreferencesParams{TenantID: "shop", UserID: uid, Locale: "pt-BR"}).Return(sqlc.UserPreference{UserID: uid, Locale: "pt-BR"}, nil)
+
+	p, err := repo.Upsert(entity.WithTenant(defaultTenant(), "shop"), entity.UserPreferences{UserID: uid, Locale: "pt-BR"})
+	require.NoError(t, err)
+	require.Equal(t, "pt-BR", p.Locale)
+
+	mq.AssertExpectations(t)
+}
@@ -1,8 +1,11 @@
 package gateway
 
 import (
-	"fmt"
+	"context"
+	"sort"
+	"strings"
 
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/ports"
 )
@@ -13,7 +16,35 @@ func NewFakeGateway() ports.NotificationGateway {
 	return &FakeGateway{}
 }
 
-func (g *FakeGateway) Send(n entity.Notification) error {
-	fmt.Printf("📩 sending %s notification to %s: %s\n", n.Type, n.UserID, n.Message)
+// Send writes n to the log of ctx, which carries the recipient and type
+// of the notification when it comes from the use case.
+func (g *FakeGateway) Send(ctx context.Context, n entity.Notification) error {
+	logging.FromContext(ctx).Info("notification sent to console", "message", formatConsole(n))
 	return nil
 }
+
+// formatConsole renders the structured payload as a single console line.
+// A notification with only a message prints just the message.
+func formatConsole(n entity.Notification) string {
+	var b strings.Builder
+	if n.Title != "" {
+		b.WriteString("[" + n.Title + "] ")
+	}
+	b.WriteString(n.Message)
+	if n.ActionURL != "" {
+		b.WriteString(" -> " + n.ActionURL)
+	}
+	if len(n.Metadata) > 0 {
+		keys := make([]string, 0, len(n.Metadata))
+		for k := range n.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			pairs = append(pairs, k+"="+n.Metadata[k])
+		}
+		b.WriteString(" {" + strings.Join(pairs, ", ") + "}")
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"testing"
 
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
@@ -11,5 +12,16 @@ import (
 func TestFakeGatewaySendNoError(t *testing.T) {
 	g := NewFakeGateway()
 	n := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.Status, Message: "hello"}
-	require.NoError(t, g.Send(n))
+	require.NoError(t, g.Send(context.Background(), n))
+}
+
+func TestFormatConsoleStructuredPayload(t *testing.T) {
+	n := entity.Notification{
+		Title:     "Order shipped",
+		Message:   "Your order is on its way",
+		ActionURL: "https://example.com/orders/42",
+		Metadata:  map[string]string{"order_id": "42", "carrier": "ups"},
+	}
+	require.Equal(t, "[Order shipped] Your order is on its way -> https://example.com/orders/42 {carrier=ups, order_id=42}", formatConsole(n))
+	require.Equal(t, "hello", formatConsole(entity.Notification{Message: "hello"}))
 }
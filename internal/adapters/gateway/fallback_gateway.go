@@ -0,0 +1,31 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+)
+
+// FallbackGateway delivers through primary and, when the recipient cannot
+// be reached there (errs.ErrRecipientNotConnected), through fallback.
+type FallbackGateway struct {
+	primary  ports.NotificationGateway
+	fallback ports.NotificationGateway
+}
+
+func NewFallbackGateway(primary, fallback ports.NotificationGateway) ports.NotificationGateway {
+	return &FallbackGateway{primary: primary, fallback: fallback}
+}
+
+func (g *FallbackGateway) Send(ctx context.Context, n entity.Notification) error {
+	err := g.primary.Send(ctx, n)
+	if errors.Is(err, errs.ErrRecipientNotConnected) {
+		logging.FromContext(ctx).Debug("recipient not connected, falling back")
+		return g.fallback.Send(ctx, n)
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingGateway struct {
+	err  error
+	sent []uuid.UUID
+}
+
+func (g *recordingGateway) Send(_ context.Context, n entity.Notification) error {
+	g.sent = append(g.sent, n.ID)
+	return g.err
+}
+
+func TestFallbackGateway(t *testing.T) {
+	n := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Message: "hello"}
+	failure := errors.New("boom")
+
+	tests := []struct {
+		name         string
+		primaryErr   error
+		wantErr      error
+		wantFallback bool
+	}{
+		{name: "delivered by primary"},
+		{name: "recipient not connected", primaryErr: errs.ErrRecipientNotConnected, wantFallback: true},
+		{name: "primary failure", primaryErr: failure, wantErr: failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &recordingGateway{err: tt.primaryErr}
+			fallback := &recordingGateway{}
+
+			err := NewFallbackGateway(primary, fallback).Send(context.Background(), n)
+
+			require.ErrorIs(t, err, tt.wantErr)
+			require.Equal(t, []uuid.UUID{n.ID}, primary.sent)
+			require.Equal(t, tt.wantFallback, len(fallback.sent) == 1)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/tracing"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+)
+
+// TracedGateway sends through another gateway in a span named after it,
+// such as websocket.Send.
+type TracedGateway struct {
+	name string
+	next ports.NotificationGateway
+}
+
+func NewTracedGateway(name string, next ports.NotificationGateway) ports.NotificationGateway {
+	return &TracedGateway{name: name, next: next}
+}
+
+func (g *TracedGateway) Send(ctx context.Context, n entity.Notification) error {
+	ctx, span := tracing.Start(ctx, g.name+".Send", "gateway.name", g.name, "notification.id", n.ID)
+	err := g.next.Send(ctx, n)
+	if errors.Is(err, errs.ErrRecipientNotConnected) {
+		// Not a failure: the notification goes through another gateway.
+		span.SetAttributes("gateway.recipient_connected", false)
+		span.End(nil)
+		return err
+	}
+	span.End(err)
+	return err
+}
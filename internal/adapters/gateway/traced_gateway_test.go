@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedGateway(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	n := entity.Notification{ID: uuid.New()}
+	failure := errors.New("boom")
+	require.ErrorIs(t, NewTracedGateway("websocket", &recordingGateway{err: errs.ErrRecipientNotConnected}).Send(context.Background(), n), errs.ErrRecipientNotConnected)
+	require.ErrorIs(t, NewTracedGateway("console", &recordingGateway{err: failure}).Send(context.Background(), n), failure)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "websocket.Send", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, "console.Send", spans[1].Name())
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,277 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultPingInterval = 30 * time.Second
+	writeWait           = 10 * time.Second
+	// Clients only ever answer pings, so anything bigger is not ours.
+	maxClientMessage = 512
+)
+
+var errGatewayClosed = errors.New("websocket gateway is closed")
+
+// WebSocketGateway delivers notifications to the WebSocket sessions a user
+// has open, one per connected device or tab; every session gets every
+// notification. Send reports errs.ErrRecipientNotConnected when the user
+// has no session, so fan-out can fall back to another channel.
+//
+// Each session has a bounded send buffer. A session whose buffer is full
+// on Send is a slow consumer and is disconnected instead of holding up
+// delivery. Sessions that stop answering pings are disconnected as well.
+type WebSocketGateway struct {
+	mu           sync.RWMutex
+	sessions     map[recipient]map[*session]struct{}
+	buffer       int
+	pingInterval time.Duration
+	upgrader     websocket.Upgrader
+	closed       bool
+}
+
+// recipient is who a session belongs to. The same user id may exist in
+// several tenants, which must not see each other's notifications.
+type recipient struct {
+	tenant entity.TenantID
+	user   uuid.UUID
+}
+
+type session struct {
+	conn *websocket.Conn
+	send chan []byte
+
+	once        sync.Once
+	done        chan struct{}
+	closeCode   int
+	closeReason string
+}
+
+// wsMessage is the JSON text frame written for each notification.
+type wsMessage struct {
+	Event string         `json:"event"`
+	Data  wsNotification `json:"data"`
+}
+
+type wsNotification struct {
+	ID        uuid.UUID         `json:"id"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Type      string            `json:"type"`
+	Title     string            `json:"title,omitempty"`
+	Message   string            `json:"message"`
+	ActionURL string            `json:"action_url,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Locale    string            `json:"locale,omitempty"`
+	SentAt    *time.Time        `json:"sent_at,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// NewWebSocketGateway returns a gateway whose sessions buffer up to buffer
+// notifications and are pinged every pingInterval. A session that has not
+// answered within two intervals is disconnected.
+func NewWebSocketGateway(buffer int, pingInterval time.Duration) *WebSocketGateway {
+	if pingInterval <= 0 {
+		pingInterval = defaultPingInterval
+	}
+	return &WebSocketGateway{
+		sessions:     make(map[recipient]map[*session]struct{}),
+		buffer:       buffer,
+		pingInterval: pingInterval,
+	}
+}
+
+func (g *WebSocketGateway) Send(ctx context.Context, n entity.Notification) error {
+	payload, err := json.Marshal(wsMessage{
+		Event: "notification",
+		Data: wsNotification{
+			ID:        n.ID,
+			UserID:    n.UserID,
+			Type:      string(n.Type),
+			Title:     n.Title,
+			Message:   n.Message,
+			ActionURL: n.ActionURL,
+			Metadata:  n.Metadata,
+			Locale:    n.Locale,
+			SentAt:    n.SentAt,
+			CreatedAt: n.CreatedAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	to := recipient{tenant: n.TenantID, user: n.UserID}
+	var (
+		delivered int
+		lagging   []*session
+	)
+	g.mu.RLock()
+	for s := range g.sessions[to] {
+		select {
+		case s.send <- payload:
+			delivered++
+		default:
+			lagging = append(lagging, s)
+		}
+	}
+	g.mu.RUnlock()
+
+	log := logging.FromContext(ctx)
+	for _, s := range lagging {
+		log.Warn("disconnecting slow websocket session")
+		g.disconnect(to, s, websocket.CloseTryAgainLater, "slow consumer")
+	}
+	if delivered == 0 {
+		return errs.ErrRecipientNotConnected
+	}
+	log.Info("notification sent over websocket", "sessions", delivered)
+	return nil
+}
+
+// Accept upgrades the request to a WebSocket session for userID, in the
+// tenant of the request's context, and serves it until the connection
+// ends. When the upgrade fails a response has already been written to w.
+func (g *WebSocketGateway) Accept(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error {
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return err
+	}
+
+	to := recipient{tenant: entity.TenantOf(r.Context()), user: userID}
+	s := newSession(conn, g.buffer)
+	g.register(to, s)
+	go g.writePump(to, s)
+	g.readPump(to, s)
+	return nil
+}
+
+func newSession(conn *websocket.Conn, buffer int) *session {
+	return &session{conn: conn, send: make(chan []byte, buffer), done: make(chan struct{})}
+}
+
+// close asks the session's write pump to say goodbye with code and reason
+// and hang up. Only the first call has an effect.
+func (s *session) close(code int, reason string) {
+	s.once.Do(func() {
+		s.closeCode, s.closeReason = code, reason
+		close(s.done)
+	})
+}
+
+func (g *WebSocketGateway) register(to recipient, s *session) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		s.close(websocket.CloseGoingAway, "server shutting down")
+		return
+	}
+	if g.sessions[to] == nil {
+		g.sessions[to] = make(map[*session]struct{})
+	}
+	g.sessions[to][s] = struct{}{}
+}
+
+// Close disconnects every session, and any accepted afterwards, with a
+// going away close frame so clients reconnect to another instance. Send
+// then reports errs.ErrRecipientNotConnected for everyone.
+func (g *WebSocketGateway) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.closed = true
+	for to, sessions := range g.sessions {
+		for s := range sessions {
+			s.close(websocket.CloseGoingAway, "server shutting down")
+		}
+		delete(g.sessions, to)
+	}
+}
+
+// Check reports whether the gateway still accepts sessions, which it does
+// until Close.
+func (g *WebSocketGateway) Check(context.Context) error {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if g.closed {
+		return errGatewayClosed
+	}
+	return nil
+}
+
+// disconnect removes the session from the registry before closing it, so
+// Send never counts a session that is going away as a delivery.
+func (g *WebSocketGateway) disconnect(to recipient, s *session, code int, reason string) {
+	g.mu.Lock()
+	if sessions := g.sessions[to]; sessions != nil {
+		delete(sessions, s)
+		if len(sessions) == 0 {
+			delete(g.sessions, to)
+		}
+	}
+	g.mu.Unlock()
+
+	s.close(code, reason)
+}
+
+// readPump discards whatever the client sends and keeps the read deadline
+// moving on each pong. A missed pong or a closed connection ends the
+// session.
+func (g *WebSocketGateway) readPump(to recipient, s *session) {
+	defer g.disconnect(to, s, websocket.CloseNormalClosure, "")
+
+	pongWait := 2 * g.pingInterval
+	s.conn.SetReadLimit(maxClientMessage)
+	s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		if _, _, err := s.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump is the only writer of the connection besides close frames. It
+// owns closing the connection, which in turn ends readPump.
+func (g *WebSocketGateway) writePump(to recipient, s *session) {
+	ping := time.NewTicker(g.pingInterval)
+	defer func() {
+		ping.Stop()
+		s.conn.Close()
+	}()
+
+	for {
+		select {
+		case <-s.done:
+			s.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(s.closeCode, s.closeReason), time.Now().Add(writeWait))
+			return
+		case payload := <-s.send:
+			s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := s.conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				g.disconnect(to, s, websocket.CloseNormalClosure, "")
+				return
+			}
+		case <-ping.C:
+			s.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := s.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				g.disconnect(to, s, websocket.CloseNormalClosure, "")
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebSocketServer(t *testing.T, g *WebSocketGateway, userID uuid.UUID) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Accept(w, r.WithContext(entity.WithTenant(r.Context(), entity.DefaultTenant)), userID)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func connected(g *WebSocketGateway, userID uuid.UUID) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.sessions[recipient{tenant: entity.DefaultTenant, user: userID}])
+}
+
+func TestWebSocketGatewayDeliversToEverySession(t *testing.T) {
+	g := NewWebSocketGateway(8, time.Minute)
+	userID := uuid.New()
+	url := newWebSocketServer(t, g, userID)
+
+	phone, laptop := dial(t, url), dial(t, url)
+	require.Eventually(t, func() bool { return connected(g, userID) == 2 }, time.Second, 5*time.Millisecond)
+
+	other := entity.Notification{ID: uuid.New(), TenantID: "shop", UserID: userID, Message: "not yours"}
+	require.ErrorIs(t, g.Send(context.Background(), other), errs.ErrRecipientNotConnected)
+
+	n := entity.Notification{ID: uuid.New(), TenantID: entity.DefaultTenant, UserID: userID, Type: entity.News, Title: "Hi", Message: "hello"}
+	require.NoError(t, g.Send(context.Background(), n))
+
+	for _, conn := range []*websocket.Conn{phone, laptop} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var msg wsMessage
+		require.NoError(t, json.Unmarshal(data, &msg))
+		require.Equal(t, "notification", msg.Event)
+		require.Equal(t, n.ID, msg.Data.ID)
+		require.Equal(t, "Hi", msg.Data.Title)
+		require.Equal(t, "hello", msg.Data.Message)
+	}
+}
+
+func TestWebSocketGatewayNotConnected(t *testing.T) {
+	g := NewWebSocketGateway(8, time.Minute)
+
+	err := g.Send(context.Background(), entity.Notification{ID: uuid.New(), UserID: uuid.New(), Message: "hello"})
+	require.ErrorIs(t, err, errs.ErrRecipientNotConnected)
+}
+
+func TestWebSocketGatewayDisconnectsSlowConsumer(t *testing.T) {
+	g := NewWebSocketGateway(1, time.Minute)
+	userID := uuid.New()
+	// No pumps: nothing drains the buffer.
+	slow := newSession(nil, 1)
+	g.register(recipient{tenant: entity.DefaultTenant, user: userID}, slow)
+
+	n := entity.Notification{ID: uuid.New(), TenantID: entity.DefaultTenant, UserID: userID, Message: "hello"}
+	require.NoError(t, g.Send(context.Background(), n))
+	require.ErrorIs(t, g.Send(context.Background(), n), errs.ErrRecipientNotConnected)
+
+	require.Zero(t, connected(g, userID))
+	require.Equal(t, websocket.CloseTryAgainLater, slow.closeCode)
+	select {
+	case <-slow.done:
+	default:
+		t.Fatal("slow session was not closed")
+	}
+}
+
+func TestWebSocketGatewayPingsAndDropsUnresponsiveSessions(t *testing.T) {
+	g := NewWebSocketGateway(8, 20*time.Millisecond)
+	userID := uuid.New()
+	url := newWebSocketServer(t, g, userID)
+
+	pinged := make(chan struct{}, 1)
+	conn := dial(t, url)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		// Never answer, as a stalled client would.
+		return nil
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("no ping received")
+	}
+	require.Eventually(t, func() bool { return connected(g, userID) == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWebSocketGatewayCloseSaysGoingAway(t *testing.T) {
+	g := NewWebSocketGateway(8, time.Minute)
+	userID := uuid.New()
+	url := newWebSocketServer(t, g, userID)
+
+	conn := dial(t, url)
+	require.Eventually(t, func() bool { return connected(g, userID) == 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, g.Check(context.Background()))
+
+	g.Close()
+	require.Error(t, g.Check(context.Background()))
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+
+	n := entity.Notification{ID: uuid.New(), TenantID: entity.DefaultTenant, UserID: userID, Message: "hello"}
+	require.ErrorIs(t, g.Send(context.Background(), n), errs.ErrRecipientNotConnected)
+
+	late := dial(t, url)
+	late.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = late.ReadMessage()
+	require.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+}
@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const principalContextKey = "auth.principal"
+
+// apiKeyPrefix tells API keys apart from user tokens in the Authorization
+// header.
+const apiKeyPrefix = "mk_"
+
+// Authenticator resolves the secret of an API key to the key, returning
+// errs.ErrUnauthorized when it does not match an active key.
+type Authenticator interface {
+	Authenticate(ctx context.Context, secret string) (entity.APIKey, error)
+}
+
+// TokenVerifier resolves an end-user bearer token to the user it was
+// issued for, returning errs.ErrUnauthorized when it is not valid and
+// errs.ErrUnavailable when it cannot tell for now.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (Principal, error)
+}
+
+// Principal is who a request was authenticated as: an API key, or an end
+// user identified by UserID when authenticated with a user token. Either
+// way it acts on behalf of TenantID.
+type Principal struct {
+	APIKey   *entity.APIKey
+	UserID   uuid.UUID
+	TenantID entity.TenantID
+	Scopes   []entity.Scope
+}
+
+// IsUser reports whether the principal is an end user rather than an API
+// key.
+func (p Principal) IsUser() bool {
+	return p.UserID != uuid.Nil
+}
+
+// HasScope reports whether the principal was granted s, directly or
+// through entity.ScopeAdmin.
+func (p Principal) HasScope(s entity.Scope) bool {
+	for _, granted := range p.Scopes {
+		if granted == s || granted == entity.ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Bearer authenticates requests by their "Authorization: Bearer" header
+// and rejects those without valid credentials with 401. API keys are
+// checked with keys; any other token with tokens, when it is set. The
+// principal is available to later handlers through PrincipalFrom, and its
+// tenant through entity.TenantOf on the request context.
+func Bearer(keys Authenticator, tokens TokenVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			unauthorized(c)
+			return
+		}
+
+		var (
+			p   Principal
+			err error
+		)
+		if strings.HasPrefix(token, apiKeyPrefix) || tokens == nil {
+			var k entity.APIKey
+			k, err = keys.Authenticate(c.Request.Context(), token)
+			p = Principal{APIKey: &k, TenantID: k.TenantID, Scopes: k.Scopes}
+			if p.TenantID == "" {
+				p.TenantID = entity.DefaultTenant
+			}
+		} else {
+			p, err = tokens.Verify(c.Request.Context(), token)
+		}
+		if errors.Is(err, errs.ErrUnauthorized) {
+			unauthorized(c)
+			return
+		}
+		if err != nil {
+			// The cause may name internal hosts; only its kind is answered.
+			logging.FromContext(c.Request.Context()).Error("authentication failed", "err", err)
+			problem.Abort(c, errs.As(err))
+			return
+		}
+		// Users are never given a tenant they were not issued for.
+		if p.TenantID == "" {
+			unauthorized(c)
+			return
+		}
+
+		c.Set(principalContextKey, p)
+		ctx := logging.With(entity.WithTenant(c.Request.Context(), p.TenantID), "tenant_id", p.TenantID)
+		if p.APIKey != nil {
+			ctx = logging.With(ctx, "api_key_id", p.APIKey.ID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// QueryToken lets clients that cannot set headers, such as a browser's
+// EventSource or WebSocket, send their bearer token in the query parameter
+// param instead. It must run before Bearer and only on the routes that
+// need it. The parameter is dropped from the request so it is not passed
+// on, and API keys are refused in it: URLs end up in proxy logs and
+// browser history, so only short-lived user tokens belong there.
+func QueryToken(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		token := strings.TrimSpace(query.Get(param))
+		if token == "" || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		if strings.HasPrefix(token, apiKeyPrefix) {
+			unauthorized(c)
+			return
+		}
+		query.Del(param)
+		c.Request.URL.RawQuery = query.Encode()
+		c.Request.Header.Set("Authorization", "Bearer "+token)
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose principal is not an API key granted
+// scope with 403. End users are refused whatever their token claims, as
+// scopes here are only granted to keys. It must run after Bearer.
+func RequireScope(scope entity.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := PrincipalFrom(c)
+		if !ok {
+			unauthorized(c)
+			return
+		}
+		if p.IsUser() || !p.HasScope(scope) {
+			forbidden(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelf guards routes under a user_id path parameter. End users may
+// only reach their own user_id unless they were granted the admin scope;
+// API keys need scope. It must run after Bearer.
+func RequireSelf(scope entity.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := PrincipalFrom(c)
+		if !ok {
+			unauthorized(c)
+			return
+		}
+
+		if p.IsUser() {
+			userID, err := uuid.Parse(c.Param("user_id"))
+			if (err != nil || userID != p.UserID) && !p.HasScope(entity.ScopeAdmin) {
+				forbidden(c, entity.ScopeAdmin)
+				return
+			}
+		} else if !p.HasScope(scope) {
+			forbidden(c, scope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// PrincipalFrom returns who the request was authenticated as.
+func PrincipalFrom(c *gin.Context) (Principal, bool) {
+	v, ok := c.Get(principalContextKey)
+	if !ok {
+		return Principal{}, false
+	}
+	p, ok := v.(Principal)
+	return p, ok
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+func unauthorized(c *gin.Context) {
+	c.Header("WWW-Authenticate", "Bearer")
+	problem.Abort(c, errs.ErrUnauthorized)
+}
+
+func forbidden(c *gin.Context, scope entity.Scope) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="insufficient_scope", scope="%s"`, scope))
+	problem.Abort(c, fmt.Errorf("%w: %s", errs.ErrForbidden, scope))
+}
@@ -0,0 +1,268 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth/authtest"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthenticator map[string]entity.APIKey
+
+func (f fakeAuthenticator) Authenticate(_ context.Context, secret string) (entity.APIKey, error) {
+	k, ok := f[secret]
+	if !ok {
+		return entity.APIKey{}, errs.ErrUnauthorized
+	}
+	return k, nil
+}
+
+var keys = fakeAuthenticator{
+	"mk_sender": {Name: "sender", Scopes: []entity.Scope{entity.ScopeNotificationsSend}},
+	"mk_reader": {Name: "reader", Scopes: []entity.Scope{entity.ScopeNotificationsRead}},
+	"mk_admin":  {Name: "admin", Scopes: []entity.Scope{entity.ScopeAdmin}},
+	"mk_shop":   {Name: "shop", TenantID: "shop", Scopes: []entity.Scope{entity.ScopeNotificationsRead}},
+}
+
+func newRouter(tokens auth.TokenVerifier) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(auth.Bearer(keys, tokens))
+	r.POST("/send", auth.RequireScope(entity.ScopeNotificationsSend), func(c *gin.Context) {
+		p, _ := auth.PrincipalFrom(c)
+		c.String(http.StatusOK, p.APIKey.Name)
+	})
+	r.GET("/read", auth.RequireScope(entity.ScopeNotificationsRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/users/:user_id/notifications", auth.RequireSelf(entity.ScopeNotificationsRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/tenant", func(c *gin.Context) {
+		c.String(http.StatusOK, string(entity.TenantOf(c.Request.Context())))
+	})
+	return r
+}
+
+func serve(r *gin.Engine, method, path, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	tests := []struct {
+		name          string
+		method, path  string
+		authorization string
+		wantStatus    int
+	}{
+		{"missing header", http.MethodPost, "/send", "", http.StatusUnauthorized},
+		{"not bearer", http.MethodPost, "/send", "Basic mk_sender", http.StatusUnauthorized},
+		{"unknown key", http.MethodPost, "/send", "Bearer mk_unknown", http.StatusUnauthorized},
+		{"granted scope", http.MethodPost, "/send", "Bearer mk_sender", http.StatusOK},
+		{"missing scope", http.MethodGet, "/read", "Bearer mk_sender", http.StatusForbidden},
+		{"admin has every scope", http.MethodGet, "/read", "bearer mk_admin", http.StatusOK},
+		{"key reads any user", http.MethodGet, "/users/" + uuid.NewString() + "/notifications", "Bearer mk_reader", http.StatusOK},
+		{"key without read scope", http.MethodGet, "/users/" + uuid.NewString() + "/notifications", "Bearer mk_sender", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(newRouter(nil), tt.method, tt.path, tt.authorization)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusUnauthorized {
+				require.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestUserTokenAuthentication(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	other := authtest.NewTokenIssuer(t)
+	r := newRouter(issuer.Verifier(t))
+	userID := uuid.New()
+	own := "/users/" + userID.String() + "/notifications"
+
+	expired := issuer.Sign(t, jwt.MapClaims{
+		"iss": authtest.Issuer,
+		"aud": authtest.Audience,
+		"sub": userID.String(),
+		"exp": time.Now().Add(-time.Minute).Unix(),
+	})
+	wrongAudience := issuer.Sign(t, jwt.MapClaims{
+		"iss": authtest.Issuer,
+		"aud": "someone-else",
+		"sub": userID.String(),
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	notUUID := issuer.Sign(t, jwt.MapClaims{
+		"iss": authtest.Issuer,
+		"aud": authtest.Audience,
+		"sub": "alice",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{"own inbox", own, issuer.Token(t, userID), http.StatusOK},
+		{"someone else's inbox", "/users/" + uuid.NewString() + "/notifications", issuer.Token(t, userID), http.StatusForbidden},
+		{"admin reads anyone", "/users/" + uuid.NewString() + "/notifications", issuer.Token(t, userID, "admin"), http.StatusOK},
+		{"user token is not a key", "/read", issuer.Token(t, userID), http.StatusForbidden},
+		{"admin user token is not a key", "/read", issuer.Token(t, userID, "admin"), http.StatusForbidden},
+		{"expired", own, expired, http.StatusUnauthorized},
+		{"wrong audience", own, wrongAudience, http.StatusUnauthorized},
+		{"sub is not a user id", own, notUUID, http.StatusUnauthorized},
+		{"signed by another key", own, other.Token(t, userID), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, tt.path, "Bearer "+tt.token)
+			require.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestUserTokenCannotSend(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	token := issuer.Token(t, uuid.New(), "notifications:send", "admin")
+
+	w := serve(newRouter(issuer.Verifier(t)), http.MethodPost, "/send", "Bearer "+token)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestBearerSetsTenant(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	r := newRouter(issuer.Verifier(t))
+	userID := uuid.New()
+
+	games := issuer.Sign(t, jwt.MapClaims{
+		"iss":       authtest.Issuer,
+		"aud":       authtest.Audience,
+		"sub":       userID.String(),
+		"exp":       time.Now().Add(time.Minute).Unix(),
+		"tenant_id": "games",
+	})
+	noTenant := issuer.Sign(t, jwt.MapClaims{
+		"iss": authtest.Issuer,
+		"aud": authtest.Audience,
+		"sub": userID.String(),
+		"exp": time.Now().Add(time.Minute).Unix(),
+	})
+	invalid := issuer.Sign(t, jwt.MapClaims{
+		"iss":       authtest.Issuer,
+		"aud":       authtest.Audience,
+		"sub":       userID.String(),
+		"exp":       time.Now().Add(time.Minute).Unix(),
+		"tenant_id": "Not A Tenant",
+	})
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantTenant string
+	}{
+		{"key of a tenant", "mk_shop", http.StatusOK, "shop"},
+		{"key without tenant", "mk_reader", http.StatusOK, "default"},
+		{"user token with tenant", games, http.StatusOK, "games"},
+		{"user token with tenant claim", issuer.Token(t, userID), http.StatusOK, authtest.Tenant},
+		{"user token without tenant", noTenant, http.StatusUnauthorized, ""},
+		{"user token with invalid tenant", invalid, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, "/tenant", "Bearer "+tt.token)
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, tt.wantTenant, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUserTokenWithKeySetDown(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+	r := newRouter(auth.NewJWTVerifier(auth.NewJWKSURL(srv.URL, time.Hour), authtest.Issuer, authtest.Audience))
+	userID := uuid.New()
+
+	w := serve(r, http.MethodGet, "/users/"+userID.String()+"/notifications", "Bearer "+issuer.Token(t, userID))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.Contains(t, w.Body.String(), `"code":"unavailable"`)
+	require.NotContains(t, w.Body.String(), "jwks")
+	require.NotContains(t, w.Body.String(), srv.URL)
+}
+
+func TestUserTokenRejectedWithoutVerifier(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	userID := uuid.New()
+
+	w := serve(newRouter(nil), http.MethodGet, "/users/"+userID.String()+"/notifications", "Bearer "+issuer.Token(t, userID))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestQueryToken(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(auth.QueryToken("access_token"), auth.Bearer(keys, issuer.Verifier(t)))
+	r.GET("/users/:user_id/notifications/stream", auth.RequireSelf(entity.ScopeNotificationsRead), func(c *gin.Context) {
+		c.String(http.StatusOK, c.Request.URL.RawQuery)
+	})
+	userID := uuid.New()
+	path := "/users/" + userID.String() + "/notifications/stream"
+
+	tests := []struct {
+		name          string
+		query         string
+		authorization string
+		wantStatus    int
+	}{
+		{"user token in query", "?access_token=" + issuer.Token(t, userID) + "&last_event_id=1", "", http.StatusOK},
+		{"invalid token in query", "?access_token=garbage", "", http.StatusUnauthorized},
+		{"API key in query", "?access_token=mk_reader", "", http.StatusUnauthorized},
+		{"header wins over query", "?access_token=garbage", "Bearer " + issuer.Token(t, userID), http.StatusOK},
+		{"no token", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(r, http.MethodGet, path+tt.query, tt.authorization)
+			require.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK && tt.authorization == "" {
+				require.Equal(t, "last_event_id=1", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestBearerIgnoresQueryToken(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	userID := uuid.New()
+
+	w := serve(newRouter(issuer.Verifier(t)), http.MethodGet, "/users/"+userID.String()+"/notifications?access_token="+issuer.Token(t, userID), "")
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/tracing"
+	"golang.org/x/sync/singleflight"
+)
+
+// errUnknownKey is returned by key sets for key ids they do not have. Any
+// other error means the keys could not be loaded.
+var errUnknownKey = errors.New("unknown signing key")
+
+// KeySet finds the public key a token was signed with by its key id.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// jwk is one JSON Web Key (RFC 7517). Only public RSA, EC and Ed25519 keys
+// are supported.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses a JSON Web Key Set into public keys by key id. Keys
+// that are not meant for signatures or of an unsupported type are skipped.
+func ParseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("ed25519 key has %d bytes", len(x))
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+// StaticKeySet is a fixed set of keys, such as one read from a file.
+type StaticKeySet map[string]crypto.PublicKey
+
+// NewJWKSFile reads a JSON Web Key Set from path.
+func NewJWKSFile(path string) (StaticKeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJWKS(data)
+}
+
+func (s StaticKeySet) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// minJWKSRefresh keeps tokens with unknown key ids from making RemoteKeySet
+// fetch the set over and over.
+const minJWKSRefresh = time.Minute
+
+// RemoteKeySet is a JSON Web Key Set served at a URL, such as an OIDC
+// provider's jwks_uri. It is fetched on first use and again after refresh,
+// or sooner when a token names a key it does not have yet, which is how
+// providers roll out new keys. Concurrent requests share one fetch, and
+// after a failed one the set is not fetched again for minJWKSRefresh.
+type RemoteKeySet struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	fetches singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetched   time.Time
+	attempted time.Time
+	err       error
+}
+
+func NewJWKSURL(url string, refresh time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(http.DefaultTransport)},
+		refresh: max(refresh, minJWKSRefresh),
+	}
+}
+
+func (s *RemoteKeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	fresh := time.Since(s.fetched) < s.refresh
+	backingOff := time.Since(s.attempted) < minJWKSRefresh
+	lastErr := s.err
+	s.mu.Unlock()
+
+	if ok && (fresh || backingOff) {
+		return key, nil
+	}
+	if backingOff {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("%w %q", errUnknownKey, kid)
+	}
+
+	keys, err := s.update(ctx)
+	if err != nil {
+		if ok {
+			// Keep serving the key we know while the provider is down.
+			return key, nil
+		}
+		return nil, err
+	}
+	if key, ok = keys[kid]; !ok {
+		return nil, fmt.Errorf("%w %q", errUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// update fetches the set and records the attempt, whether it failed or
+// not. The fetch outlives the request that started it, as others may be
+// waiting for it too; the client's timeout bounds it.
+func (s *RemoteKeySet) update(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	v, err, _ := s.fetches.Do(s.url, func() (any, error) {
+		keys, err := s.fetch(context.WithoutCancel(ctx))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.attempted, s.err = time.Now(), err
+		if err != nil {
+			return nil, err
+		}
+		s.keys, s.fetched = keys, s.attempted
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(map[string]crypto.PublicKey), nil
+}
+
+func (s *RemoteKeySet) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching jwks: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("fetching jwks: %w", err)
+	}
+	return ParseJWKS(data)
+}
@@ -0,0 +1,86 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth/authtest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSFile(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, issuer.JWKS(), 0o600))
+
+	keys, err := auth.NewJWKSFile(path)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	p, err := auth.NewJWTVerifier(keys, authtest.Issuer, authtest.Audience).Verify(context.Background(), issuer.Token(t, userID, "notifications:read"))
+	require.NoError(t, err)
+	require.Equal(t, userID, p.UserID)
+	require.True(t, p.IsUser())
+}
+
+func TestJWKSURL(t *testing.T) {
+	issuer := authtest.NewTokenIssuer(t)
+	srv := issuer.Server(t)
+
+	keys := auth.NewJWKSURL(srv.URL, time.Hour)
+	verifier := auth.NewJWTVerifier(keys, "", "")
+
+	userID := uuid.New()
+	p, err := verifier.Verify(context.Background(), issuer.Token(t, userID))
+	require.NoError(t, err)
+	require.Equal(t, userID, p.UserID)
+
+	// The set is cached: the key is still found with the provider down.
+	srv.Close()
+	_, err = verifier.Verify(context.Background(), issuer.Token(t, userID))
+	require.NoError(t, err)
+}
+
+func TestJWKSURLSharesFetchesAndBacksOff(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+	keys := auth.NewJWKSURL(srv.URL, time.Hour)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(context.Background(), "kid")
+			require.Error(t, err)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.EqualValues(t, 1, hits.Load(), "concurrent lookups share one fetch")
+
+	_, err := keys.Key(context.Background(), "kid")
+	require.ErrorContains(t, err, "500")
+	require.EqualValues(t, 1, hits.Load(), "a failed fetch is not retried right away")
+}
+
+func TestParseJWKSRejectsGarbage(t *testing.T) {
+	_, err := auth.ParseJWKS([]byte(`{"keys": [{"kty": "RSA", "kid": "a", "n": "!!", "e": "AQAB"}]}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// signingMethods are the asymmetric algorithms accepted for user tokens.
+// Symmetric ones are refused so that a public key can never be used as an
+// HMAC secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWTVerifier verifies end-user JWTs, such as OIDC ID or access tokens,
+// against the keys of a KeySet. The sub claim must be the user's id, and
+// the tenant_id claim the tenant the user belongs to.
+type JWTVerifier struct {
+	keys     KeySet
+	issuer   string
+	audience string
+}
+
+// NewJWTVerifier returns a verifier for tokens signed with keys. Tokens
+// must carry the given issuer and audience when those are not empty.
+func NewJWTVerifier(keys KeySet, issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{keys: keys, issuer: issuer, audience: audience}
+}
+
+// userClaims are the claims read from a user token. Scopes come from the
+// space separated scope claim (RFC 8693) or the scp list some providers
+// use instead. Tokens without a tenant_id are refused rather than given a
+// tenant.
+type userClaims struct {
+	jwt.RegisteredClaims
+	Scope    string   `json:"scope,omitempty"`
+	Scp      []string `json:"scp,omitempty"`
+	TenantID string   `json:"tenant_id,omitempty"`
+}
+
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	var (
+		claims userClaims
+		keyErr error
+	)
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil && !errors.Is(err, errUnknownKey) {
+			keyErr = err
+		}
+		return key, err
+	}, opts...)
+	if keyErr != nil {
+		// The token may well be valid; it is the provider that is down.
+		return Principal{}, fmt.Errorf("%w: %w", errs.ErrUnavailable, keyErr)
+	}
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", errs.ErrUnauthorized, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: sub is not a user id", errs.ErrUnauthorized)
+	}
+
+	if claims.TenantID == "" {
+		return Principal{}, fmt.Errorf("%w: token has no tenant_id", errs.ErrUnauthorized)
+	}
+	tenant := entity.TenantID(claims.TenantID)
+	if err := tenant.Validate(); err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", errs.ErrUnauthorized, err)
+	}
+
+	p := Principal{UserID: userID, TenantID: tenant}
+	for _, s := range append(strings.Fields(claims.Scope), claims.Scp...) {
+		p.Scopes = append(p.Scopes, entity.Scope(s))
+	}
+	return p, nil
+}
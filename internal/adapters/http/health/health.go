@@ -0,0 +1,126 @@
+// Package health serves the liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Check is a dependency the service needs to serve traffic, such as the
+// database. Run reports why it is unusable.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Response is the body of both probes. Checks is only set by readiness.
+type Response struct {
+	Status string                 `json:"status" example:"ok"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// CheckResult is the outcome of one check. Why a check failed is only
+// logged, as the probes are served without authentication.
+type CheckResult struct {
+	Status     string  `json:"status" example:"ok"`
+	DurationMS float64 `json:"duration_ms" example:"1.27"`
+}
+
+// Probes answers liveness from the process alone and readiness from its
+// checks.
+type Probes struct {
+	checks   []Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// New returns probes whose readiness runs checks, giving each up to
+// timeout.
+func New(timeout time.Duration, checks ...Check) *Probes {
+	return &Probes{checks: checks, timeout: timeout}
+}
+
+// Drain makes readiness fail from now on, so that load balancers stop
+// sending traffic to a server that is shutting down.
+func (p *Probes) Drain() {
+	p.draining.Store(true)
+}
+
+// Livez answers 200 as long as the process serves HTTP. It checks no
+// dependency, so that an outage of one does not get the process restarted.
+//
+// @Summary Liveness probe
+// @Description Answers 200 as long as the process serves HTTP, without checking any dependency. /health is kept as an alias for existing clients.
+// @Tags health
+// @Produce json
+// @Success 200 {object} Response
+// @Router /livez [get]
+// @Router /health [get]
+func (p *Probes) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, Response{Status: StatusOK})
+}
+
+// Readyz runs every check concurrently and answers 503 when any of them
+// fails, or without running them while draining.
+//
+// @Summary Readiness probe
+// @Description Checks the database, the migrations and the WebSocket gateway. Answers 503 with status fail when any check fails, or with status draining while the server shuts down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} Response
+// @Failure 503 {object} Response
+// @Router /readyz [get]
+func (p *Probes) Readyz(c *gin.Context) {
+	if p.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, Response{Status: StatusDraining})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), p.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(p.checks))
+	var wg sync.WaitGroup
+	for i, check := range p.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	resp := Response{Status: StatusOK, Checks: make(map[string]CheckResult, len(p.checks))}
+	status := http.StatusOK
+	for i, check := range p.checks {
+		if results[i].Status != StatusOK {
+			resp.Status = StatusFail
+			status = http.StatusServiceUnavailable
+		}
+		resp.Checks[check.Name] = results[i]
+	}
+	c.JSON(status, resp)
+}
+
+func run(ctx context.Context, check Check) CheckResult {
+	start := time.Now()
+	err := check.Run(ctx)
+	res := CheckResult{Status: StatusOK, DurationMS: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		logging.FromContext(ctx).Warn("readiness check failed", "check", check.Name, "err", err)
+		res.Status = StatusFail
+	}
+	return res
+}
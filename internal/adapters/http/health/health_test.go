@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(p *Probes) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/livez", p.Livez)
+	r.GET("/readyz", p.Readyz)
+	return r
+}
+
+func get(t *testing.T, r http.Handler, path string) (int, Response) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func ok(context.Context) error { return nil }
+
+func TestReadyzReportsEveryCheck(t *testing.T) {
+	r := newRouter(New(time.Second,
+		Check{Name: "database", Run: ok},
+		Check{Name: "migrations", Run: func(context.Context) error { return errors.New("schema version is 17, want 18") }},
+	))
+
+	code, resp := get(t, r, "/readyz")
+
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusFail, resp.Status)
+	require.Equal(t, StatusOK, resp.Checks["database"].Status)
+	require.Equal(t, StatusFail, resp.Checks["migrations"].Status)
+}
+
+func TestReadyzHidesWhyChecksFail(t *testing.T) {
+	r := newRouter(New(time.Second,
+		Check{Name: "database", Run: func(context.Context) error { return errors.New("dial tcp 10.0.0.5:5432: connection refused") }},
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	require.NotContains(t, w.Body.String(), "10.0.0.5")
+	require.NotContains(t, w.Body.String(), "error")
+}
+
+func TestReadyzBoundsChecksByTimeout(t *testing.T) {
+	slow := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	r := newRouter(New(10*time.Millisecond, Check{Name: "database", Run: slow}))
+
+	code, resp := get(t, r, "/readyz")
+
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusFail, resp.Checks["database"].Status)
+	require.GreaterOrEqual(t, resp.Checks["database"].DurationMS, 10.0)
+}
+
+func TestReadyzFailsWhileDraining(t *testing.T) {
+	p := New(time.Second, Check{Name: "database", Run: ok})
+	r := newRouter(p)
+
+	code, resp := get(t, r, "/readyz")
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, resp.Status)
+	require.Equal(t, StatusOK, resp.Checks["database"].Status)
+
+	p.Drain()
+
+	code, resp = get(t, r, "/readyz")
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusDraining, resp.Status)
+
+	code, resp = get(t, r, "/livez")
+	require.Equal(t, http.StatusOK, code, "draining is no reason to restart")
+	require.Equal(t, StatusOK, resp.Status)
+}
@@ -0,0 +1,45 @@
+// Package httpmetrics counts and times HTTP requests for Prometheus.
+package httpmetrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// unmatchedRoute labels requests that matched no route, so that scanning
+// for random paths does not create new series.
+const unmatchedRoute = "unmatched"
+
+// Middleware registers modak_http_requests_total and
+// modak_http_request_duration_seconds in reg and records every request in
+// them by method, route pattern, such as /v1/templates/:id, and status.
+func Middleware(reg prometheus.Registerer) gin.HandlerFunc {
+	requests := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "modak",
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route and status.",
+	}, []string{"method", "route", "status"})
+	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "modak",
+		Name:      "http_request_duration_seconds",
+		Help:      "Time spent serving HTTP requests, by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	reg.MustRegister(requests, durations)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		labels := prometheus.Labels{"method": c.Request.Method, "route": route, "status": strconv.Itoa(c.Writer.Status())}
+		requests.With(labels).Inc()
+		durations.With(labels).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,268 @@
+// Package problem reports errors as RFC 9457 problem details, served as
+// application/problem+json.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const ContentType = "application/problem+json"
+
+// typePrefix turns error codes into problem type URIs. They identify the
+// type of problem and are not meant to be dereferenced.
+const typePrefix = "urn:modak:problem:"
+
+// Problem describes an error. code is the stable code of the errs.Error
+// behind it and type is built from it; title is the same for every
+// problem of a type, while detail explains this occurrence. errors lists
+// the request fields at fault when validation failed.
+type Problem struct {
+	Type       string       `json:"type" example:"urn:modak:problem:invalid_request"`
+	Title      string       `json:"title" example:"invalid request"`
+	Status     int          `json:"status" example:"400"`
+	Detail     string       `json:"detail,omitempty" example:"invalid request: user_id is required"`
+	Instance   string       `json:"instance,omitempty" example:"/v1/notifications/send"`
+	Code       string       `json:"code" example:"invalid_request"`
+	Errors     []FieldError `json:"errors,omitempty"`
+	RetryAfter int          `json:"retry_after,omitempty" example:"12"`
+}
+
+// FieldError says what is wrong with one field of a request. field is
+// the JSON name of a body field, with indexes for list items, or the
+// name of a path or query parameter.
+type FieldError struct {
+	Field   string `json:"field" example:"user_id"`
+	Message string `json:"message" example:"is required"`
+}
+
+var statuses = map[errs.Kind]int{
+	errs.KindInvalid:       http.StatusBadRequest,
+	errs.KindUnauthorized:  http.StatusUnauthorized,
+	errs.KindForbidden:     http.StatusForbidden,
+	errs.KindNotFound:      http.StatusNotFound,
+	errs.KindConflict:      http.StatusConflict,
+	errs.KindUnprocessable: http.StatusUnprocessableEntity,
+	errs.KindRateLimited:   http.StatusTooManyRequests,
+	errs.KindUnavailable:   http.StatusServiceUnavailable,
+}
+
+// New returns the problem err stands for. Errors that do not wrap an
+// errs.Error are reported as internal errors without their text, which
+// may come from the database or other dependencies.
+func New(err error) Problem {
+	e := errs.As(err)
+	status, ok := statuses[e.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+
+	p := Problem{Type: typePrefix + e.Code, Title: e.Message, Status: status, Code: e.Code}
+	if e != errs.ErrInternal && err.Error() != e.Message {
+		p.Detail = err.Error()
+	}
+
+	var verr *errs.ValidationError
+	if errors.As(err, &verr) {
+		p.Errors = make([]FieldError, len(verr.Fields))
+		for i, f := range verr.Fields {
+			p.Errors[i] = FieldError{Field: f.Field, Message: f.Message}
+		}
+	}
+	return p
+}
+
+// Abort writes the problem for err and stops the handler chain.
+func Abort(c *gin.Context, err error) {
+	write(c, New(err))
+	c.Abort()
+}
+
+// AbortWith writes p and stops the handler chain.
+func AbortWith(c *gin.Context, p Problem) {
+	write(c, p)
+	c.Abort()
+}
+
+func write(c *gin.Context, p Problem) {
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ContentType)
+	c.JSON(p.Status, p)
+}
+
+// Bind records that the request could not be bound, for instance by
+// c.ShouldBindJSON, so that Handler answers it with 400 and the fields at
+// fault.
+func Bind(c *gin.Context, err error) {
+	c.Error(Validation(err))
+}
+
+// Handler writes the problem for the last error handlers recorded with
+// c.Error, unless they already wrote a response. Handlers report errors
+// this way instead of writing them, so that every error is logged and
+// shaped the same.
+func Handler() gin.HandlerFunc {
+	useJSONFieldNames()
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+		p := New(err)
+		log := logging.FromContext(c.Request.Context())
+		if p.Status >= http.StatusInternalServerError {
+			log.Error("request failed", "code", p.Code, "err", err)
+		} else {
+			log.Info("request rejected", "code", p.Code, "err", err)
+		}
+		write(c, p)
+	}
+}
+
+// Validation turns a binding error into an *errs.ValidationError listing
+// the fields at fault, when it can tell which they are, or else an
+// errs.ErrInvalidRequest.
+func Validation(err error) error {
+	var (
+		verrs     validator.ValidationErrors
+		typeErr   *json.UnmarshalTypeError
+		syntaxErr *json.SyntaxError
+	)
+	switch {
+	case errors.As(err, &verrs):
+		fields := make([]errs.FieldError, len(verrs))
+		for i, fe := range verrs {
+			fields[i] = errs.FieldError{Field: fieldName(fe), Message: fieldMessage(fe)}
+		}
+		return &errs.ValidationError{Fields: fields}
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return errs.InvalidField(typeErr.Field, "must be a "+jsonType(typeErr.Type))
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return fmt.Errorf("%w: body is not valid JSON", errs.ErrInvalidRequest)
+	case errors.Is(err, io.EOF):
+		return fmt.Errorf("%w: body is required", errs.ErrInvalidRequest)
+	default:
+		return fmt.Errorf("%w: %v", errs.ErrInvalidRequest, err)
+	}
+}
+
+// fieldName strips the name of the request struct from the namespace of
+// fe, leaving the path of the field within the body.
+func fieldName(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if _, field, ok := strings.Cut(ns, "."); ok {
+		return field
+	}
+	return ns
+}
+
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		if isCollection(fe) {
+			return "must have at least " + fe.Param() + " items"
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if isCollection(fe) {
+			return "must have at most " + fe.Param() + " items"
+		}
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "uuid":
+		return "must be a UUID"
+	case "url", "http_url":
+		return "must be a URL"
+	case "required_without":
+		return "is required without " + snakeCase(fe.Param())
+	case "excluded_with":
+		return "must not be combined with " + snakeCase(fe.Param())
+	default:
+		if fe.Param() != "" {
+			return "must satisfy " + fe.Tag() + "=" + fe.Param()
+		}
+		return "must satisfy " + fe.Tag()
+	}
+}
+
+// snakeCase turns the Go name of a field, which cross-field tags refer to,
+// into the JSON name request DTOs give it.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(name[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isCollection(fe validator.FieldError) bool {
+	switch fe.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	default:
+		return "number"
+	}
+}
+
+var jsonFieldNames sync.Once
+
+// useJSONFieldNames makes the validator name fields after their JSON
+// names, which are the ones clients know.
+func useJSONFieldNames() {
+	jsonFieldNames.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+		v.RegisterTagNameFunc(func(f reflect.StructField) string {
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			switch name {
+			case "-":
+				return ""
+			case "":
+				return f.Name
+			}
+			return name
+		})
+	})
+}
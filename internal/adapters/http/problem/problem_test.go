@@ -0,0 +1,91 @@
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type bindRequest struct {
+	UserID string   `json:"user_id" binding:"required"`
+	Type   string   `json:"type" binding:"required,oneof=status news"`
+	Tags   []string `json:"tags" binding:"max=1"`
+	Count  int      `json:"count"`
+}
+
+func serve(t *testing.T, body string) Problem {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Handler())
+	r.POST("/things", func(c *gin.Context) {
+		var req bindRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			Bind(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/things", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	var p Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	return p
+}
+
+func TestNewMapsKindToStatus(t *testing.T) {
+	p := New(fmt.Errorf("%w: id 42", errs.ErrTemplateNotFound))
+	assert.Equal(t, http.StatusNotFound, p.Status)
+	assert.Equal(t, "template_not_found", p.Code)
+	assert.Equal(t, "urn:modak:problem:template_not_found", p.Type)
+	assert.Equal(t, "template not found", p.Title)
+	assert.Equal(t, "template not found: id 42", p.Detail)
+
+	assert.Equal(t, http.StatusTooManyRequests, New(errs.ErrRateLimitExceeded).Status)
+	assert.Empty(t, New(errs.ErrRateLimitExceeded).Detail)
+}
+
+func TestNewHidesInternalErrors(t *testing.T) {
+	p := New(errors.New("pq: connection refused"))
+	assert.Equal(t, http.StatusInternalServerError, p.Status)
+	assert.Equal(t, "internal", p.Code)
+	assert.Empty(t, p.Detail)
+}
+
+func TestHandlerListsFieldsAtFault(t *testing.T) {
+	p := serve(t, `{"type":"other","tags":["a","b"]}`)
+	assert.Equal(t, "invalid_request", p.Code)
+	assert.Equal(t, "/things", p.Instance)
+	assert.Equal(t, []FieldError{
+		{Field: "user_id", Message: "is required"},
+		{Field: "type", Message: "must be one of status, news"},
+		{Field: "tags", Message: "must have at most 1 items"},
+	}, p.Errors)
+}
+
+func TestHandlerReportsWrongJSONType(t *testing.T) {
+	p := serve(t, `{"user_id":"u","type":"news","count":"three"}`)
+	assert.Equal(t, []FieldError{{Field: "count", Message: "must be a number"}}, p.Errors)
+}
+
+func TestHandlerReportsMalformedBody(t *testing.T) {
+	p := serve(t, `{"user_id":`)
+	assert.Equal(t, "invalid_request", p.Code)
+	assert.Equal(t, "invalid request: body is not valid JSON", p.Detail)
+	assert.Empty(t, p.Errors)
+}
@@ -0,0 +1,69 @@
+// Package requestlog gives every request an id and a logger carrying it,
+// and logs each request once it has been served.
+package requestlog
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Header carries the request id. A client or proxy may set it to
+// correlate its own logs with ours; it is echoed on every response.
+const Header = "X-Request-ID"
+
+// maxIDLength bounds the ids accepted from clients, which end up in every
+// log line of the request.
+const maxIDLength = 128
+
+// Middleware takes the request id from the X-Request-ID header, or makes
+// one up when it is missing or malformed, and puts a logger with it in the
+// request's context for handlers and everything they call.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(Header)
+		if !validID(id) {
+			id = uuid.NewString()
+		}
+		c.Header(Header, id)
+
+		ctx := logging.With(c.Request.Context(), "request_id", id)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logging.FromContext(ctx).LogAttrs(ctx, level, "request served",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+		)
+	}
+}
+
+// validID reports whether id is short and made of printable ASCII, so a
+// client cannot forge log lines through it.
+func validID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
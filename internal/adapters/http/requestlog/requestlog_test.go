@@ -0,0 +1,74 @@
+package requestlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// serve runs a request with header as its X-Request-ID and returns the
+// response and the lines logged while serving it.
+func serve(t *testing.T, header string) (*httptest.ResponseRecorder, []map[string]any) {
+	t.Helper()
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	require.NoError(t, err)
+	prev := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/things/:id", func(c *gin.Context) {
+		logging.FromContext(c.Request.Context()).Info("handled")
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/things/42", nil)
+	if header != "" {
+		req.Header.Set(Header, header)
+	}
+	r.ServeHTTP(w, req)
+
+	var lines []map[string]any
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal([]byte(l), &line))
+		lines = append(lines, line)
+	}
+	return w, lines
+}
+
+func TestMiddlewareKeepsRequestID(t *testing.T) {
+	w, lines := serve(t, "req-123")
+	require.Equal(t, "req-123", w.Header().Get(Header))
+	require.Len(t, lines, 2)
+	require.Equal(t, "handled", lines[0]["msg"])
+	require.Equal(t, "req-123", lines[0]["request_id"])
+
+	require.Equal(t, "request served", lines[1]["msg"])
+	require.Equal(t, "req-123", lines[1]["request_id"])
+	require.Equal(t, "/things/:id", lines[1]["route"])
+	require.EqualValues(t, http.StatusNoContent, lines[1]["status"])
+}
+
+func TestMiddlewareGeneratesRequestID(t *testing.T) {
+	for _, header := range []string{"", "bad id\n", strings.Repeat("a", maxIDLength+1)} {
+		w, lines := serve(t, header)
+		id := w.Header().Get(Header)
+		_, err := uuid.Parse(id)
+		require.NoError(t, err)
+		require.Equal(t, id, lines[0]["request_id"])
+	}
+}
@@ -1,25 +1,51 @@
 package http
 
 import (
+	"io"
+	"runtime/debug"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/health"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/httpmetrics"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/httptracing"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/requestlog"
 	v1 "github.com/Paulooo0/modak-challenge/internal/adapters/http/v1"
-	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(uc *usecase.NotificationUseCase) *gin.Engine {
-	r := gin.Default()
+// NewRouter serves the API, the probes on /livez and /readyz, and metrics
+// registered in reg on /metrics. HTTP requests are counted in reg as well.
+// Client IPs are only read from X-Forwarded-For and similar headers when
+// the request comes from one of trustedProxies, IPs or CIDRs; with none,
+// the address of the connection is used.
+func NewRouter(deps v1.Dependencies, reg *prometheus.Registry, probes *health.Probes, trustedProxies []string) (*gin.Engine, error) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
+	r.Use(httptracing.Middleware(), requestlog.Middleware(), gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logging.FromContext(c.Request.Context()).Error("panic recovered", "panic", recovered, "stack", string(debug.Stack()))
+		problem.Abort(c, errs.ErrInternal)
+	}), httpmetrics.Middleware(reg), problem.Handler())
+
+	r.GET("/livez", probes.Livez)
+	r.GET("/readyz", probes.Readyz)
+	// Kept for existing clients; liveness is what it always reported.
+	r.GET("/health", probes.Livez)
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok"})
-	})
+	r.GET("/metrics", gin.WrapH(promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg})))
 
 	// Swagger UI
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	apiV1 := r.Group("/v1")
-	v1.RegisterRoutes(apiV1, uc)
+	v1.RegisterRoutes(apiV1, deps)
 
-	return r
+	return r, nil
 }
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/health"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/throttle"
+	v1 "github.com/Paulooo0/modak-challenge/internal/adapters/http/v1"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPIgnoresForwardedForFromUntrustedPeers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	deps := v1.Dependencies{ClientRates: v1.ClientRates{IP: throttle.Rate{Requests: 1, Period: time.Minute}}}
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		wantSecond     int
+	}{
+		{"no trusted proxies", nil, http.StatusTooManyRequests},
+		{"behind a trusted proxy", []string{"192.0.2.0/24"}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRouter(deps, prometheus.NewRegistry(), health.New(time.Second), tt.trustedProxies)
+			require.NoError(t, err)
+
+			codes := make([]int, 2)
+			for i, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+				req := httptest.NewRequest(http.MethodGet, "/v1/templates", nil)
+				req.RemoteAddr = "192.0.2.10:4321"
+				req.Header.Set("X-Forwarded-For", forwardedFor)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				codes[i] = w.Code
+			}
+
+			require.Equal(t, []int{http.StatusUnauthorized, tt.wantSecond}, codes)
+		})
+	}
+}
+
+func TestNewRouterRejectsInvalidTrustedProxies(t *testing.T) {
+	_, err := NewRouter(v1.Dependencies{}, prometheus.NewRegistry(), health.New(time.Second), []string{"not-an-ip"})
+	require.Error(t, err)
+}
@@ -0,0 +1,140 @@
+// Package throttle limits how fast each API client may call the server,
+// so that a single caller cannot saturate it. It is unrelated to the
+// per-recipient rate limits of notification types.
+package throttle
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// Rate lets a client make Requests requests every Period. They may arrive
+// in a single burst, after which the allowance refills evenly over the
+// period. The zero Rate does not limit.
+type Rate struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseRate parses rates such as "600/1m" or "10/1s". An empty string or
+// "0" is the zero Rate.
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Rate{}, nil
+	}
+
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must be <requests>/<period>, such as 600/1m", s)
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return Rate{}, fmt.Errorf("rate %q must allow at least one request", s)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must have a positive period", s)
+	}
+	return Rate{Requests: n, Period: d}, nil
+}
+
+// Limiter tracks the allowance of every client seen recently against a
+// single Rate.
+type Limiter struct {
+	rate Rate
+
+	mu        sync.Mutex
+	clients   map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+func NewLimiter(r Rate) *Limiter {
+	return &Limiter{rate: r, clients: make(map[string]*rate.Limiter)}
+}
+
+// Allow takes one request from the allowance of client at now. When none
+// is left, it returns false and how long until there is.
+func (l *Limiter) Allow(client string, now time.Time) (bool, time.Duration) {
+	if l.rate.Requests == 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	l.sweep(now)
+	lim, ok := l.clients[client]
+	if !ok {
+		lim = rate.NewLimiter(rate.Every(l.rate.Period/time.Duration(l.rate.Requests)), l.rate.Requests)
+		l.clients[client] = lim
+	}
+	l.mu.Unlock()
+
+	res := lim.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// sweep forgets, at most once a period, the clients whose allowance has
+// refilled completely, since a new limiter would treat them the same.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.rate.Period {
+		return
+	}
+	l.lastSweep = now
+	for client, lim := range l.clients {
+		if lim.TokensAt(now) >= float64(l.rate.Requests) {
+			delete(l.clients, client)
+		}
+	}
+}
+
+// PerClient limits each client to r, answering 429 with Retry-After once a
+// client runs out, and a problem with code too_many_requests whose
+// retry_after holds the same number of seconds. Clients authenticated by
+// auth.Bearer are told apart by API key or user; before authentication, or
+// without it, by IP. Every call counts against a separate allowance, so
+// route groups limited with PerClient do not share theirs.
+func PerClient(r Rate) gin.HandlerFunc {
+	if r.Requests == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	l := NewLimiter(r)
+	return func(c *gin.Context) {
+		ok, wait := l.Allow(clientKey(c), time.Now())
+		if !ok {
+			seconds := int(math.Ceil(wait.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			p := problem.New(errs.ErrTooManyRequests)
+			p.RetryAfter = seconds
+			problem.AbortWith(c, p)
+			return
+		}
+		c.Next()
+	}
+}
+
+func clientKey(c *gin.Context) string {
+	p, ok := auth.PrincipalFrom(c)
+	switch {
+	case ok && p.APIKey != nil:
+		return "key:" + p.APIKey.ID.String()
+	case ok && p.IsUser():
+		return "user:" + string(p.TenantID) + ":" + p.UserID.String()
+	default:
+		return "ip:" + c.ClientIP()
+	}
+}
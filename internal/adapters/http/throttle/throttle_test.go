@@ -0,0 +1,114 @@
+package throttle
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Rate
+		wantErr bool
+	}{
+		{"600/1m", Rate{Requests: 600, Period: time.Minute}, false},
+		{" 10/1s ", Rate{Requests: 10, Period: time.Second}, false},
+		{"", Rate{}, false},
+		{"0", Rate{}, false},
+		{"600", Rate{}, true},
+		{"0/1m", Rate{}, true},
+		{"10/forever", Rate{}, true},
+		{"10/-1s", Rate{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRate(tt.in)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLimiterAllowsBurstThenRefills(t *testing.T) {
+	l := NewLimiter(Rate{Requests: 2, Period: 2 * time.Second})
+	now := time.Now()
+
+	ok, _ := l.Allow("a", now)
+	require.True(t, ok)
+	ok, _ = l.Allow("a", now)
+	require.True(t, ok)
+	ok, wait := l.Allow("a", now)
+	require.False(t, ok)
+	require.Equal(t, time.Second, wait)
+
+	ok, _ = l.Allow("b", now)
+	require.True(t, ok, "clients have separate allowances")
+
+	ok, _ = l.Allow("a", now.Add(time.Second))
+	require.True(t, ok)
+}
+
+func TestLimiterForgetsIdleClients(t *testing.T) {
+	l := NewLimiter(Rate{Requests: 1, Period: time.Second})
+	now := time.Now()
+
+	l.Allow("a", now)
+	l.Allow("b", now.Add(2*time.Second))
+
+	require.Len(t, l.clients, 1)
+}
+
+func TestPerClientAnswers429(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", PerClient(Rate{Requests: 1, Period: time.Minute}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	require.Equal(t, http.StatusOK, serve("10.0.0.1").Code)
+
+	w := serve("10.0.0.1")
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+	require.Equal(t, "60", w.Header().Get("Retry-After"))
+	require.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+	var resp problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, errs.ErrTooManyRequests.Code, resp.Code)
+	require.Equal(t, 60, resp.RetryAfter)
+
+	require.Equal(t, http.StatusOK, serve("10.0.0.2").Code)
+}
+
+func TestPerClientZeroRateDoesNotLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", PerClient(Rate{}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for range 5 {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+}
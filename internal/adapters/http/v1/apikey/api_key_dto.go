@@ -0,0 +1,59 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// APIKeyRequest creates a key with the given scopes: notifications:send,
+// notifications:read or admin. The key belongs to the caller's tenant
+// unless tenant_id names another one, which only API keys of the default
+// tenant may do.
+type APIKeyRequest struct {
+	Name     string   `json:"name" binding:"required,max=255"`
+	Scopes   []string `json:"scopes" binding:"required,min=1,dive,oneof=notifications:send notifications:read admin"`
+	TenantID string   `json:"tenant_id,omitempty" example:"shop"`
+}
+
+func (r APIKeyRequest) toEntity() entity.APIKey {
+	k := entity.APIKey{TenantID: entity.TenantID(r.TenantID), Name: r.Name, Scopes: make([]entity.Scope, len(r.Scopes))}
+	for i, s := range r.Scopes {
+		k.Scopes[i] = entity.Scope(s)
+	}
+	return k
+}
+
+// APIKeyResponse describes a key. prefix is the start of its secret; the
+// secret itself is only returned, as key, when the key is created or
+// rotated.
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	Key        string     `json:"key,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func newAPIKeyResponse(k entity.APIKey, secret string) APIKeyResponse {
+	resp := APIKeyResponse{
+		ID:         k.ID,
+		TenantID:   string(k.TenantID),
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     make([]string, len(k.Scopes)),
+		Key:        secret,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+	for i, s := range k.Scopes {
+		resp.Scopes[i] = string(s)
+	}
+	return resp
+}
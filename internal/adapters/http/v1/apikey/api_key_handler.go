@@ -0,0 +1,127 @@
+package apikey
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	uc *usecase.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(uc *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{uc: uc}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Creates an API key with the given scopes, in the caller's tenant or, for API keys of the default tenant, in tenant_id. The key is only returned in this response.
+// @Tags api-keys
+// @Security BearerAuth
+// @Param request body APIKeyRequest true "API key payload"
+// @Success 201 {object} APIKeyResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 403 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+	// Only keys may hand out keys for other tenants; a user token is
+	// confined to the tenant it was issued for.
+	if p, ok := auth.PrincipalFrom(c); ok && p.IsUser() && req.TenantID != "" && entity.TenantID(req.TenantID) != p.TenantID {
+		c.Error(errs.ErrTenantForbidden)
+		return
+	}
+
+	k, secret, err := h.uc.Create(c.Request.Context(), req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newAPIKeyResponse(k, secret))
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description Lists every API key, including revoked ones, without their secrets
+// @Tags api-keys
+// @Security BearerAuth
+// @Success 200 {array} APIKeyResponse
+// @Failure 500 {object} problem.Problem
+// @Router /v1/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.uc.List(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := make([]APIKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = newAPIKeyResponse(k, "")
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate an API key
+// @Description Replaces the secret of an API key, keeping its name and scopes. The old secret stops working immediately; the new one is only returned in this response.
+// @Tags api-keys
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} APIKeyResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrAPIKeyNotFound)
+		return
+	}
+
+	k, secret, err := h.uc.Rotate(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAPIKeyResponse(k, secret))
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revokes an API key for good
+// @Tags api-keys
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrAPIKeyNotFound)
+		return
+	}
+
+	if err := h.uc.Revoke(c.Request.Context(), id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package apikey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyRepo struct{ mock.Mock }
+
+func (m *MockAPIKeyRepo) Create(ctx context.Context, k entity.APIKey) (entity.APIKey, error) {
+	args := m.Called(ctx, k)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetActiveByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) List(ctx context.Context) ([]entity.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Rotate(ctx context.Context, id uuid.UUID, prefix, hash string) (entity.APIKey, error) {
+	args := m.Called(ctx, id, prefix, hash)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAPIKeyRepo) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return m.Called(ctx, id, usedAt).Error(0)
+}
+
+func newRouter(repo *MockAPIKeyRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler(), func(c *gin.Context) {
+		// As auth.Bearer does, for a key of the default tenant unless the
+		// test acts for another.
+		if _, ok := entity.TenantFromContext(c.Request.Context()); !ok {
+			c.Request = c.Request.WithContext(entity.WithTenant(c.Request.Context(), entity.DefaultTenant))
+		}
+	})
+	RegisterAPIKeyRoutes(r.Group("/v1"), usecase.NewAPIKeyUseCase(repo))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestCreateAPIKeyReturnsSecretOnce(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	repo.On("Create", mock.Anything, mock.Anything).Return(entity.APIKey{
+		ID: uuid.New(), Name: "backend", Prefix: "mk_abcdefgh", Scopes: []entity.Scope{entity.ScopeNotificationsSend},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/api-keys", APIKeyRequest{
+		Name:   "backend",
+		Scopes: []string{"notifications:send"},
+	}))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp APIKeyResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, strings.HasPrefix(resp.Key, "mk_"))
+	require.Equal(t, []string{"notifications:send"}, resp.Scopes)
+}
+
+func TestCreateAPIKeyRejectsUnknownScope(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(new(MockAPIKeyRepo)).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/api-keys", APIKeyRequest{
+		Name:   "backend",
+		Scopes: []string{"everything"},
+	}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateAPIKeyForAnotherTenantForbidden(t *testing.T) {
+	req := newJSONRequest(t, http.MethodPost, "/v1/api-keys", APIKeyRequest{
+		Name:     "backend",
+		Scopes:   []string{"admin"},
+		TenantID: "games",
+	})
+	req = req.WithContext(entity.WithTenant(req.Context(), "shop"))
+
+	w := httptest.NewRecorder()
+	newRouter(new(MockAPIKeyRepo)).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+type userTokens auth.Principal
+
+func (u userTokens) Verify(context.Context, string) (auth.Principal, error) {
+	return auth.Principal(u), nil
+}
+
+func TestCreateAPIKeyForAnotherTenantForbiddenToUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler(), auth.Bearer(nil, userTokens{UserID: uuid.New(), TenantID: entity.DefaultTenant, Scopes: []entity.Scope{entity.ScopeAdmin}}))
+	RegisterAPIKeyRoutes(r.Group("/v1"), usecase.NewAPIKeyUseCase(new(MockAPIKeyRepo)))
+
+	req := newJSONRequest(t, http.MethodPost, "/v1/api-keys", APIKeyRequest{
+		Name:     "backend",
+		Scopes:   []string{"admin"},
+		TenantID: "games",
+	})
+	req.Header.Set("Authorization", "Bearer user-token")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Contains(t, w.Body.String(), "tenant_forbidden")
+}
+
+func TestListAPIKeysHidesSecrets(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	repo.On("List", mock.Anything).Return([]entity.APIKey{{ID: uuid.New(), Name: "backend", Prefix: "mk_abcdefgh", Hash: "hash"}}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/api-keys", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), `"key"`)
+	require.NotContains(t, w.Body.String(), "hash")
+}
+
+func TestRotateRevokedAPIKey(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	id := uuid.New()
+	repo.On("Rotate", mock.Anything, id, mock.Anything, mock.Anything).Return(entity.APIKey{}, errs.ErrAPIKeyNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/api-keys/"+id.String()+"/rotate", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	id := uuid.New()
+	repo.On("Revoke", mock.Anything, id).Return(nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/api-keys/"+id.String(), nil))
+
+	require.Equal(t, http.StatusNoContent, w.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,18 @@
+package apikey
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAPIKeyRoutes(r *gin.RouterGroup, uc *usecase.APIKeyUseCase) {
+	h := NewAPIKeyHandler(uc)
+
+	api := r.Group("/api-keys")
+	{
+		api.POST("", h.CreateAPIKey)
+		api.GET("", h.ListAPIKeys)
+		api.POST("/:id/rotate", h.RotateAPIKey)
+		api.DELETE("/:id", h.RevokeAPIKey)
+	}
+}
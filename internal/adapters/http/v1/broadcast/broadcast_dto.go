@@ -0,0 +1,67 @@
+package broadcast
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// BroadcastRequest sends one notification to every user of a segment.
+type BroadcastRequest struct {
+	SegmentID uuid.UUID         `json:"segment_id" binding:"required"`
+	Type      string            `json:"type" binding:"required,oneof=status news marketing"`
+	Title     string            `json:"title,omitempty" binding:"max=255"`
+	Message   string            `json:"message" binding:"required"`
+	ActionURL string            `json:"action_url,omitempty" binding:"omitempty,url"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+func (r BroadcastRequest) toEntity() entity.Broadcast {
+	return entity.Broadcast{
+		SegmentID: r.SegmentID,
+		Type:      entity.NotificationType(r.Type),
+		Title:     r.Title,
+		Message:   r.Message,
+		ActionURL: r.ActionURL,
+		Metadata:  r.Metadata,
+	}
+}
+
+// BroadcastResponse reports a broadcast's progress. total is the segment
+// size when the broadcast was created; processed counts recipients handled
+// so far, split into sent, rate_limited, skipped (duplicate, held for a
+// digest or expired) and failed.
+type BroadcastResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	SegmentID   uuid.UUID  `json:"segment_id"`
+	Type        string     `json:"type"`
+	Status      string     `json:"status"`
+	Total       int        `json:"total"`
+	Processed   int        `json:"processed"`
+	Sent        int        `json:"sent"`
+	RateLimited int        `json:"rate_limited"`
+	Skipped     int        `json:"skipped"`
+	Failed      int        `json:"failed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func newBroadcastResponse(b entity.Broadcast) BroadcastResponse {
+	return BroadcastResponse{
+		ID:          b.ID,
+		SegmentID:   b.SegmentID,
+		Type:        string(b.Type),
+		Status:      string(b.Status),
+		Total:       b.Total,
+		Processed:   b.Processed,
+		Sent:        b.Sent,
+		RateLimited: b.RateLimited,
+		Skipped:     b.Skipped,
+		Failed:      b.Failed,
+		CreatedAt:   b.CreatedAt,
+		UpdatedAt:   b.UpdatedAt,
+		CompletedAt: b.CompletedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package broadcast
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BroadcastHandler struct {
+	uc *usecase.BroadcastUseCase
+}
+
+func NewBroadcastHandler(uc *usecase.BroadcastUseCase) *BroadcastHandler {
+	return &BroadcastHandler{uc: uc}
+}
+
+// CreateBroadcast godoc
+// @Summary Broadcast to a segment
+// @Description Queues a notification for every user of a segment. The segment is expanded in the background in pages, and each recipient goes through the usual rate limits, dedupe and digests. Follow progress with GET /v1/broadcasts/{id}.
+// @Tags broadcasts
+// @Security BearerAuth
+// @Param request body BroadcastRequest true "Broadcast payload"
+// @Success 202 {object} BroadcastResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/broadcasts [post]
+func (h *BroadcastHandler) CreateBroadcast(c *gin.Context) {
+	var req BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	b, err := h.uc.Create(c.Request.Context(), req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newBroadcastResponse(b))
+}
+
+// GetBroadcast godoc
+// @Summary Get broadcast progress
+// @Description Returns the status of a broadcast and how many recipients were sent, rate limited, skipped or failed so far
+// @Tags broadcasts
+// @Security BearerAuth
+// @Param id path string true "Broadcast ID"
+// @Success 200 {object} BroadcastResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/broadcasts/{id} [get]
+func (h *BroadcastHandler) GetBroadcast(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrBroadcastNotFound)
+		return
+	}
+
+	b, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newBroadcastResponse(b))
+}
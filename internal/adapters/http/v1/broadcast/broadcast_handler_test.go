@@ -0,0 +1,133 @@
+package broadcast
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockBroadcastRepo struct{ mock.Mock }
+
+func (m *MockBroadcastRepo) Create(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	args := m.Called(ctx, b)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) Get(ctx context.Context, id uuid.UUID) (entity.Broadcast, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Broadcast, error) {
+	args := m.Called(ctx, now, leasedUntil)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) SaveProgress(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	args := m.Called(ctx, b)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+type MockSegmentRepo struct{ mock.Mock }
+
+func (m *MockSegmentRepo) Create(ctx context.Context, s entity.Segment) (entity.Segment, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Get(ctx context.Context, id uuid.UUID) (entity.Segment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Count(ctx context.Context, s entity.Segment) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSegmentRepo) ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, s, after, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func newRouter(broadcasts *MockBroadcastRepo, segments *MockSegmentRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterBroadcastRoutes(r.Group("/v1"), usecase.NewBroadcastUseCase(broadcasts, segments, nil, 100, time.Minute))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestCreateBroadcast(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	segments := new(MockSegmentRepo)
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"beta"}}
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("Count", mock.Anything, seg).Return(250, nil)
+	broadcasts.On("Create", mock.Anything, mock.Anything).Return(entity.Broadcast{ID: uuid.New(), SegmentID: seg.ID, Status: entity.BroadcastPending, Total: 250}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(broadcasts, segments).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/broadcasts", BroadcastRequest{
+		SegmentID: seg.ID,
+		Type:      "news",
+		Message:   "New release",
+	}))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp BroadcastResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "pending", resp.Status)
+	require.Equal(t, 250, resp.Total)
+}
+
+func TestCreateBroadcastUnknownSegment(t *testing.T) {
+	segments := new(MockSegmentRepo)
+	id := uuid.New()
+	segments.On("Get", mock.Anything, id).Return(entity.Segment{}, errs.ErrSegmentNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(new(MockBroadcastRepo), segments).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/broadcasts", BroadcastRequest{
+		SegmentID: id,
+		Type:      "news",
+		Message:   "New release",
+	}))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetBroadcastProgress(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	b := entity.Broadcast{ID: uuid.New(), Status: entity.BroadcastRunning, Total: 10, Processed: 4, Sent: 3, RateLimited: 1}
+	broadcasts.On("Get", mock.Anything, b.ID).Return(b, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(broadcasts, new(MockSegmentRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/broadcasts/"+b.ID.String(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp BroadcastResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 4, resp.Processed)
+	require.Equal(t, 1, resp.RateLimited)
+}
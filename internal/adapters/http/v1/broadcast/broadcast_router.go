@@ -0,0 +1,16 @@
+package broadcast
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterBroadcastRoutes(r *gin.RouterGroup, uc *usecase.BroadcastUseCase) {
+	h := NewBroadcastHandler(uc)
+
+	api := r.Group("/broadcasts")
+	{
+		api.POST("", h.CreateBroadcast)
+		api.GET("/:id", h.GetBroadcast)
+	}
+}
@@ -0,0 +1,92 @@
+package campaign
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// CampaignRequest rolls a template out to a segment as marketing
+// notifications, starting at start_at (now when omitted) and sending to at
+// most rate_per_minute recipients a minute. data is rendered into the
+// template for every recipient; template_version defaults to the latest.
+type CampaignRequest struct {
+	Name            string         `json:"name" binding:"required,max=255"`
+	SegmentID       uuid.UUID      `json:"segment_id" binding:"required"`
+	TemplateID      uuid.UUID      `json:"template_id" binding:"required"`
+	TemplateVersion *int           `json:"template_version,omitempty" binding:"omitempty,min=1"`
+	Data            map[string]any `json:"data,omitempty"`
+	StartAt         *time.Time     `json:"start_at,omitempty"`
+	RatePerMinute   int            `json:"rate_per_minute" binding:"required,min=1"`
+}
+
+func (r CampaignRequest) toEntity() entity.Campaign {
+	c := entity.Campaign{
+		Name:          r.Name,
+		SegmentID:     r.SegmentID,
+		TemplateID:    r.TemplateID,
+		Data:          r.Data,
+		RatePerMinute: r.RatePerMinute,
+	}
+	if r.TemplateVersion != nil {
+		c.TemplateVersion = *r.TemplateVersion
+	}
+	if r.StartAt != nil {
+		c.StartAt = *r.StartAt
+	}
+	return c
+}
+
+// CampaignStats counts what happened to the recipients handled so far.
+// suppressed covers duplicates, notifications held for a digest and
+// expired ones.
+type CampaignStats struct {
+	Total       int `json:"total"`
+	Processed   int `json:"processed"`
+	Sent        int `json:"sent"`
+	RateLimited int `json:"rate_limited"`
+	Suppressed  int `json:"suppressed"`
+	Failed      int `json:"failed"`
+}
+
+type CampaignResponse struct {
+	ID              uuid.UUID      `json:"id"`
+	Name            string         `json:"name"`
+	SegmentID       uuid.UUID      `json:"segment_id"`
+	TemplateID      uuid.UUID      `json:"template_id"`
+	TemplateVersion int            `json:"template_version"`
+	Data            map[string]any `json:"data,omitempty"`
+	StartAt         time.Time      `json:"start_at"`
+	RatePerMinute   int            `json:"rate_per_minute"`
+	Status          string         `json:"status"`
+	Stats           CampaignStats  `json:"stats"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	CompletedAt     *time.Time     `json:"completed_at,omitempty"`
+}
+
+func newCampaignResponse(c entity.Campaign) CampaignResponse {
+	return CampaignResponse{
+		ID:              c.ID,
+		Name:            c.Name,
+		SegmentID:       c.SegmentID,
+		TemplateID:      c.TemplateID,
+		TemplateVersion: c.TemplateVersion,
+		Data:            c.Data,
+		StartAt:         c.StartAt,
+		RatePerMinute:   c.RatePerMinute,
+		Status:          string(c.Status),
+		Stats: CampaignStats{
+			Total:       c.Total,
+			Processed:   c.Processed,
+			Sent:        c.Sent,
+			RateLimited: c.RateLimited,
+			Suppressed:  c.Suppressed,
+			Failed:      c.Failed,
+		},
+		CreatedAt:   c.CreatedAt,
+		UpdatedAt:   c.UpdatedAt,
+		CompletedAt: c.CompletedAt,
+	}
+}
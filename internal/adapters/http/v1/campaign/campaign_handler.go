@@ -0,0 +1,124 @@
+package campaign
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CampaignHandler struct {
+	uc *usecase.CampaignUseCase
+}
+
+func NewCampaignHandler(uc *usecase.CampaignUseCase) *CampaignHandler {
+	return &CampaignHandler{uc: uc}
+}
+
+// CreateCampaign godoc
+// @Summary Create a campaign
+// @Description Schedules a marketing campaign that renders a template for every user of a segment, starting at start_at and sending to at most rate_per_minute recipients a minute. The marketing rate limit still applies per user.
+// @Tags campaigns
+// @Security BearerAuth
+// @Param request body CampaignRequest true "Campaign payload"
+// @Success 201 {object} CampaignResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/campaigns [post]
+func (h *CampaignHandler) CreateCampaign(c *gin.Context) {
+	var req CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	campaign, err := h.uc.Create(c.Request.Context(), req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newCampaignResponse(campaign))
+}
+
+// GetCampaign godoc
+// @Summary Get a campaign
+// @Description Returns a campaign with its status and stats
+// @Tags campaigns
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} CampaignResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/campaigns/{id} [get]
+func (h *CampaignHandler) GetCampaign(c *gin.Context) {
+	h.serve(c, h.uc.Get)
+}
+
+// PauseCampaign godoc
+// @Summary Pause a campaign
+// @Description Stops a scheduled or running campaign after the batch in flight
+// @Tags campaigns
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} CampaignResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/campaigns/{id}/pause [post]
+func (h *CampaignHandler) PauseCampaign(c *gin.Context) {
+	h.serve(c, h.uc.Pause)
+}
+
+// ResumeCampaign godoc
+// @Summary Resume a campaign
+// @Description Continues a paused campaign where it stopped
+// @Tags campaigns
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} CampaignResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/campaigns/{id}/resume [post]
+func (h *CampaignHandler) ResumeCampaign(c *gin.Context) {
+	h.serve(c, h.uc.Resume)
+}
+
+// CancelCampaign godoc
+// @Summary Cancel a campaign
+// @Description Stops a campaign for good after the batch in flight. Recipients already handled keep their notifications.
+// @Tags campaigns
+// @Security BearerAuth
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} CampaignResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/campaigns/{id}/cancel [post]
+func (h *CampaignHandler) CancelCampaign(c *gin.Context) {
+	h.serve(c, h.uc.Cancel)
+}
+
+// serve runs fn on the campaign named in the path and writes the result.
+func (h *CampaignHandler) serve(c *gin.Context, fn func(context.Context, uuid.UUID) (entity.Campaign, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrCampaignNotFound)
+		return
+	}
+
+	campaign, err := fn(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newCampaignResponse(campaign))
+}
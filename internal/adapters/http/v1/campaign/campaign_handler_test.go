@@ -0,0 +1,195 @@
+package campaign
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockCampaignRepo struct{ mock.Mock }
+
+func (m *MockCampaignRepo) Create(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) Get(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Campaign, error) {
+	args := m.Called(ctx, now, leasedUntil)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) SaveProgress(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) UpdateStatus(ctx context.Context, id uuid.UUID, from []entity.CampaignStatus, to entity.CampaignStatus) (entity.Campaign, error) {
+	args := m.Called(ctx, id, from, to)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+type MockSegmentRepo struct{ mock.Mock }
+
+func (m *MockSegmentRepo) Create(ctx context.Context, s entity.Segment) (entity.Segment, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Get(ctx context.Context, id uuid.UUID) (entity.Segment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Count(ctx context.Context, s entity.Segment) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSegmentRepo) ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, s, after, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+type MockTemplateRepo struct{ mock.Mock }
+
+func (m *MockTemplateRepo) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) List(ctx context.Context) ([]entity.Template, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Template), args.Error(1)
+}
+
+func newRouter(campaigns *MockCampaignRepo, segments *MockSegmentRepo, templates *MockTemplateRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterCampaignRoutes(r.Group("/v1"), usecase.NewCampaignUseCase(campaigns, segments, templates, nil, 100, time.Minute))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestCreateCampaign(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	segments := new(MockSegmentRepo)
+	templates := new(MockTemplateRepo)
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"vip"}}
+	tmplID := uuid.New()
+
+	templates.On("Get", mock.Anything, tmplID, 0).Return(entity.Template{ID: tmplID, Version: 2, Body: "Sale!"}, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("Count", mock.Anything, seg).Return(1000, nil)
+	campaigns.On("Create", mock.Anything, mock.Anything).Return(entity.Campaign{
+		ID: uuid.New(), Name: "spring sale", TemplateID: tmplID, TemplateVersion: 2,
+		Status: entity.CampaignScheduled, RatePerMinute: 100, Total: 1000,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(campaigns, segments, templates).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/campaigns", CampaignRequest{
+		Name:          "spring sale",
+		SegmentID:     seg.ID,
+		TemplateID:    tmplID,
+		RatePerMinute: 100,
+	}))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp CampaignResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "scheduled", resp.Status)
+	require.Equal(t, 2, resp.TemplateVersion)
+	require.Equal(t, 1000, resp.Stats.Total)
+}
+
+func TestCreateCampaignRequiresRate(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(new(MockCampaignRepo), new(MockSegmentRepo), new(MockTemplateRepo)).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/campaigns", map[string]any{
+		"name":        "spring sale",
+		"segment_id":  uuid.New(),
+		"template_id": uuid.New(),
+	}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetCampaignStats(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	c := entity.Campaign{ID: uuid.New(), Status: entity.CampaignRunning, Total: 10, Processed: 6, Sent: 4, RateLimited: 1, Suppressed: 1}
+	campaigns.On("Get", mock.Anything, c.ID).Return(c, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(campaigns, new(MockSegmentRepo), new(MockTemplateRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/campaigns/"+c.ID.String(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp CampaignResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, CampaignStats{Total: 10, Processed: 6, Sent: 4, RateLimited: 1, Suppressed: 1}, resp.Stats)
+}
+
+func TestPauseCampaign(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	id := uuid.New()
+	campaigns.On("UpdateStatus", mock.Anything, id, mock.Anything, entity.CampaignPaused).
+		Return(entity.Campaign{ID: id, Status: entity.CampaignPaused}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(campaigns, new(MockSegmentRepo), new(MockTemplateRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/campaigns/"+id.String()+"/pause", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"status":"paused"`)
+}
+
+func TestCancelFinishedCampaignConflicts(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	id := uuid.New()
+	campaigns.On("UpdateStatus", mock.Anything, id, mock.Anything, entity.CampaignCancelled).Return(entity.Campaign{}, errs.ErrCampaignNotFound)
+	campaigns.On("Get", mock.Anything, id).Return(entity.Campaign{ID: id, Status: entity.CampaignCompleted}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(campaigns, new(MockSegmentRepo), new(MockTemplateRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/campaigns/"+id.String()+"/cancel", nil))
+
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestResumeUnknownCampaign(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	id := uuid.New()
+	campaigns.On("Get", mock.Anything, id).Return(entity.Campaign{}, errs.ErrCampaignNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(campaigns, new(MockSegmentRepo), new(MockTemplateRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/campaigns/"+id.String()+"/resume", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
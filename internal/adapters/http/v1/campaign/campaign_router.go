@@ -0,0 +1,19 @@
+package campaign
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCampaignRoutes(r *gin.RouterGroup, uc *usecase.CampaignUseCase) {
+	h := NewCampaignHandler(uc)
+
+	api := r.Group("/campaigns")
+	{
+		api.POST("", h.CreateCampaign)
+		api.GET("/:id", h.GetCampaign)
+		api.POST("/:id/pause", h.PauseCampaign)
+		api.POST("/:id/resume", h.ResumeCampaign)
+		api.POST("/:id/cancel", h.CancelCampaign)
+	}
+}
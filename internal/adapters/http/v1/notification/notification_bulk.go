@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+const (
+	bulkStatusInvalid = "invalid"
+	bulkStatusFailed  = "failed"
+)
+
+// SendBulk godoc
+// @Summary Send notifications in bulk
+// @Description Sends up to 1000 notifications in one request, given as items or as one message with a list of user_ids. Rate limits apply per recipient, counting earlier items of the same request. Each item gets its own result: sent, held (over the limit of a digest type), rate_limited, invalid or failed (stored but not delivered). Bulk sends are delivered immediately and do not support templates, scheduling or idempotency keys.
+// @Tags notifications
+// @Security BearerAuth
+// @Param request body BulkSendRequest true "Notifications"
+// @Success 200 {object} BulkSendResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/notifications/bulk [post]
+func (h *NotificationHandler) SendBulk(c *gin.Context) {
+	var req BulkSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	items := req.Items
+	switch {
+	case len(req.Items) > 0 && len(req.UserIDs) > 0:
+		c.Error(fmt.Errorf("%w: items and user_ids are mutually exclusive", errs.ErrInvalidRequest))
+		return
+	case len(req.UserIDs) > 0:
+		items = make([]BulkSendItem, 0, len(req.UserIDs))
+		for _, userID := range req.UserIDs {
+			items = append(items, BulkSendItem{
+				UserID:    userID,
+				Type:      req.Type,
+				Title:     req.Title,
+				Message:   req.Message,
+				ActionURL: req.ActionURL,
+				Metadata:  req.Metadata,
+			})
+		}
+	case len(req.Items) == 0:
+		c.Error(fmt.Errorf("%w: either items or user_ids is required", errs.ErrInvalidRequest))
+		return
+	}
+
+	results := make([]BulkSendResult, len(items))
+	ns := make([]entity.Notification, 0, len(items))
+	valid := make([]int, 0, len(items))
+	for i, item := range items {
+		results[i] = BulkSendResult{Index: i, UserID: item.UserID}
+		if err := binding.Validator.ValidateStruct(item); err != nil {
+			results[i].Status = bulkStatusInvalid
+			results[i].setError(problem.Validation(err))
+			continue
+		}
+		ns = append(ns, entity.Notification{
+			UserID:    item.UserID,
+			Type:      entity.NotificationType(item.Type),
+			Title:     item.Title,
+			Message:   item.Message,
+			ActionURL: item.ActionURL,
+			Metadata:  item.Metadata,
+		})
+		valid = append(valid, i)
+	}
+
+	sent, err := h.uc.SendBulk(c.Request.Context(), ns)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	for j, r := range sent {
+		res := &results[valid[j]]
+		if r.Notification.ID != uuid.Nil {
+			id := r.Notification.ID
+			res.ID = &id
+		}
+		switch {
+		case r.Err == nil:
+			res.Status = string(r.Notification.Status)
+			continue
+		case errors.Is(r.Err, errs.ErrRateLimitExceeded):
+			res.Status = string(entity.StatusRateLimited)
+		case errors.Is(r.Err, errs.ErrInvalidNotification):
+			res.Status = bulkStatusInvalid
+		default:
+			logging.FromContext(c.Request.Context()).Error("bulk delivery failed", "err", r.Err,
+				"notification_id", r.Notification.ID, "user_id", r.Notification.UserID, "type", r.Notification.Type)
+			res.Status = bulkStatusFailed
+		}
+		res.setError(r.Err)
+	}
+
+	c.JSON(http.StatusOK, BulkSendResponse{Results: results})
+}
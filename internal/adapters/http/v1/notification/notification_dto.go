@@ -1,17 +1,207 @@
 package notification
 
-import "github.com/google/uuid"
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"time"
 
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// SendNotificationRequest carries either a rendered message or a template_id
+// with the data to render it. message is the body; title, action_url and
+// metadata are optional and used by channels that support them. The
+// optional id also acts as the idempotency key when no Idempotency-Key
+// header is sent, and ttl is the number of seconds after delivery time the
+// notification stays worth sending. locale overrides the recipient's
+// preferred locale when rendering a template.
 type SendNotificationRequest struct {
-	UserID  uuid.UUID `json:"user_id" binding:"required"`
-	Type    string    `json:"type" binding:"required,oneof=status news marketing"`
-	Message string    `json:"message" binding:"required"`
+	ID              *uuid.UUID        `json:"id,omitempty"`
+	UserID          uuid.UUID         `json:"user_id" binding:"required"`
+	Type            string            `json:"type" binding:"required,oneof=status news marketing"`
+	Title           string            `json:"title,omitempty" binding:"max=255"`
+	Message         string            `json:"message" binding:"required_without=TemplateID,excluded_with=TemplateID"`
+	ActionURL       string            `json:"action_url,omitempty" binding:"omitempty,url"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	TemplateID      *uuid.UUID        `json:"template_id,omitempty"`
+	TemplateVersion *int              `json:"template_version,omitempty" binding:"omitempty,min=1"`
+	Data            map[string]any    `json:"data,omitempty"`
+	Locale          string            `json:"locale,omitempty"`
+	SendAt          *time.Time        `json:"send_at,omitempty"`
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`
+	TTL             *int              `json:"ttl,omitempty" binding:"omitempty,min=1,excluded_with=ExpiresAt"`
 }
 
-type StatusResponse struct {
-	Status string `json:"status"`
+// Hash fingerprints the request so that a reused idempotency key with a
+// different body can be detected.
+func (r SendNotificationRequest) Hash() string {
+	b, _ := json.Marshal(r)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+type SendNotificationResponse struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+// ListNotificationsQuery filters a user's notifications. type and status
+// may be repeated, from is inclusive and to exclusive, read and archived
+// select by inbox state, and cursor is the next_cursor of a previous page.
+type ListNotificationsQuery struct {
+	Type     []string  `form:"type" binding:"dive,oneof=status news marketing"`
+	Status   []string  `form:"status" binding:"dive,oneof=scheduled sending sent rate_limited canceled expired duplicate held digested"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Read     *bool     `form:"read"`
+	Archived *bool     `form:"archived"`
+	Limit    int       `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor   string    `form:"cursor"`
+}
+
+type NotificationResponse struct {
+	ID              uuid.UUID         `json:"id"`
+	UserID          uuid.UUID         `json:"user_id"`
+	Type            string            `json:"type"`
+	Status          string            `json:"status"`
+	StatusReason    string            `json:"status_reason,omitempty"`
+	Title           string            `json:"title,omitempty"`
+	Message         string            `json:"message"`
+	ActionURL       string            `json:"action_url,omitempty"`
+	Metadata        map[string]string `json:"metadata,omitempty"`
+	Locale          string            `json:"locale,omitempty"`
+	TemplateID      *uuid.UUID        `json:"template_id,omitempty"`
+	TemplateVersion int               `json:"template_version,omitempty"`
+	SendAt          *time.Time        `json:"send_at,omitempty"`
+	SentAt          *time.Time        `json:"sent_at,omitempty"`
+	ExpiresAt       *time.Time        `json:"expires_at,omitempty"`
+	ReadAt          *time.Time        `json:"read_at,omitempty"`
+	ArchivedAt      *time.Time        `json:"archived_at,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+}
+
+func newNotificationResponse(n entity.Notification) NotificationResponse {
+	return NotificationResponse{
+		ID:              n.ID,
+		UserID:          n.UserID,
+		Type:            string(n.Type),
+		Status:          string(n.Status),
+		StatusReason:    n.StatusReason,
+		Title:           n.Title,
+		Message:         n.Message,
+		ActionURL:       n.ActionURL,
+		Metadata:        n.Metadata,
+		Locale:          n.Locale,
+		TemplateID:      n.TemplateID,
+		TemplateVersion: n.TemplateVersion,
+		SendAt:          n.SendAt,
+		SentAt:          n.SentAt,
+		ExpiresAt:       n.ExpiresAt,
+		ReadAt:          n.ReadAt,
+		ArchivedAt:      n.ArchivedAt,
+		CreatedAt:       n.CreatedAt,
+	}
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	NextCursor    string                 `json:"next_cursor,omitempty"`
+}
+
+// BulkSendRequest carries either items, one notification each, or a single
+// notification in type, title, message, action_url and metadata addressed
+// to every user in user_ids.
+type BulkSendRequest struct {
+	Items     []BulkSendItem    `json:"items,omitempty" binding:"max=1000"`
+	UserIDs   []uuid.UUID       `json:"user_ids,omitempty" binding:"max=1000"`
+	Type      string            `json:"type,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Message   string            `json:"message,omitempty"`
+	ActionURL string            `json:"action_url,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// BulkSendItem is validated on its own so that one bad item does not fail
+// the whole request.
+type BulkSendItem struct {
+	UserID    uuid.UUID         `json:"user_id" binding:"required"`
+	Type      string            `json:"type" binding:"required,oneof=status news marketing"`
+	Title     string            `json:"title,omitempty" binding:"max=255"`
+	Message   string            `json:"message" binding:"required"`
+	ActionURL string            `json:"action_url,omitempty" binding:"omitempty,url"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+}
+
+// BulkSendResult reports one item, by its position in the request. status
+// is sent, held, rate_limited, invalid or failed; id is set when the
+// notification was stored. Items that were not sent carry the code and
+// message of their error, as in a problem, and invalid ones the fields at
+// fault.
+type BulkSendResult struct {
+	Index  int                  `json:"index"`
+	UserID uuid.UUID            `json:"user_id"`
+	ID     *uuid.UUID           `json:"id,omitempty"`
+	Status string               `json:"status"`
+	Code   string               `json:"code,omitempty"`
+	Error  string               `json:"error,omitempty"`
+	Errors []problem.FieldError `json:"errors,omitempty"`
 }
 
-type ErrorResponse struct {
-	Error string `json:"error"`
+func (r *BulkSendResult) setError(err error) {
+	p := problem.New(err)
+	r.Code = p.Code
+	r.Error = p.Title
+	if p.Detail != "" {
+		r.Error = p.Detail
+	}
+	r.Errors = p.Errors
+}
+
+type BulkSendResponse struct {
+	Results []BulkSendResult `json:"results"`
+}
+
+type MarkReadRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
+}
+
+type MarkReadResponse struct {
+	Updated int `json:"updated"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+// cursorToken is the JSON form of an entity.NotificationCursor inside the
+// opaque cursor handed to clients.
+type cursorToken struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"i"`
+}
+
+func encodeCursor(c entity.NotificationCursor) string {
+	b, _ := json.Marshal(cursorToken{CreatedAt: c.CreatedAt, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (entity.NotificationCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return entity.NotificationCursor{}, errs.ErrInvalidCursor
+	}
+	var tok cursorToken
+	if err := json.Unmarshal(b, &tok); err != nil || tok.ID == uuid.Nil {
+		return entity.NotificationCursor{}, errs.ErrInvalidCursor
+	}
+	return entity.NotificationCursor{CreatedAt: tok.CreatedAt, ID: tok.ID}, nil
+}
+
+type StatusResponse struct {
+	Status string `json:"status"`
 }
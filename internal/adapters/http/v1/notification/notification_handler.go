@@ -1,9 +1,10 @@
 package notification
 
 import (
-	"log"
 	"net/http"
+	"time"
 
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
 	"github.com/Paulooo0/modak-challenge/internal/config/errs"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
@@ -11,57 +12,379 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	headerIdempotencyKey = "Idempotency-Key"
+	headerLastEventID    = "Last-Event-ID"
+
+	defaultHeartbeat = 15 * time.Second
+	replayPageSize   = 100
+)
+
 type NotificationHandler struct {
-	uc *usecase.NotificationUseCase
+	uc        *usecase.NotificationUseCase
+	heartbeat time.Duration
+	sessions  SessionAcceptor
 }
 
 func NewNotificationHandler(uc *usecase.NotificationUseCase) *NotificationHandler {
-	return &NotificationHandler{uc: uc}
+	return &NotificationHandler{uc: uc, heartbeat: defaultHeartbeat}
 }
 
 // SendNotification godoc
 // @Summary Send a notification
-// @Description Sends a notification to a user respecting per-type rate limits
+// @Description Sends a notification to a user respecting per-type rate limits. When send_at is in the future the notification is scheduled and rate limits are applied at delivery time. A notification past its expires_at or ttl is recorded as expired instead of being delivered. Instead of message, template_id and data render a stored template in the recipient's preferred locale.
 // @Tags notifications
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Key that makes retries of the same request return the original result"
 // @Param request body SendNotificationRequest true "Notification payload"
-// @Success 201 {object} StatusResponse
-// @Failure 400 {object} ErrorResponse
-// @Failure 429 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Success 201 {object} SendNotificationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Failure 429 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
 // @Router /v1/notifications/send [post]
 func (h *NotificationHandler) SendNotification(c *gin.Context) {
 	var req SendNotificationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		problem.Bind(c, err)
 		return
 	}
 	if !entity.IsValidNotificationType(entity.NotificationType(req.Type)) {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: errs.ErrInvalidNotification.Error()})
+		c.Error(errs.ErrInvalidNotification)
 		return
 	}
 
 	n := entity.Notification{
-		ID:      uuid.New(),
-		UserID:  req.UserID,
-		Type:    entity.NotificationType(req.Type),
-		Message: req.Message,
+		UserID:         req.UserID,
+		Type:           entity.NotificationType(req.Type),
+		Title:          req.Title,
+		Message:        req.Message,
+		ActionURL:      req.ActionURL,
+		Metadata:       req.Metadata,
+		SendAt:         utc(req.SendAt),
+		ExpiresAt:      utc(req.ExpiresAt),
+		IdempotencyKey: c.GetHeader(headerIdempotencyKey),
+		RequestHash:    req.Hash(),
+		TemplateID:     req.TemplateID,
+		TemplateData:   req.Data,
+	}
+	if req.Locale != "" {
+		locale, ok := entity.NormalizeLocale(req.Locale)
+		if !ok {
+			c.Error(errs.ErrInvalidLocale)
+			return
+		}
+		n.Locale = locale
+	}
+	if req.TemplateVersion != nil {
+		n.TemplateVersion = *req.TemplateVersion
+	}
+	if req.ID != nil {
+		n.ID = *req.ID
+		if n.IdempotencyKey == "" {
+			n.IdempotencyKey = req.ID.String()
+		}
+	}
+	if req.TTL != nil {
+		n.ApplyTTL(time.Duration(*req.TTL)*time.Second, time.Now().UTC())
 	}
 
-	err := h.uc.Send(c.Request.Context(), n)
+	saved, err := h.uc.Send(c.Request.Context(), n)
 	if err != nil {
-		log.Println(err)
-		switch err {
-		case errs.ErrRateLimitExceeded:
-			c.JSON(http.StatusTooManyRequests, ErrorResponse{Error: errs.ErrRateLimitExceeded.Error()})
-			return
-		case errs.ErrInvalidNotification:
-			c.JSON(http.StatusBadRequest, ErrorResponse{Error: errs.ErrInvalidNotification.Error()})
-			return
-		default:
-			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SendNotificationResponse{ID: saved.ID, Status: string(saved.Status)})
+}
+
+// CancelNotification godoc
+// @Summary Cancel a scheduled notification
+// @Description Cancels a notification that is scheduled and has not been sent yet
+// @Tags notifications
+// @Security BearerAuth
+// @Param id path string true "Notification ID"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/notifications/{id} [delete]
+func (h *NotificationHandler) CancelNotification(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrInvalidNotification)
+		return
+	}
+
+	err = h.uc.Cancel(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListUserNotifications godoc
+// @Summary List a user's notifications
+// @Description Returns a user's notifications newest first. Pass next_cursor back as cursor to get the following page.
+// @Tags notifications
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param type query []string false "Notification type" collectionFormat(multi)
+// @Param status query []string false "Notification status" collectionFormat(multi)
+// @Param from query string false "Created at or after (RFC 3339)"
+// @Param to query string false "Created before (RFC 3339)"
+// @Param read query bool false "Only read (true) or unread (false) notifications"
+// @Param archived query bool false "Only archived (true) or inbox (false) notifications"
+// @Param limit query int false "Page size (1-100, default 20)"
+// @Param cursor query string false "Cursor from a previous page"
+// @Success 200 {object} ListNotificationsResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications [get]
+func (h *NotificationHandler) ListUserNotifications(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var q ListNotificationsQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	filter := entity.NotificationFilter{UserID: userID, Limit: q.Limit}
+	for _, t := range q.Type {
+		filter.Types = append(filter.Types, entity.NotificationType(t))
+	}
+	for _, s := range q.Status {
+		filter.Statuses = append(filter.Statuses, entity.NotificationStatus(s))
+	}
+	if !q.From.IsZero() {
+		filter.From = utc(&q.From)
+	}
+	if !q.To.IsZero() {
+		filter.To = utc(&q.To)
+	}
+	filter.Read = q.Read
+	filter.Archived = q.Archived
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		c.Error(errs.InvalidField("from", "must be before to"))
+		return
+	}
+	if q.Cursor != "" {
+		cursor, err := decodeCursor(q.Cursor)
+		if err != nil {
+			c.Error(err)
 			return
 		}
+		filter.After = &cursor
+	}
+
+	page, err := h.uc.List(c.Request.Context(), filter)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := ListNotificationsResponse{Notifications: make([]NotificationResponse, 0, len(page.Notifications))}
+	for _, n := range page.Notifications {
+		resp.Notifications = append(resp.Notifications, newNotificationResponse(n))
+	}
+	if page.Next != nil {
+		resp.NextCursor = encodeCursor(*page.Next)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// UnreadCount godoc
+// @Summary Count unread notifications
+// @Description Returns how many delivered notifications of a user are neither read nor archived
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} UnreadCountResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.uc.CountUnread(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
 	}
 
-	c.JSON(http.StatusCreated, StatusResponse{Status: "sent"})
+	c.JSON(http.StatusOK, UnreadCountResponse{Unread: count})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Description Marks a sent notification of a user as read. Notifications that are scheduled, held or were never delivered are not found.
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, id, ok := notificationParams(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.uc.MarkRead(c.Request.Context(), userID, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationResponse(n))
+}
+
+// MarkManyRead godoc
+// @Summary Mark notifications as read
+// @Description Marks the given sent notifications of a user as read. Unknown, unsent or already read ids are ignored.
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param request body MarkReadRequest true "Notification IDs"
+// @Success 200 {object} MarkReadResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/read [post]
+func (h *NotificationHandler) MarkManyRead(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req MarkReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	count, err := h.uc.MarkManyRead(c.Request.Context(), userID, req.IDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkReadResponse{Updated: count})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} MarkReadResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	count, err := h.uc.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkReadResponse{Updated: count})
+}
+
+// ArchiveNotification godoc
+// @Summary Archive a notification
+// @Description Moves a sent notification out of the inbox. Archived notifications do not count as unread. Notifications that are scheduled, held or were never delivered are not found.
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/{id}/archive [post]
+func (h *NotificationHandler) ArchiveNotification(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveNotification godoc
+// @Summary Restore an archived notification
+// @Description Moves an archived notification back into the inbox. Notifications that were never delivered are not found.
+// @Tags inbox
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param id path string true "Notification ID"
+// @Success 200 {object} NotificationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/{id}/archive [delete]
+func (h *NotificationHandler) UnarchiveNotification(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *NotificationHandler) setArchived(c *gin.Context, archived bool) {
+	userID, id, ok := notificationParams(c)
+	if !ok {
+		return
+	}
+
+	n, err := h.uc.Archive(c.Request.Context(), userID, id, archived)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newNotificationResponse(n))
+}
+
+func userIDParam(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.Error(errs.InvalidField("user_id", "must be a UUID"))
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func notificationParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrInvalidNotification)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// utc converts t to UTC. Timestamps are stored without a time zone, so an
+// offset left on them would be dropped rather than applied.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
 }
@@ -7,9 +7,12 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
 	"github.com/gin-gonic/gin"
@@ -30,9 +33,95 @@ func (m *MockRepo) CountInTimeWindow(ctx context.Context, userID uuid.UUID, noti
 	return args.Get(0).(int), args.Error(1)
 }
 
+func (m *MockRepo) CreateMany(ctx context.Context, ns []entity.Notification) error {
+	args := m.Called(ctx, ns)
+	return args.Error(0)
+}
+
+func (m *MockRepo) CountInTimeWindowByUser(ctx context.Context, userIDs []uuid.UUID, notifType entity.NotificationType, since time.Time) (map[uuid.UUID]int, error) {
+	args := m.Called(ctx, userIDs, notifType, since)
+	counts, _ := args.Get(0).(map[uuid.UUID]int)
+	return counts, args.Error(1)
+}
+
+func (m *MockRepo) FindDuplicate(ctx context.Context, n entity.Notification, since time.Time) (entity.Notification, error) {
+	args := m.Called(ctx, n, since)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) GetByID(ctx context.Context, id uuid.UUID) (entity.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) GetByIdempotencyKey(ctx context.Context, key string) (entity.Notification, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ReleaseIdempotencyKey(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepo) ClaimDue(ctx context.Context, now, leasedUntil time.Time, limit int) ([]entity.Notification, error) {
+	args := m.Called(ctx, now, leasedUntil, limit)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ListHeldGroups(ctx context.Context, after entity.DigestGroup, limit int) ([]entity.DigestGroup, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]entity.DigestGroup), args.Error(1)
+}
+
+func (m *MockRepo) ClaimHeld(ctx context.Context, g entity.DigestGroup, reason string) ([]entity.Notification, error) {
+	args := m.Called(ctx, g, reason)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) List(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ListSentAfter(ctx context.Context, last entity.Notification, limit int) ([]entity.Notification, error) {
+	args := m.Called(ctx, last, limit)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) MarkRead(ctx context.Context, userID, id uuid.UUID) (entity.Notification, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) MarkManyRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) SetArchived(ctx context.Context, userID, id uuid.UUID, archived bool) (entity.Notification, error) {
+	args := m.Called(ctx, userID, id, archived)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.NotificationStatus, reason string, at time.Time) (entity.Notification, error) {
+	args := m.Called(ctx, id, from, to, reason, at)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
 type MockGateway struct{ mock.Mock }
 
-func (m *MockGateway) Send(n entity.Notification) error {
+func (m *MockGateway) Send(_ context.Context, n entity.Notification) error {
 	args := m.Called(n)
 	return args.Error(0)
 }
@@ -44,14 +133,19 @@ func buildHandler(repo *MockRepo, gw *MockGateway, rules map[entity.Notification
 
 const (
 	pathSend          = "/v1/notifications/send"
+	pathCancel        = "/v1/notifications/:id"
+	pathList          = "/v1/users/:user_id/notifications"
 	headerContentType = "Content-Type"
 	contentTypeJSON   = "application/json"
 )
 
 type sendPayload struct {
-	UserID  uuid.UUID `json:"user_id"`
-	Type    string    `json:"type"`
-	Message string    `json:"message"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message"`
+	SendAt    *time.Time `json:"send_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	TTL       *int       `json:"ttl,omitempty"`
 }
 
 func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
@@ -77,6 +171,7 @@ func TestSendNotificationSuccess(t *testing.T) {
 	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
 
 	r := gin.New()
+	r.Use(problem.Handler())
 	w := httptest.NewRecorder()
 	r.POST(pathSend, h.SendNotification)
 
@@ -99,6 +194,7 @@ func TestSendNotificationRateLimited(t *testing.T) {
 	repo.On("CountInTimeWindow", mock.Anything, mock.AnythingOfType("uuid.UUID"), entity.Status, mock.AnythingOfType("time.Time")).Return(1, nil)
 
 	r := gin.New()
+	r.Use(problem.Handler())
 	w := httptest.NewRecorder()
 	r.POST(pathSend, h.SendNotification)
 
@@ -116,6 +212,7 @@ func TestSendNotificationInvalidType(t *testing.T) {
 	h := buildHandler(repo, gw, rules)
 
 	r := gin.New()
+	r.Use(problem.Handler())
 	w := httptest.NewRecorder()
 	r.POST(pathSend, h.SendNotification)
 
@@ -123,6 +220,13 @@ func TestSendNotificationInvalidType(t *testing.T) {
 
 	r.ServeHTTP(w, req)
 	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, problem.ContentType, w.Header().Get("Content-Type"))
+
+	var p problem.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+	require.Equal(t, "invalid_request", p.Code)
+	require.Equal(t, pathSend, p.Instance)
+	require.Equal(t, []problem.FieldError{{Field: "type", Message: "must be one of status, news, marketing"}}, p.Errors)
 }
 
 func TestSendNotificationInternalError(t *testing.T) {
@@ -137,6 +241,7 @@ func TestSendNotificationInternalError(t *testing.T) {
 	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{}, boom)
 
 	r := gin.New()
+	r.Use(problem.Handler())
 	w := httptest.NewRecorder()
 	r.POST(pathSend, h.SendNotification)
 
@@ -145,3 +250,663 @@ func TestSendNotificationInternalError(t *testing.T) {
 	r.ServeHTTP(w, req)
 	require.Equal(t, http.StatusInternalServerError, w.Code)
 }
+
+func TestSendNotificationScheduled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	rules := map[entity.NotificationType]entity.RateLimit{entity.News: {Limit: 1, Interval: time.Hour}}
+	h := buildHandler(repo, gw, rules)
+
+	id := uuid.New()
+	sendAt := time.Now().Add(time.Hour)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{ID: id, Status: entity.StatusScheduled}, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.News), Message: "hello", SendAt: &sendAt})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp SendNotificationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, id, resp.ID)
+	require.Equal(t, string(entity.StatusScheduled), resp.Status)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationScheduledWithOffset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	sendAt := time.Now().Add(time.Hour).Truncate(time.Second).In(time.FixedZone("BRT", -3*60*60))
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.SendAt != nil && n.SendAt.Location() == time.UTC && n.SendAt.Equal(sendAt)
+	})).Return(entity.Notification{ID: uuid.New(), Status: entity.StatusScheduled}, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	r.ServeHTTP(w, newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.News), Message: "hello", SendAt: &sendAt}))
+	require.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationExpiresAtWithOffset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second).In(time.FixedZone("JST", 9*60*60))
+	repo.On("CountInTimeWindow", mock.Anything, mock.AnythingOfType("uuid.UUID"), entity.Status, mock.AnythingOfType("time.Time")).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.ExpiresAt != nil && n.ExpiresAt.Location() == time.UTC && n.ExpiresAt.Equal(expiresAt)
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	r.ServeHTTP(w, newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.Status), Message: "hello", ExpiresAt: &expiresAt}))
+	require.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationTTLAndExpiresAtConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	ttl := 60
+	expiresAt := time.Now().Add(time.Hour)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.Status), Message: "hello", ExpiresAt: &expiresAt, TTL: &ttl})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSendNotificationWithTTL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	ttl := 120
+	repo.On("CountInTimeWindow", mock.Anything, mock.AnythingOfType("uuid.UUID"), entity.Status, mock.AnythingOfType("time.Time")).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.ExpiresAt != nil && n.ExpiresAt.Location() == time.UTC && n.ExpiresAt.After(time.Now().Add(time.Minute))
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.Status), Message: "hello", TTL: &ttl})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationIdempotencyKeyReplay(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	payload := sendPayload{UserID: uuid.New(), Type: string(entity.Status), Message: "hello"}
+	hash := SendNotificationRequest{UserID: payload.UserID, Type: payload.Type, Message: payload.Message}.Hash()
+	original := entity.Notification{ID: uuid.New(), Status: entity.StatusSent, IdempotencyKey: "key-1", RequestHash: hash, CreatedAt: time.Now()}
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(original, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, payload)
+	req.Header.Set(headerIdempotencyKey, "key-1")
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp SendNotificationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, original.ID, resp.ID)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationIdempotencyKeyConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	original := entity.Notification{ID: uuid.New(), Status: entity.StatusSent, IdempotencyKey: "key-1", RequestHash: "another body", CreatedAt: time.Now()}
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(original, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, sendPayload{UserID: uuid.New(), Type: string(entity.Status), Message: "hello"})
+	req.Header.Set(headerIdempotencyKey, "key-1")
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestSendNotificationStructuredPayload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	repo.On("CountInTimeWindow", mock.Anything, mock.AnythingOfType("uuid.UUID"), entity.Status, mock.AnythingOfType("time.Time")).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Title == "Order shipped" && n.ActionURL == "https://example.com/orders/42" && n.Metadata["order_id"] == "42"
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, map[string]any{
+		"user_id":    uuid.New(),
+		"type":       string(entity.Status),
+		"title":      "Order shipped",
+		"message":    "Your order is on its way",
+		"action_url": "https://example.com/orders/42",
+		"metadata":   map[string]string{"order_id": "42"},
+	})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationInvalidActionURL(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, map[string]any{
+		"user_id":    uuid.New(),
+		"type":       string(entity.Status),
+		"message":    "hello",
+		"action_url": "not a url",
+	})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+type MockTemplateRepo struct{ mock.Mock }
+
+func (m *MockTemplateRepo) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) List(ctx context.Context) ([]entity.Template, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Template), args.Error(1)
+}
+
+func TestSendNotificationTemplateMissingVariable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	templates := new(MockTemplateRepo)
+	h := NewNotificationHandler(usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTemplates(templates)))
+
+	id := uuid.New()
+	templates.On("Get", mock.Anything, id, 0).Return(entity.Template{
+		ID:        id,
+		Version:   1,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, mock.AnythingOfType("uuid.UUID"), entity.Status, mock.AnythingOfType("time.Time")).Return(0, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.POST(pathSend, h.SendNotification)
+
+	req := newJSONRequest(t, http.MethodPost, pathSend, map[string]any{
+		"user_id":     uuid.New(),
+		"type":        string(entity.Status),
+		"template_id": id,
+		"data":        map[string]any{},
+	})
+
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "name")
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCancelNotification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	id := uuid.New()
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{ID: id, Status: entity.StatusCanceled}, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.DELETE(pathCancel, h.CancelNotification)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/notifications/"+id.String(), nil))
+	require.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestCancelNotificationConflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	id := uuid.New()
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByID", mock.Anything, id).Return(entity.Notification{ID: id, Status: entity.StatusSent}, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.DELETE(pathCancel, h.CancelNotification)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/notifications/"+id.String(), nil))
+	require.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestCancelNotificationNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	id := uuid.New()
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByID", mock.Anything, id).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.DELETE(pathCancel, h.CancelNotification)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/notifications/"+id.String(), nil))
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListUserNotifications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	userID := uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []entity.Notification{
+		{ID: uuid.New(), UserID: userID, Type: entity.News, Status: entity.StatusSent, Message: "a", CreatedAt: from.Add(2 * time.Hour)},
+		{ID: uuid.New(), UserID: userID, Type: entity.News, Status: entity.StatusSent, Message: "b", CreatedAt: from.Add(time.Hour)},
+	}
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f entity.NotificationFilter) bool {
+		return f.UserID == userID && f.Limit == 2 && len(f.Types) == 2 && f.From != nil && f.From.Equal(from) && f.After == nil
+	})).Return(rows, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.GET(pathList, h.ListUserNotifications)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/notifications?type=news&type=status&from=2025-01-01T00:00:00Z&limit=1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp ListNotificationsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Notifications, 1)
+	require.NotEmpty(t, resp.NextCursor)
+
+	cursor, err := decodeCursor(resp.NextCursor)
+	require.NoError(t, err)
+	require.Equal(t, rows[0].ID, cursor.ID)
+	require.True(t, rows[0].CreatedAt.Equal(cursor.CreatedAt))
+}
+
+func TestListUserNotificationsRangeWithOffset(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	userID := uuid.New()
+	from := time.Date(2025, 12, 31, 22, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	repo.On("List", mock.Anything, mock.MatchedBy(func(f entity.NotificationFilter) bool {
+		return f.From != nil && f.From.Location() == time.UTC && f.From.Equal(from) &&
+			f.To != nil && f.To.Location() == time.UTC && f.To.Equal(to)
+	})).Return([]entity.Notification{}, nil)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.GET(pathList, h.ListUserNotifications)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/notifications?from=2026-01-01T00:00:00%2B02:00&to=2025-12-31T22:00:00-05:00", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestListUserNotificationsInvalidCursor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	h := buildHandler(repo, gw, entity.DefaultRateLimits)
+
+	r := gin.New()
+	r.Use(problem.Handler())
+	w := httptest.NewRecorder()
+	r.GET(pathList, h.ListUserNotifications)
+
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+uuid.NewString()+"/notifications?cursor=garbage", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+}
+
+func newInboxRouter(repo *MockRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	uc := usecase.NewNotificationUseCase(repo, new(MockGateway), entity.DefaultRateLimits)
+	RegisterInboxRoutes(r.Group("/v1"), uc)
+	RegisterStreamRoutes(r.Group("/v1"), uc, time.Second, nil)
+	return r
+}
+
+func TestUnreadCount(t *testing.T) {
+	repo := new(MockRepo)
+	userID := uuid.New()
+	repo.On("CountUnread", mock.Anything, userID).Return(7, nil)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/notifications/unread-count", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"unread":7}`, w.Body.String())
+}
+
+func TestMarkManyRead(t *testing.T) {
+	repo := new(MockRepo)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	repo.On("MarkManyRead", mock.Anything, userID, ids).Return(1, nil)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/users/"+userID.String()+"/notifications/read", MarkReadRequest{IDs: ids}))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"updated":1}`, w.Body.String())
+}
+
+func TestMarkAllRead(t *testing.T) {
+	repo := new(MockRepo)
+	userID := uuid.New()
+	repo.On("MarkAllRead", mock.Anything, userID).Return(3, nil)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/users/"+userID.String()+"/notifications/read-all", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"updated":3}`, w.Body.String())
+}
+
+func TestMarkReadNotFound(t *testing.T) {
+	repo := new(MockRepo)
+	userID, id := uuid.New(), uuid.New()
+	repo.On("MarkRead", mock.Anything, userID, id).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/users/"+userID.String()+"/notifications/"+id.String()+"/read", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestArchiveNotification(t *testing.T) {
+	repo := new(MockRepo)
+	userID, id := uuid.New(), uuid.New()
+	now := time.Now()
+	repo.On("SetArchived", mock.Anything, userID, id, true).Return(entity.Notification{ID: id, UserID: userID, ArchivedAt: &now}, nil)
+	repo.On("SetArchived", mock.Anything, userID, id, false).Return(entity.Notification{ID: id, UserID: userID}, nil)
+
+	r := newInboxRouter(repo)
+	path := "/v1/users/" + userID.String() + "/notifications/" + id.String() + "/archive"
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), "archived_at")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "archived_at")
+
+	repo.AssertExpectations(t)
+}
+
+func TestArchiveNotificationNotFound(t *testing.T) {
+	repo := new(MockRepo)
+	userID, id := uuid.New(), uuid.New()
+	repo.On("SetArchived", mock.Anything, userID, id, true).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/users/"+userID.String()+"/notifications/"+id.String()+"/archive", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// fakeStream hands every subscriber the same pre-filled channel.
+type fakeStream struct{ ch chan entity.Notification }
+
+func (s *fakeStream) Publish(n entity.Notification) { s.ch <- n }
+
+func (s *fakeStream) Subscribe(entity.TenantID, uuid.UUID) (<-chan entity.Notification, func()) {
+	return s.ch, func() {}
+}
+
+func TestStreamNotificationsResumesAndStreams(t *testing.T) {
+	repo := new(MockRepo)
+	userID := uuid.New()
+	sentAt := time.Now()
+	last := entity.Notification{ID: uuid.New(), UserID: userID, Status: entity.StatusSent, SentAt: &sentAt}
+	missed := entity.Notification{ID: uuid.New(), UserID: userID, Status: entity.StatusSent, Message: "missed"}
+	live := entity.Notification{ID: uuid.New(), UserID: userID, Status: entity.StatusSent, Message: "live"}
+
+	repo.On("GetByID", mock.Anything, last.ID).Return(last, nil)
+	repo.On("ListSentAfter", mock.Anything, last, 100).Return([]entity.Notification{missed}, nil)
+
+	stream := &fakeStream{ch: make(chan entity.Notification, 2)}
+	stream.ch <- missed
+	stream.ch <- live
+	close(stream.ch)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterStreamRoutes(r.Group("/v1"), usecase.NewNotificationUseCase(repo, new(MockGateway), entity.DefaultRateLimits, usecase.WithStream(stream)), time.Minute, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/notifications/stream", nil)
+	req.Header.Set(headerLastEventID, last.ID.String())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	require.Equal(t, 1, strings.Count(body, "id: "+missed.ID.String()))
+	require.Contains(t, body, "id: "+live.ID.String())
+	require.Less(t, strings.Index(body, missed.ID.String()), strings.Index(body, live.ID.String()))
+}
+
+func TestStreamNotificationsUnavailable(t *testing.T) {
+	repo := new(MockRepo)
+
+	w := httptest.NewRecorder()
+	newInboxRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+uuid.NewString()+"/notifications/stream", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+type fakeAcceptor struct{ userID uuid.UUID }
+
+func (a *fakeAcceptor) Accept(w http.ResponseWriter, _ *http.Request, userID uuid.UUID) error {
+	a.userID = userID
+	w.WriteHeader(http.StatusSwitchingProtocols)
+	return nil
+}
+
+func TestConnectWebSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	sessions := &fakeAcceptor{}
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterStreamRoutes(r.Group("/v1"), usecase.NewNotificationUseCase(new(MockRepo), new(MockGateway), entity.DefaultRateLimits), time.Second, sessions)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+userID.String()+"/notifications/ws", nil))
+	require.Equal(t, http.StatusSwitchingProtocols, w.Code)
+	require.Equal(t, userID, sessions.userID)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/nope/notifications/ws", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestConnectWebSocketUnavailable(t *testing.T) {
+	w := httptest.NewRecorder()
+	newInboxRouter(new(MockRepo)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+uuid.New().String()+"/notifications/ws", nil))
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func postBulk(t *testing.T, repo *MockRepo, gw *MockGateway, body string) (*httptest.ResponseRecorder, BulkSendResponse) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterNotificationRoutes(r.Group("/v1"), usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/notifications/bulk", strings.NewReader(body)))
+	var resp BulkSendResponse
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	}
+	return w, resp
+}
+
+func TestSendBulkItems(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	alice, bob := uuid.New(), uuid.New()
+
+	// news allows one per day: bob's second item is over the limit.
+	repo.On("CountInTimeWindowByUser", mock.Anything, []uuid.UUID{alice, bob}, entity.News, mock.Anything).Return(map[uuid.UUID]int{}, nil)
+	repo.On("CreateMany", mock.Anything, mock.MatchedBy(func(ns []entity.Notification) bool { return len(ns) == 2 })).Return(nil)
+	gw.On("Send", mock.Anything).Return(nil).Twice()
+
+	body := `{"items":[
+		{"user_id":"` + alice.String() + `","type":"news","message":"hello"},
+		{"user_id":"` + bob.String() + `","type":"news","message":"hello"},
+		{"user_id":"` + bob.String() + `","type":"news","message":"again"},
+		{"user_id":"` + bob.String() + `","type":"news"}
+	]}`
+	w, resp := postBulk(t, repo, gw, body)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, resp.Results, 4)
+	require.Equal(t, "sent", resp.Results[0].Status)
+	require.NotNil(t, resp.Results[0].ID)
+	require.Equal(t, "sent", resp.Results[1].Status)
+	require.Equal(t, "rate_limited", resp.Results[2].Status)
+	require.Nil(t, resp.Results[2].ID)
+	require.Equal(t, "invalid", resp.Results[3].Status)
+	require.Equal(t, 3, resp.Results[3].Index)
+	require.NotEmpty(t, resp.Results[3].Error)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestSendBulkUserIDs(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	alice, bob := uuid.New(), uuid.New()
+
+	repo.On("CountInTimeWindowByUser", mock.Anything, []uuid.UUID{alice, bob}, entity.Status, mock.Anything).Return(map[uuid.UUID]int{}, nil)
+	repo.On("CreateMany", mock.Anything, mock.MatchedBy(func(ns []entity.Notification) bool {
+		return len(ns) == 2 && ns[0].Title == "Maintenance" && ns[1].UserID == bob
+	})).Return(nil)
+	gw.On("Send", mock.Anything).Return(nil).Twice()
+
+	body := `{"user_ids":["` + alice.String() + `","` + bob.String() + `"],"type":"status","title":"Maintenance","message":"Back at 10"}`
+	w, resp := postBulk(t, repo, gw, body)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, resp.Results, 2)
+	require.Equal(t, alice, resp.Results[0].UserID)
+	require.Equal(t, "sent", resp.Results[1].Status)
+	repo.AssertExpectations(t)
+}
+
+func TestSendBulkRejectsMalformedRequest(t *testing.T) {
+	userID := uuid.New().String()
+	for _, body := range []string{
+		`{}`,
+		`{"user_ids":["` + userID + `"],"items":[{"user_id":"` + userID + `","type":"news","message":"hi"}],"type":"news","message":"hi"}`,
+	} {
+		w, _ := postBulk(t, new(MockRepo), new(MockGateway), body)
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
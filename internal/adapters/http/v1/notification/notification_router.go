@@ -1,15 +1,53 @@
 package notification
 
 import (
+	"time"
+
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
 	"github.com/gin-gonic/gin"
 )
 
+// RegisterNotificationRoutes registers the routes that send and cancel
+// notifications.
 func RegisterNotificationRoutes(r *gin.RouterGroup, uc *usecase.NotificationUseCase) {
 	h := NewNotificationHandler(uc)
 
 	api := r.Group("/notifications")
 	{
 		api.POST("/send", h.SendNotification)
+		api.POST("/bulk", h.SendBulk)
+		api.DELETE("/:id", h.CancelNotification)
+	}
+}
+
+// RegisterInboxRoutes registers the routes that read a user's
+// notifications and manage their inbox state.
+func RegisterInboxRoutes(r *gin.RouterGroup, uc *usecase.NotificationUseCase) {
+	h := NewNotificationHandler(uc)
+
+	users := r.Group("/users/:user_id/notifications")
+	{
+		users.GET("", h.ListUserNotifications)
+		users.GET("/unread-count", h.UnreadCount)
+		users.POST("/read", h.MarkManyRead)
+		users.POST("/read-all", h.MarkAllRead)
+		users.POST("/:id/read", h.MarkRead)
+		users.POST("/:id/archive", h.ArchiveNotification)
+		users.DELETE("/:id/archive", h.UnarchiveNotification)
+	}
+}
+
+// RegisterStreamRoutes registers the routes that push a user's
+// notifications as they are delivered, over Server-Sent Events and
+// WebSocket.
+func RegisterStreamRoutes(r *gin.RouterGroup, uc *usecase.NotificationUseCase, heartbeat time.Duration, sessions SessionAcceptor) {
+	h := NewNotificationHandler(uc)
+	h.heartbeat = heartbeat
+	h.sessions = sessions
+
+	users := r.Group("/users/:user_id/notifications")
+	{
+		users.GET("/stream", h.StreamNotifications)
+		users.GET("/ws", h.ConnectWebSocket)
 	}
 }
@@ -0,0 +1,124 @@
+package notification
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StreamNotifications godoc
+// @Summary Stream a user's notifications
+// @Description Pushes each notification delivered to the user as a Server-Sent Event named "notification" whose id is the notification id. A client reconnecting with Last-Event-ID (or last_event_id) first receives what it missed. A comment line is sent as a heartbeat while idle.
+// @Tags notifications
+// @Security BearerAuth
+// @Produce text/event-stream
+// @Param user_id path string true "User ID"
+// @Param Last-Event-ID header string false "Id of the last notification received"
+// @Param last_event_id query string false "Same as Last-Event-ID, for clients that cannot set headers"
+// @Param access_token query string false "End-user token, for clients that cannot set the Authorization header"
+// @Success 200 {object} NotificationResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 503 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/stream [get]
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+
+	last := c.GetHeader(headerLastEventID)
+	if last == "" {
+		last = c.Query("last_event_id")
+	}
+	var lastID uuid.UUID
+	if last != "" {
+		id, err := uuid.Parse(last)
+		if err != nil {
+			c.Error(errs.InvalidField("last_event_id", "must be a UUID"))
+			return
+		}
+		lastID = id
+	}
+
+	// Subscribe before replaying so nothing delivered in between is lost;
+	// replayed notifications are skipped when they also arrive live.
+	events, unsubscribe, err := h.uc.Subscribe(c.Request.Context(), userID)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).Error("stream subscription failed", "err", err)
+		c.Error(errs.ErrStreamUnavailable)
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	ctx := c.Request.Context()
+	replayed := make(map[uuid.UUID]bool)
+	for lastID != uuid.Nil {
+		batch, err := h.uc.Replay(ctx, userID, lastID, replayPageSize)
+		if errors.Is(err, errs.ErrNotificationNotFound) {
+			break
+		}
+		if err != nil {
+			logging.FromContext(ctx).Error("stream replay failed", "err", err)
+			return
+		}
+		for _, n := range batch {
+			if err := writeEvent(c.Writer, newNotificationResponse(n)); err != nil {
+				return
+			}
+			replayed[n.ID] = true
+		}
+		if len(batch) < replayPageSize {
+			break
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-events:
+			if !ok {
+				return
+			}
+			if replayed[n.ID] {
+				continue
+			}
+			if err := writeEvent(c.Writer, newNotificationResponse(n)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func writeEvent(w io.Writer, n NotificationResponse) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", n.ID, data)
+	return err
+}
@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SessionAcceptor takes over a request as a live delivery session for a
+// user, such as a WebSocket connection, and returns once it ends.
+type SessionAcceptor interface {
+	Accept(w http.ResponseWriter, r *http.Request, userID uuid.UUID) error
+}
+
+// ConnectWebSocket godoc
+// @Summary Receive a user's notifications over WebSocket
+// @Description Upgrades to a WebSocket on which each notification delivered to the user arrives as a text message {"event":"notification","data":{...}}. A user may hold several sessions and each receives every notification. The server pings periodically and disconnects sessions that stop answering or fall too far behind. There is no replay; after reconnecting, catch up from the notification list.
+// @Tags notifications
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param access_token query string false "End-user token, for clients that cannot set the Authorization header"
+// @Success 101
+// @Failure 400 {object} problem.Problem
+// @Failure 503 {object} problem.Problem
+// @Router /v1/users/{user_id}/notifications/ws [get]
+func (h *NotificationHandler) ConnectWebSocket(c *gin.Context) {
+	userID, ok := userIDParam(c)
+	if !ok {
+		return
+	}
+	if h.sessions == nil {
+		c.Error(errs.ErrStreamUnavailable)
+		return
+	}
+
+	if err := h.sessions.Accept(c.Writer, c.Request, userID); err != nil {
+		logging.FromContext(c.Request.Context()).Warn("websocket upgrade failed", "user_id", userID, "err", err)
+	}
+}
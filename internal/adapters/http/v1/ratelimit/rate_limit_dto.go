@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+)
+
+// RateLimitRequest lets at most limit notifications of a type reach a user
+// every interval_seconds.
+type RateLimitRequest struct {
+	Limit           int `json:"limit" binding:"required,min=1" example:"5"`
+	IntervalSeconds int `json:"interval_seconds" binding:"required,min=1" example:"60"`
+}
+
+func (r RateLimitRequest) toEntity(notifType entity.NotificationType) entity.RateLimitRule {
+	return entity.RateLimitRule{
+		Type:      notifType,
+		RateLimit: entity.RateLimit{Limit: r.Limit, Interval: time.Duration(r.IntervalSeconds) * time.Second},
+	}
+}
+
+// RateLimitResponse describes the rate limit in effect for a type. default
+// is true when the tenant has not overridden it.
+type RateLimitResponse struct {
+	Type            string     `json:"type"`
+	Limit           int        `json:"limit"`
+	IntervalSeconds int        `json:"interval_seconds"`
+	Default         bool       `json:"default"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+func newRateLimitResponse(r entity.RateLimitRule) RateLimitResponse {
+	resp := RateLimitResponse{
+		Type:            string(r.Type),
+		Limit:           r.Limit,
+		IntervalSeconds: int(r.Interval / time.Second),
+		Default:         r.Default,
+	}
+	if !r.Default {
+		resp.UpdatedAt = &r.UpdatedAt
+	}
+	return resp
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitHandler struct {
+	uc *usecase.RateLimitRuleUseCase
+}
+
+func NewRateLimitHandler(uc *usecase.RateLimitRuleUseCase) *RateLimitHandler {
+	return &RateLimitHandler{uc: uc}
+}
+
+// ListRateLimits godoc
+// @Summary List rate limits
+// @Description Lists the rate limit in effect for every notification type in the caller's tenant: its own rule, or the default
+// @Tags rate-limits
+// @Security BearerAuth
+// @Success 200 {array} RateLimitResponse
+// @Failure 500 {object} problem.Problem
+// @Router /v1/rate-limits [get]
+func (h *RateLimitHandler) ListRateLimits(c *gin.Context) {
+	rules, err := h.uc.List(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := make([]RateLimitResponse, len(rules))
+	for i, r := range rules {
+		resp[i] = newRateLimitResponse(r)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PutRateLimit godoc
+// @Summary Override a rate limit
+// @Description Overrides, for the caller's tenant, the default rate limit of a notification type
+// @Tags rate-limits
+// @Security BearerAuth
+// @Param type path string true "Notification type" Enums(status, news, marketing)
+// @Param request body RateLimitRequest true "Rate limit payload"
+// @Success 200 {object} RateLimitResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/rate-limits/{type} [put]
+func (h *RateLimitHandler) PutRateLimit(c *gin.Context) {
+	var req RateLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	rule, err := h.uc.Put(c.Request.Context(), req.toEntity(entity.NotificationType(c.Param("type"))))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newRateLimitResponse(rule))
+}
+
+// DeleteRateLimit godoc
+// @Summary Restore a default rate limit
+// @Description Drops the caller's tenant override of a notification type, so that the default rate limit applies again
+// @Tags rate-limits
+// @Security BearerAuth
+// @Param type path string true "Notification type" Enums(status, news, marketing)
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/rate-limits/{type} [delete]
+func (h *RateLimitHandler) DeleteRateLimit(c *gin.Context) {
+	if err := h.uc.Delete(c.Request.Context(), entity.NotificationType(c.Param("type"))); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRateLimitRuleRepo struct{ mock.Mock }
+
+func (m *MockRateLimitRuleRepo) Get(ctx context.Context, notifType entity.NotificationType) (entity.RateLimitRule, error) {
+	args := m.Called(ctx, notifType)
+	return args.Get(0).(entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) List(ctx context.Context) ([]entity.RateLimitRule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) Upsert(ctx context.Context, rule entity.RateLimitRule) (entity.RateLimitRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) Delete(ctx context.Context, notifType entity.NotificationType) error {
+	return m.Called(ctx, notifType).Error(0)
+}
+
+func newRouter(repo *MockRateLimitRuleRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterRateLimitRoutes(r.Group("/v1"), usecase.NewRateLimitRuleUseCase(repo, entity.DefaultRateLimits))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestListRateLimits(t *testing.T) {
+	repo := new(MockRateLimitRuleRepo)
+	repo.On("List", mock.Anything).Return([]entity.RateLimitRule{{
+		Type: entity.News, RateLimit: entity.RateLimit{Limit: 5, Interval: time.Hour}, UpdatedAt: time.Now(),
+	}}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/rate-limits", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp []RateLimitResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp, 3)
+	require.Equal(t, "news", resp[1].Type)
+	require.Equal(t, 3600, resp[1].IntervalSeconds)
+	require.False(t, resp[1].Default)
+	require.True(t, resp[2].Default)
+}
+
+func TestPutRateLimit(t *testing.T) {
+	repo := new(MockRateLimitRuleRepo)
+	rule := entity.RateLimitRule{Type: entity.Status, RateLimit: entity.RateLimit{Limit: 10, Interval: time.Minute}}
+	repo.On("Upsert", mock.Anything, rule).Return(rule, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPut, "/v1/rate-limits/status", RateLimitRequest{
+		Limit:           10,
+		IntervalSeconds: 60,
+	}))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestPutRateLimitRejectsUnknownType(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(new(MockRateLimitRuleRepo)).ServeHTTP(w, newJSONRequest(t, http.MethodPut, "/v1/rate-limits/sms", RateLimitRequest{
+		Limit:           10,
+		IntervalSeconds: 60,
+	}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteRateLimitWithoutOverride(t *testing.T) {
+	repo := new(MockRateLimitRuleRepo)
+	repo.On("Delete", mock.Anything, entity.News).Return(errs.ErrRateLimitRuleNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/v1/rate-limits/news", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
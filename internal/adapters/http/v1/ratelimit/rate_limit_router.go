@@ -0,0 +1,17 @@
+package ratelimit
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRateLimitRoutes(r *gin.RouterGroup, uc *usecase.RateLimitRuleUseCase) {
+	h := NewRateLimitHandler(uc)
+
+	api := r.Group("/rate-limits")
+	{
+		api.GET("", h.ListRateLimits)
+		api.PUT("/:type", h.PutRateLimit)
+		api.DELETE("/:type", h.DeleteRateLimit)
+	}
+}
@@ -1,11 +1,80 @@
 package v1
 
 import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/auth"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/throttle"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/apikey"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/broadcast"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/campaign"
 	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/notification"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/ratelimit"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/segment"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/template"
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/v1/user"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.RouterGroup, uc *usecase.NotificationUseCase) {
-	notification.RegisterNotificationRoutes(r, uc)
+// Dependencies are the use cases and settings the v1 API is served from.
+type Dependencies struct {
+	Notifications   *usecase.NotificationUseCase
+	Templates       *usecase.TemplateUseCase
+	Preferences     *usecase.UserPreferencesUseCase
+	Attributes      *usecase.UserAttributesUseCase
+	Segments        *usecase.SegmentUseCase
+	Broadcasts      *usecase.BroadcastUseCase
+	Campaigns       *usecase.CampaignUseCase
+	APIKeys         *usecase.APIKeyUseCase
+	RateLimits      *usecase.RateLimitRuleUseCase
+	Tokens          auth.TokenVerifier
+	ClientRates     ClientRates
+	StreamHeartbeat time.Duration
+	Sessions        notification.SessionAcceptor
+}
+
+// AccessTokenParam is the query parameter the stream and WebSocket routes
+// take a user token from.
+const AccessTokenParam = "access_token"
+
+// ClientRates limit how fast each client may call the API. IP applies to
+// every request by client IP, before authentication; the others to each
+// API key or user in the send, read and admin route groups.
+type ClientRates struct {
+	IP    throttle.Rate
+	Send  throttle.Rate
+	Read  throttle.Rate
+	Admin throttle.Rate
+}
+
+// RegisterRoutes registers the v1 API behind bearer authentication.
+// Sending needs the notifications:send scope, reading inboxes
+// notifications:read, and everything else admin. End users holding a
+// token from deps.Tokens may only read their own inbox; the send and admin
+// routes take nothing but API keys. Browsers cannot set headers on the
+// stream and WebSocket routes, so those also take a user token in the
+// access_token query parameter. Every request acts
+// on behalf of the tenant of its credentials, and is throttled per client
+// with deps.ClientRates.
+func RegisterRoutes(r *gin.RouterGroup, deps Dependencies) {
+	r.Use(throttle.PerClient(deps.ClientRates.IP))
+	keysOnly := auth.Bearer(deps.APIKeys, nil)
+	send := r.Group("", keysOnly, auth.RequireScope(entity.ScopeNotificationsSend), throttle.PerClient(deps.ClientRates.Send))
+	readOnly := []gin.HandlerFunc{auth.Bearer(deps.APIKeys, deps.Tokens), auth.RequireSelf(entity.ScopeNotificationsRead), throttle.PerClient(deps.ClientRates.Read)}
+	read := r.Group("", readOnly...)
+	stream := r.Group("", append([]gin.HandlerFunc{auth.QueryToken(AccessTokenParam)}, readOnly...)...)
+	admin := r.Group("", keysOnly, auth.RequireScope(entity.ScopeAdmin), throttle.PerClient(deps.ClientRates.Admin))
+
+	notification.RegisterNotificationRoutes(send, deps.Notifications)
+	notification.RegisterInboxRoutes(read, deps.Notifications)
+	notification.RegisterStreamRoutes(stream, deps.Notifications, deps.StreamHeartbeat, deps.Sessions)
+	template.RegisterTemplateRoutes(admin, deps.Templates)
+	user.RegisterUserRoutes(admin, deps.Preferences, deps.Attributes)
+	segment.RegisterSegmentRoutes(admin, deps.Segments)
+	broadcast.RegisterBroadcastRoutes(admin, deps.Broadcasts)
+	campaign.RegisterCampaignRoutes(admin, deps.Campaigns)
+	apikey.RegisterAPIKeyRoutes(admin, deps.APIKeys)
+	ratelimit.RegisterRateLimitRoutes(admin, deps.RateLimits)
 }
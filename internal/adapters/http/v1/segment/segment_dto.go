@@ -0,0 +1,51 @@
+package segment
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// SegmentRequest defines either a static segment by user_ids, or a segment
+// of the users whose attributes include all of attributes and whose tags
+// include all of tags.
+type SegmentRequest struct {
+	Name       string            `json:"name" binding:"required,max=255"`
+	UserIDs    []uuid.UUID       `json:"user_ids,omitempty" binding:"max=10000"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Tags       []string          `json:"tags,omitempty" binding:"max=100"`
+}
+
+func (r SegmentRequest) toEntity() entity.Segment {
+	return entity.Segment{
+		Name:       r.Name,
+		Static:     len(r.UserIDs) > 0,
+		UserIDs:    r.UserIDs,
+		Attributes: r.Attributes,
+		Tags:       r.Tags,
+	}
+}
+
+// SegmentResponse describes a segment. size is how many users it matches
+// now and is only returned when reading a segment.
+type SegmentResponse struct {
+	ID         uuid.UUID         `json:"id"`
+	Name       string            `json:"name"`
+	Static     bool              `json:"static"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	Size       *int              `json:"size,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func newSegmentResponse(s entity.Segment) SegmentResponse {
+	return SegmentResponse{
+		ID:         s.ID,
+		Name:       s.Name,
+		Static:     s.Static,
+		Attributes: s.Attributes,
+		Tags:       s.Tags,
+		CreatedAt:  s.CreatedAt,
+	}
+}
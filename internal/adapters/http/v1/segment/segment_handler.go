@@ -0,0 +1,73 @@
+package segment
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SegmentHandler struct {
+	uc *usecase.SegmentUseCase
+}
+
+func NewSegmentHandler(uc *usecase.SegmentUseCase) *SegmentHandler {
+	return &SegmentHandler{uc: uc}
+}
+
+// CreateSegment godoc
+// @Summary Create a segment
+// @Description Creates an audience for broadcasts: a static list of user_ids, or every user whose attributes include all of attributes and whose tags include all of tags.
+// @Tags segments
+// @Security BearerAuth
+// @Param request body SegmentRequest true "Segment payload"
+// @Success 201 {object} SegmentResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/segments [post]
+func (h *SegmentHandler) CreateSegment(c *gin.Context) {
+	var req SegmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	s, err := h.uc.Create(c.Request.Context(), req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newSegmentResponse(s))
+}
+
+// GetSegment godoc
+// @Summary Get a segment
+// @Description Returns a segment and the number of users it currently matches
+// @Tags segments
+// @Security BearerAuth
+// @Param id path string true "Segment ID"
+// @Success 200 {object} SegmentResponse
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/segments/{id} [get]
+func (h *SegmentHandler) GetSegment(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrSegmentNotFound)
+		return
+	}
+
+	s, size, err := h.uc.Get(c.Request.Context(), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := newSegmentResponse(s)
+	resp.Size = &size
+	c.JSON(http.StatusOK, resp)
+}
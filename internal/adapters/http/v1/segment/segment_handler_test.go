@@ -0,0 +1,101 @@
+package segment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSegmentRepo struct{ mock.Mock }
+
+func (m *MockSegmentRepo) Create(ctx context.Context, s entity.Segment) (entity.Segment, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Get(ctx context.Context, id uuid.UUID) (entity.Segment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Count(ctx context.Context, s entity.Segment) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSegmentRepo) ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, s, after, limit)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func newRouter(repo *MockSegmentRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterSegmentRoutes(r.Group("/v1"), usecase.NewSegmentUseCase(repo))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestCreateSegment(t *testing.T) {
+	repo := new(MockSegmentRepo)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(s entity.Segment) bool {
+		return !s.Static && s.Attributes["plan"] == "pro" && len(s.Tags) == 1
+	})).Return(entity.Segment{ID: uuid.New(), Name: "beta pros"}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/segments", SegmentRequest{
+		Name:       "beta pros",
+		Attributes: map[string]string{"plan": "pro"},
+		Tags:       []string{"beta"},
+	}))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateSegmentRejectsMixedDefinition(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRouter(new(MockSegmentRepo)).ServeHTTP(w, newJSONRequest(t, http.MethodPost, "/v1/segments", SegmentRequest{
+		Name:    "mixed",
+		UserIDs: []uuid.UUID{uuid.New()},
+		Tags:    []string{"beta"},
+	}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetSegmentIncludesSize(t *testing.T) {
+	repo := new(MockSegmentRepo)
+	s := entity.Segment{ID: uuid.New(), Name: "pilot", Static: true}
+	repo.On("Get", mock.Anything, s.ID).Return(s, nil)
+	repo.On("Count", mock.Anything, s).Return(3, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/segments/"+s.ID.String(), nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp SegmentResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotNil(t, resp.Size)
+	require.Equal(t, 3, *resp.Size)
+}
@@ -0,0 +1,16 @@
+package segment
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSegmentRoutes(r *gin.RouterGroup, uc *usecase.SegmentUseCase) {
+	h := NewSegmentHandler(uc)
+
+	api := r.Group("/segments")
+	{
+		api.POST("", h.CreateSegment)
+		api.GET("/:id", h.GetSegment)
+	}
+}
@@ -0,0 +1,66 @@
+package template
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type TemplateVariable struct {
+	Name     string `json:"name" binding:"required"`
+	Required bool   `json:"required"`
+}
+
+// TemplateRequest describes a template body written with Go text/template
+// and the variables it may be rendered with. locale is the language of body
+// (en when omitted) and variants maps other locales to translated bodies.
+type TemplateRequest struct {
+	Name      string             `json:"name" binding:"required"`
+	Body      string             `json:"body" binding:"required"`
+	Locale    string             `json:"locale,omitempty"`
+	Variants  map[string]string  `json:"variants,omitempty"`
+	Variables []TemplateVariable `json:"variables" binding:"dive"`
+}
+
+func (r TemplateRequest) toEntity() entity.Template {
+	t := entity.Template{
+		Name:      r.Name,
+		Body:      r.Body,
+		Locale:    r.Locale,
+		Variants:  r.Variants,
+		Variables: make([]entity.TemplateVariable, 0, len(r.Variables)),
+	}
+	for _, v := range r.Variables {
+		t.Variables = append(t.Variables, entity.TemplateVariable{Name: v.Name, Required: v.Required})
+	}
+	return t
+}
+
+type TemplateResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	Version   int                `json:"version"`
+	Name      string             `json:"name"`
+	Body      string             `json:"body"`
+	Locale    string             `json:"locale"`
+	Variants  map[string]string  `json:"variants"`
+	Variables []TemplateVariable `json:"variables"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+func newTemplateResponse(t entity.Template) TemplateResponse {
+	resp := TemplateResponse{
+		ID:        t.ID,
+		Version:   t.Version,
+		Name:      t.Name,
+		Body:      t.Body,
+		Locale:    t.Locale,
+		Variants:  t.Variants,
+		Variables: make([]TemplateVariable, 0, len(t.Variables)),
+		CreatedAt: t.CreatedAt,
+	}
+	for _, v := range t.Variables {
+		resp.Variables = append(resp.Variables, TemplateVariable{Name: v.Name, Required: v.Required})
+	}
+	return resp
+}
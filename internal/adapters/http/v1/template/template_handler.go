@@ -0,0 +1,140 @@
+package template
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TemplateHandler struct {
+	uc *usecase.TemplateUseCase
+}
+
+func NewTemplateHandler(uc *usecase.TemplateUseCase) *TemplateHandler {
+	return &TemplateHandler{uc: uc}
+}
+
+// CreateTemplate godoc
+// @Summary Create a template
+// @Description Creates version 1 of a message template written with Go text/template
+// @Tags templates
+// @Security BearerAuth
+// @Param request body TemplateRequest true "Template payload"
+// @Success 201 {object} TemplateResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	t, err := h.uc.Create(c.Request.Context(), req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTemplateResponse(t))
+}
+
+// UpdateTemplate godoc
+// @Summary Update a template
+// @Description Stores a new version of a template. Previous versions remain available.
+// @Tags templates
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param request body TemplateRequest true "Template payload"
+// @Success 201 {object} TemplateResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrTemplateNotFound)
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	t, err := h.uc.Update(c.Request.Context(), id, req.toEntity())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newTemplateResponse(t))
+}
+
+// GetTemplate godoc
+// @Summary Get a template
+// @Description Returns the latest version of a template, or the one given by the version query parameter
+// @Tags templates
+// @Security BearerAuth
+// @Param id path string true "Template ID"
+// @Param version query int false "Template version"
+// @Success 200 {object} TemplateResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(errs.ErrTemplateNotFound)
+		return
+	}
+
+	version := 0
+	if v := c.Query("version"); v != "" {
+		version, err = strconv.Atoi(v)
+		if err != nil || version < 1 {
+			c.Error(errs.InvalidField("version", "must be a positive integer"))
+			return
+		}
+	}
+
+	t, err := h.uc.Get(c.Request.Context(), id, version)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newTemplateResponse(t))
+}
+
+// ListTemplates godoc
+// @Summary List templates
+// @Description Returns the latest version of every template
+// @Tags templates
+// @Security BearerAuth
+// @Success 200 {array} TemplateResponse
+// @Failure 500 {object} problem.Problem
+// @Router /v1/templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.uc.List(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resp := make([]TemplateResponse, 0, len(templates))
+	for _, t := range templates {
+		resp = append(resp, newTemplateResponse(t))
+	}
+	c.JSON(http.StatusOK, resp)
+}
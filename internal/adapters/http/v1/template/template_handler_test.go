@@ -0,0 +1,98 @@
+package template
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockTemplateRepo struct{ mock.Mock }
+
+func (m *MockTemplateRepo) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) List(ctx context.Context) ([]entity.Template, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Template), args.Error(1)
+}
+
+const pathTemplates = "/v1/templates"
+
+func newRouter(repo *MockTemplateRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterTemplateRoutes(r.Group("/v1"), usecase.NewTemplateUseCase(repo))
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestCreateTemplate(t *testing.T) {
+	repo := new(MockTemplateRepo)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Template")).Return(entity.Template{ID: uuid.New(), Version: 1, Name: "welcome", Body: "Hi {{.name}}"}, nil)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPost, pathTemplates, TemplateRequest{
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: []TemplateVariable{{Name: "name", Required: true}},
+	}))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp TemplateResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Version)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateTemplateInvalidBody(t *testing.T) {
+	repo := new(MockTemplateRepo)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPost, pathTemplates, TemplateRequest{
+		Name: "welcome",
+		Body: "Hi {{.name",
+	}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetTemplateVersionNotFound(t *testing.T) {
+	repo := new(MockTemplateRepo)
+	id := uuid.New()
+	repo.On("Get", mock.Anything, id, 2).Return(entity.Template{}, errs.ErrTemplateNotFound)
+
+	w := httptest.NewRecorder()
+	newRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, pathTemplates+"/"+id.String()+"?version=2", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,18 @@
+package template
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTemplateRoutes(r *gin.RouterGroup, uc *usecase.TemplateUseCase) {
+	h := NewTemplateHandler(uc)
+
+	api := r.Group("/templates")
+	{
+		api.POST("", h.CreateTemplate)
+		api.GET("", h.ListTemplates)
+		api.GET("/:id", h.GetTemplate)
+		api.PUT("/:id", h.UpdateTemplate)
+	}
+}
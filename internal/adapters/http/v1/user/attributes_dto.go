@@ -0,0 +1,26 @@
+package user
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// AttributesRequest replaces every attribute and tag of a user. Segments
+// match users by them, such as {"plan": "pro"} or the tag "beta".
+type AttributesRequest struct {
+	Attributes map[string]string `json:"attributes"`
+	Tags       []string          `json:"tags" binding:"max=100"`
+}
+
+type AttributesResponse struct {
+	UserID     uuid.UUID         `json:"user_id"`
+	Attributes map[string]string `json:"attributes"`
+	Tags       []string          `json:"tags"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func newAttributesResponse(a entity.UserAttributes) AttributesResponse {
+	return AttributesResponse{UserID: a.UserID, Attributes: a.Attributes, Tags: a.Tags, UpdatedAt: a.UpdatedAt}
+}
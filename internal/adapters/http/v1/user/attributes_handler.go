@@ -0,0 +1,80 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttributesHandler struct {
+	uc *usecase.UserAttributesUseCase
+}
+
+func NewAttributesHandler(uc *usecase.UserAttributesUseCase) *AttributesHandler {
+	return &AttributesHandler{uc: uc}
+}
+
+// GetAttributes godoc
+// @Summary Get user attributes
+// @Description Returns the attributes and tags segments match a user by
+// @Tags users
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} AttributesResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/attributes [get]
+func (h *AttributesHandler) GetAttributes(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.Error(errs.InvalidField("user_id", "must be a UUID"))
+		return
+	}
+
+	a, err := h.uc.Get(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttributesResponse(a))
+}
+
+// UpdateAttributes godoc
+// @Summary Replace user attributes
+// @Description Replaces the key/value attributes and tags of a user, which segments match users by.
+// @Tags users
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param request body AttributesRequest true "Attributes payload"
+// @Success 200 {object} AttributesResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/attributes [put]
+func (h *AttributesHandler) UpdateAttributes(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.Error(errs.InvalidField("user_id", "must be a UUID"))
+		return
+	}
+
+	var req AttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	a, err := h.uc.Update(c.Request.Context(), entity.UserAttributes{UserID: userID, Attributes: req.Attributes, Tags: req.Tags})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAttributesResponse(a))
+}
@@ -0,0 +1,64 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAttributesRepo struct{ mock.Mock }
+
+func (m *MockAttributesRepo) Get(ctx context.Context, userID uuid.UUID) (entity.UserAttributes, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(entity.UserAttributes), args.Error(1)
+}
+
+func (m *MockAttributesRepo) Upsert(ctx context.Context, a entity.UserAttributes) (entity.UserAttributes, error) {
+	args := m.Called(ctx, a)
+	return args.Get(0).(entity.UserAttributes), args.Error(1)
+}
+
+func newAttributesRouter(repo *MockAttributesRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterUserRoutes(r.Group("/v1"), nil, usecase.NewUserAttributesUseCase(repo))
+	return r
+}
+
+func TestUpdateAttributes(t *testing.T) {
+	repo := new(MockAttributesRepo)
+	uid := uuid.New()
+	want := entity.UserAttributes{UserID: uid, Attributes: map[string]string{"plan": "pro"}, Tags: []string{"beta", "early"}}
+	repo.On("Upsert", mock.Anything, want).Return(want, nil)
+
+	w := httptest.NewRecorder()
+	newAttributesRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPut, "/v1/users/"+uid.String()+"/attributes", AttributesRequest{
+		Attributes: map[string]string{"plan": "pro"},
+		Tags:       []string{"early", "beta", "beta"},
+	}))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetAttributesNotFound(t *testing.T) {
+	repo := new(MockAttributesRepo)
+	uid := uuid.New()
+	repo.On("Get", mock.Anything, uid).Return(entity.UserAttributes{}, errs.ErrUserAttributesNotFound)
+
+	w := httptest.NewRecorder()
+	newAttributesRouter(repo).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/users/"+uid.String()+"/attributes", nil))
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,22 @@
+package user
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type PreferencesRequest struct {
+	Locale string `json:"locale" binding:"required"`
+}
+
+type PreferencesResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Locale    string    `json:"locale"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newPreferencesResponse(p entity.UserPreferences) PreferencesResponse {
+	return PreferencesResponse{UserID: p.UserID, Locale: p.Locale, UpdatedAt: p.UpdatedAt}
+}
@@ -0,0 +1,80 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type PreferencesHandler struct {
+	uc *usecase.UserPreferencesUseCase
+}
+
+func NewPreferencesHandler(uc *usecase.UserPreferencesUseCase) *PreferencesHandler {
+	return &PreferencesHandler{uc: uc}
+}
+
+// GetPreferences godoc
+// @Summary Get user preferences
+// @Description Returns the preferred locale of a user
+// @Tags users
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Success 200 {object} PreferencesResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/preferences [get]
+func (h *PreferencesHandler) GetPreferences(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.Error(errs.InvalidField("user_id", "must be a UUID"))
+		return
+	}
+
+	p, err := h.uc.Get(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreferencesResponse(p))
+}
+
+// UpdatePreferences godoc
+// @Summary Update user preferences
+// @Description Sets the locale templates are rendered in for a user, such as pt-BR. Missing translations fall back to less specific locales and then to the default locale.
+// @Tags users
+// @Security BearerAuth
+// @Param user_id path string true "User ID"
+// @Param request body PreferencesRequest true "Preferences payload"
+// @Success 200 {object} PreferencesResponse
+// @Failure 400 {object} problem.Problem
+// @Failure 500 {object} problem.Problem
+// @Router /v1/users/{user_id}/preferences [put]
+func (h *PreferencesHandler) UpdatePreferences(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.Error(errs.InvalidField("user_id", "must be a UUID"))
+		return
+	}
+
+	var req PreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Bind(c, err)
+		return
+	}
+
+	p, err := h.uc.Update(c.Request.Context(), entity.UserPreferences{UserID: userID, Locale: req.Locale})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newPreferencesResponse(p))
+}
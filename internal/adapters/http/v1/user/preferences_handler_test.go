@@ -0,0 +1,69 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/adapters/http/problem"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockPreferencesRepo struct{ mock.Mock }
+
+func (m *MockPreferencesRepo) Get(ctx context.Context, userID uuid.UUID) (entity.UserPreferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(entity.UserPreferences), args.Error(1)
+}
+
+func (m *MockPreferencesRepo) Upsert(ctx context.Context, p entity.UserPreferences) (entity.UserPreferences, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(entity.UserPreferences), args.Error(1)
+}
+
+func newPreferencesRouter(repo *MockPreferencesRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(problem.Handler())
+	RegisterUserRoutes(r.Group("/v1"), usecase.NewUserPreferencesUseCase(repo), nil)
+	return r
+}
+
+func newJSONRequest(t testing.TB, method, path string, v any) *http.Request {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(b))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	repo := new(MockPreferencesRepo)
+	uid := uuid.New()
+	repo.On("Upsert", mock.Anything, entity.UserPreferences{UserID: uid, Locale: "pt-BR"}).Return(entity.UserPreferences{UserID: uid, Locale: "pt-BR"}, nil)
+
+	w := httptest.NewRecorder()
+	newPreferencesRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPut, "/v1/users/"+uid.String()+"/preferences", PreferencesRequest{Locale: "pt_br"}))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdatePreferencesInvalidLocale(t *testing.T) {
+	repo := new(MockPreferencesRepo)
+
+	w := httptest.NewRecorder()
+	newPreferencesRouter(repo).ServeHTTP(w, newJSONRequest(t, http.MethodPut, "/v1/users/"+uuid.NewString()+"/preferences", PreferencesRequest{Locale: "english!"}))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,19 @@
+package user
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterUserRoutes(r *gin.RouterGroup, puc *usecase.UserPreferencesUseCase, auc *usecase.UserAttributesUseCase) {
+	h := NewPreferencesHandler(puc)
+	ah := NewAttributesHandler(auc)
+
+	api := r.Group("/users/:user_id")
+	{
+		api.GET("/preferences", h.GetPreferences)
+		api.PUT("/preferences", h.UpdatePreferences)
+		api.GET("/attributes", ah.GetAttributes)
+		api.PUT("/attributes", ah.UpdateAttributes)
+	}
+}
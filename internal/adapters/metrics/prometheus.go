@@ -0,0 +1,48 @@
+// Package metrics exposes the metrics of the service to Prometheus.
+package metrics
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "modak"
+
+// Prometheus records the use case's metrics as Prometheus collectors:
+//
+//   - modak_notifications_total, by type and outcome
+//   - modak_notification_operation_duration_seconds, by operation
+type Prometheus struct {
+	notifications *prometheus.CounterVec
+	durations     *prometheus.HistogramVec
+}
+
+// NewPrometheus registers the collectors in reg.
+func NewPrometheus(reg prometheus.Registerer) *Prometheus {
+	p := &Prometheus{
+		notifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_total",
+			Help:      "Notifications handed to the service, by type and outcome: accepted, rate_limited, invalid or failed.",
+		}, []string{"type", "outcome"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "notification_operation_duration_seconds",
+			Help:      "Time spent sending a notification (send), counting recent notifications for its rate limit (count_in_time_window) and handing it to the gateway (gateway_send).",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, []string{"operation"}),
+	}
+	reg.MustRegister(p.notifications, p.durations)
+	return p
+}
+
+func (p *Prometheus) CountNotification(notifType entity.NotificationType, outcome ports.NotificationOutcome) {
+	p.notifications.WithLabelValues(string(notifType), string(outcome)).Inc()
+}
+
+func (p *Prometheus) ObserveDuration(op ports.Operation, d time.Duration) {
+	p.durations.WithLabelValues(string(op)).Observe(d.Seconds())
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusCountsNotifications(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	p := NewPrometheus(reg)
+
+	p.CountNotification(entity.Status, ports.OutcomeAccepted)
+	p.CountNotification(entity.Status, ports.OutcomeAccepted)
+	p.CountNotification(entity.News, ports.OutcomeRateLimited)
+	p.ObserveDuration(ports.OpSend, 3*time.Millisecond)
+
+	expected := `
+# HELP modak_notifications_total Notifications handed to the service, by type and outcome: accepted, rate_limited, invalid or failed.
+# TYPE modak_notifications_total counter
+modak_notifications_total{outcome="accepted",type="status"} 2
+modak_notifications_total{outcome="rate_limited",type="news"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "modak_notifications_total"))
+	require.Equal(t, 1, testutil.CollectAndCount(p.durations, "modak_notification_operation_duration_seconds"))
+}
+
+func TestPoolCollector(t *testing.T) {
+	cfg, err := pgxpool.ParseConfig("postgres://user@127.0.0.1:1/db?pool_max_conns=7")
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	c := NewPoolCollector(pool)
+	require.Equal(t, 10, testutil.CollectAndCount(c))
+
+	expected := `
+# HELP modak_db_pool_max_conns Most connections the pool may open.
+# TYPE modak_db_pool_max_conns gauge
+modak_db_pool_max_conns 7
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "modak_db_pool_max_conns"))
+}
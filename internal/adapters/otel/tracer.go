@@ -0,0 +1,22 @@
+// Package otel traces the use case with OpenTelemetry.
+package otel
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/tracing"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+)
+
+// Tracer starts the use case's spans with the tracer provider installed by
+// tracing.Setup.
+type Tracer struct{}
+
+func NewTracer() Tracer {
+	return Tracer{}
+}
+
+func (Tracer) Start(ctx context.Context, name string, args ...any) (context.Context, ports.Span) {
+	ctx, span := tracing.Start(ctx, name, args...)
+	return ctx, span
+}
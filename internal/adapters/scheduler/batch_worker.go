@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+)
+
+// BatchProcessor handles one batch of background work, such as a page of
+// broadcast recipients or a campaign batch, and reports whether there was
+// any.
+type BatchProcessor interface {
+	ProcessNext(ctx context.Context) (bool, error)
+}
+
+// BatchWorker periodically runs a BatchProcessor until it runs out of work.
+// Shutdown lets the batch in progress finish and stops it from claiming
+// the next one.
+type BatchWorker struct {
+	loop
+	p BatchProcessor
+}
+
+func NewBatchWorker(p BatchProcessor, interval time.Duration) *BatchWorker {
+	return &BatchWorker{loop: newLoop(interval), p: p}
+}
+
+// Run processes batches until Shutdown is called or ctx is cancelled.
+func (w *BatchWorker) Run(ctx context.Context) {
+	w.run(ctx, w.drain)
+}
+
+// drain keeps processing batches while there is work, so a large broadcast
+// does not advance only one page per interval.
+func (w *BatchWorker) drain(ctx context.Context) {
+	for ctx.Err() == nil && !w.stopping() {
+		processed, err := w.p.ProcessNext(ctx)
+		if err != nil {
+			logging.FromContext(ctx).Error("processing batch failed", "err", err)
+			return
+		}
+		if !processed {
+			return
+		}
+	}
+}
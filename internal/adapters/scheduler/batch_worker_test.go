@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingProcessor always has more work, and holds each batch until it is
+// released.
+type blockingProcessor struct {
+	started chan struct{}
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func newBlockingProcessor() *blockingProcessor {
+	return &blockingProcessor{started: make(chan struct{}, 1), release: make(chan struct{})}
+}
+
+func (p *blockingProcessor) ProcessNext(ctx context.Context) (bool, error) {
+	p.calls.Add(1)
+	p.started <- struct{}{}
+	select {
+	case <-p.release:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func TestBatchWorkerShutdownFinishesBatchInProgress(t *testing.T) {
+	p := newBlockingProcessor()
+	w := NewBatchWorker(p, time.Millisecond)
+	go w.Run(context.Background())
+	<-p.started
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- w.Shutdown(context.Background()) }()
+
+	select {
+	case <-stopped:
+		t.Fatal("shutdown returned before the batch in progress finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(p.release)
+	require.NoError(t, <-stopped)
+	assert.Equal(t, int32(1), p.calls.Load(), "no batch should be claimed after shutdown")
+}
+
+func TestBatchWorkerShutdownGivesUpAtDeadline(t *testing.T) {
+	p := newBlockingProcessor()
+	w := NewBatchWorker(p, time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+	<-p.started
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelDeadline()
+	assert.ErrorIs(t, w.Shutdown(deadline), context.DeadlineExceeded)
+
+	// Cancelling the worker's context aborts the batch.
+	cancel()
+	require.NoError(t, w.Shutdown(context.Background()))
+}
@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loop runs work every interval. Shutdown stops it from starting new work
+// while letting the work in progress finish, the way http.Server.Shutdown
+// lets in-flight requests finish; cancelling the context given to run
+// aborts the work instead.
+type loop struct {
+	interval time.Duration
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newLoop(interval time.Duration) loop {
+	return loop{interval: interval, stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+// run calls work every interval until Shutdown is called or ctx is
+// cancelled. It must be called at most once.
+func (l *loop) run(ctx context.Context, work func(ctx context.Context)) {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			work(ctx)
+		}
+	}
+}
+
+// stopping reports whether Shutdown has been called, for work that claims
+// several batches to stop between them.
+func (l *loop) stopping() bool {
+	select {
+	case <-l.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Shutdown stops the worker from claiming new work and waits for the work
+// in progress to finish, or for ctx to be done, whose error it then
+// returns. Run must have been started before.
+func (l *loop) Shutdown(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
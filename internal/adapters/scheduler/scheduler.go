@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+// Scheduler periodically hands due scheduled notifications and held digests
+// to the use case for delivery. Shutdown lets the batch in progress finish
+// and stops it from claiming the next one.
+type Scheduler struct {
+	loop
+	uc        *usecase.NotificationUseCase
+	batchSize int
+}
+
+func NewScheduler(uc *usecase.NotificationUseCase, interval time.Duration, batchSize int) *Scheduler {
+	return &Scheduler{
+		loop:      newLoop(interval),
+		uc:        uc,
+		batchSize: batchSize,
+	}
+}
+
+// Run polls for due notifications and digests until Shutdown is called or
+// ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.run(ctx, s.tick)
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	s.dispatchDue(ctx)
+	if !s.stopping() {
+		s.flushDigests(ctx)
+	}
+}
+
+func (s *Scheduler) dispatchDue(ctx context.Context) {
+	for !s.stopping() {
+		sent, err := s.uc.DispatchDue(ctx, time.Now(), s.batchSize)
+		if err != nil {
+			logging.FromContext(ctx).Error("dispatching due notifications failed", "err", err)
+			return
+		}
+		// A full batch means more notifications may be waiting.
+		if sent < s.batchSize {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) flushDigests(ctx context.Context) {
+	if _, err := s.uc.FlushDigests(ctx, s.batchSize); err != nil {
+		logging.FromContext(ctx).Error("flushing digests failed", "err", err)
+	}
+}
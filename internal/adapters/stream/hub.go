@@ -0,0 +1,106 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+// Hub is an in-process NotificationStream. Each subscriber gets a buffered
+// channel; when it is full on Publish the subscriber is dropped and its
+// channel closed, so a slow or stalled client never holds up delivery and
+// can resume from history on reconnect.
+type Hub struct {
+	mu     sync.RWMutex
+	subs   map[recipient]map[chan entity.Notification]struct{}
+	buffer int
+	closed bool
+}
+
+var _ ports.NotificationStream = (*Hub)(nil)
+
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		subs:   make(map[recipient]map[chan entity.Notification]struct{}),
+		buffer: buffer,
+	}
+}
+
+// recipient is who a subscription is for. The same user id may exist in
+// several tenants, which must not see each other's notifications.
+type recipient struct {
+	tenant entity.TenantID
+	user   uuid.UUID
+}
+
+func (h *Hub) Subscribe(tenant entity.TenantID, userID uuid.UUID) (<-chan entity.Notification, func()) {
+	to := recipient{tenant: tenant, user: userID}
+	ch := make(chan entity.Notification, h.buffer)
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		close(ch)
+		return ch, func() {}
+	}
+	if h.subs[to] == nil {
+		h.subs[to] = make(map[chan entity.Notification]struct{})
+	}
+	h.subs[to][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() { h.remove(to, ch) }
+}
+
+func (h *Hub) Publish(n entity.Notification) {
+	var lagging []chan entity.Notification
+
+	to := recipient{tenant: n.TenantID, user: n.UserID}
+	h.mu.RLock()
+	for ch := range h.subs[to] {
+		select {
+		case ch <- n:
+		default:
+			lagging = append(lagging, ch)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, ch := range lagging {
+		h.remove(to, ch)
+	}
+}
+
+// remove ends a subscription. Channels are only closed while holding the
+// write lock, so Publish never sends on a closed channel.
+func (h *Hub) remove(to recipient, ch chan entity.Notification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.subs[to]
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	if len(subs) == 0 {
+		delete(h.subs, to)
+	}
+	close(ch)
+}
+
+// Close ends every subscription, and any made afterwards, so streams
+// return on shutdown instead of waiting for their clients to hang up.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for to, subs := range h.subs {
+		for ch := range subs {
+			close(ch)
+		}
+		delete(h.subs, to)
+	}
+}
@@ -0,0 +1,75 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHubPublishesToUserSubscribers(t *testing.T) {
+	h := NewHub(1)
+	userID := uuid.New()
+
+	events, unsubscribe := h.Subscribe("shop", userID)
+	defer unsubscribe()
+	other, unsubscribeOther := h.Subscribe("shop", uuid.New())
+	defer unsubscribeOther()
+	otherTenant, unsubscribeOtherTenant := h.Subscribe("games", userID)
+	defer unsubscribeOtherTenant()
+
+	n := entity.Notification{ID: uuid.New(), TenantID: "shop", UserID: userID}
+	h.Publish(n)
+
+	require.Equal(t, n, <-events)
+	require.Empty(t, other)
+	require.Empty(t, otherTenant)
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	h := NewHub(1)
+	userID := uuid.New()
+
+	events, unsubscribe := h.Subscribe("shop", userID)
+	defer unsubscribe()
+
+	h.Publish(entity.Notification{ID: uuid.New(), TenantID: "shop", UserID: userID})
+	h.Publish(entity.Notification{ID: uuid.New(), TenantID: "shop", UserID: userID})
+
+	_, ok := <-events
+	require.True(t, ok)
+	_, ok = <-events
+	require.False(t, ok, "channel should be closed after overflowing")
+}
+
+func TestHubUnsubscribeClosesChannel(t *testing.T) {
+	h := NewHub(1)
+	userID := uuid.New()
+
+	events, unsubscribe := h.Subscribe("shop", userID)
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-events
+	require.False(t, ok)
+	h.Publish(entity.Notification{TenantID: "shop", UserID: userID})
+}
+
+func TestHubCloseEndsSubscriptions(t *testing.T) {
+	h := NewHub(1)
+	userID := uuid.New()
+
+	events, unsubscribe := h.Subscribe("shop", userID)
+	h.Close()
+	unsubscribe()
+
+	_, ok := <-events
+	require.False(t, ok)
+
+	late, unsubscribeLate := h.Subscribe("shop", userID)
+	defer unsubscribeLate()
+	_, ok = <-late
+	require.False(t, ok, "subscriptions after Close should be closed")
+	h.Publish(entity.Notification{TenantID: "shop", UserID: userID})
+}
@@ -1,29 +1,158 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	DatabaseURL string
-	Port        string
+	DatabaseURL          string
+	Port                 string
+	ShutdownTimeout      time.Duration
+	ShutdownDelay        time.Duration
+	HealthCheckTimeout   time.Duration
+	SchedulerInterval    time.Duration
+	SchedulerBatchSize   int
+	SchedulerLease       time.Duration
+	IdempotencyRetention time.Duration
+	DigestTypes          []string
+	DigestTemplate       string
+	DefaultLocale        string
+	StreamHeartbeat      time.Duration
+	StreamBuffer         int
+	WebSocketBuffer      int
+	WebSocketPing        time.Duration
+	BroadcastPageSize    int
+	BroadcastLease       time.Duration
+	CampaignBatchSize    int
+	CampaignLease        time.Duration
+	BootstrapAPIKey      string
+	JWKSFile             string
+	JWKSURL              string
+	JWKSRefresh          time.Duration
+	JWTIssuer            string
+	JWTAudience          string
+	ClientRateIP         string
+	ClientRateSend       string
+	ClientRateRead       string
+	ClientRateAdmin      string
+	TrustedProxies       []string
+	LogLevel             string
+	LogFormat            string
+	TracesExporter       string
+	ServiceName          string
+
+	// Fallbacks are the variables Load used a default for, as logging is
+	// not set up yet when it runs. See LogFallbacks.
+	Fallbacks []Fallback
+}
+
+// Fallback is a variable that was unset or invalid, and the default used
+// instead.
+type Fallback struct {
+	Key     string
+	Value   string
+	Invalid bool
 }
 
 func Load() Config {
 	godotenv.Load()
-	return Config{
-		DatabaseURL: getEnv("DB_URL", ""),
-		Port:        getEnv("APP_PORT", "8080"),
+	var e env
+	cfg := Config{
+		DatabaseURL:          e.get("DB_URL", ""),
+		Port:                 e.get("APP_PORT", "8080"),
+		ShutdownTimeout:      e.getDuration("SHUTDOWN_TIMEOUT", 20*time.Second),
+		ShutdownDelay:        e.getDuration("SHUTDOWN_DELAY", 5*time.Second),
+		HealthCheckTimeout:   e.getDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		SchedulerInterval:    e.getDuration("SCHEDULER_INTERVAL", 5*time.Second),
+		SchedulerBatchSize:   e.getInt("SCHEDULER_BATCH_SIZE", 100),
+		SchedulerLease:       e.getDuration("SCHEDULER_LEASE", time.Minute),
+		IdempotencyRetention: e.getDuration("IDEMPOTENCY_RETENTION", 24*time.Hour),
+		DigestTypes:          e.getList("DIGEST_TYPES"),
+		DigestTemplate:       e.get("DIGEST_TEMPLATE", ""),
+		DefaultLocale:        e.get("DEFAULT_LOCALE", "en"),
+		StreamHeartbeat:      e.getDuration("STREAM_HEARTBEAT", 15*time.Second),
+		StreamBuffer:         e.getInt("STREAM_BUFFER", 64),
+		WebSocketBuffer:      e.getInt("WEBSOCKET_BUFFER", 64),
+		WebSocketPing:        e.getDuration("WEBSOCKET_PING_INTERVAL", 30*time.Second),
+		BroadcastPageSize:    e.getInt("BROADCAST_PAGE_SIZE", 500),
+		BroadcastLease:       e.getDuration("BROADCAST_LEASE", time.Minute),
+		CampaignBatchSize:    e.getInt("CAMPAIGN_BATCH_SIZE", 500),
+		CampaignLease:        e.getDuration("CAMPAIGN_LEASE", time.Minute),
+		BootstrapAPIKey:      e.get("BOOTSTRAP_API_KEY", ""),
+		JWKSFile:             e.get("JWKS_FILE", ""),
+		JWKSURL:              e.get("JWKS_URL", ""),
+		JWKSRefresh:          e.getDuration("JWKS_REFRESH", time.Hour),
+		JWTIssuer:            e.get("JWT_ISSUER", ""),
+		JWTAudience:          e.get("JWT_AUDIENCE", ""),
+		ClientRateIP:         e.get("CLIENT_RATE_LIMIT_IP", "1200/1m"),
+		ClientRateSend:       e.get("CLIENT_RATE_LIMIT_SEND", "600/1m"),
+		ClientRateRead:       e.get("CLIENT_RATE_LIMIT_READ", "600/1m"),
+		ClientRateAdmin:      e.get("CLIENT_RATE_LIMIT_ADMIN", "60/1m"),
+		TrustedProxies:       e.getList("TRUSTED_PROXIES"),
+		LogLevel:             e.get("LOG_LEVEL", "info"),
+		LogFormat:            e.get("LOG_FORMAT", "json"),
+		TracesExporter:       e.get("OTEL_TRACES_EXPORTER", "none"),
+		ServiceName:          e.get("OTEL_SERVICE_NAME", "modak-challenge"),
 	}
+	cfg.Fallbacks = e.fallbacks
+	return cfg
 }
 
-func getEnv(key string, fallback string) string {
+// LogFallbacks logs the defaults Load used: at info level for unset
+// variables and as warnings for invalid ones.
+func (c Config) LogFallbacks(log *slog.Logger) {
+	for _, f := range c.Fallbacks {
+		if f.Invalid {
+			log.Warn("invalid environment variable, using default", "key", f.Key, "default", f.Value)
+			continue
+		}
+		log.Info("environment variable not set, using default", "key", f.Key, "default", f.Value)
+	}
+}
+
+// env reads variables, recording every default it falls back on.
+type env struct {
+	fallbacks []Fallback
+}
+
+func (e *env) get(key string, fallback string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
 	}
-	log.Println("Environment variable " + key + " not found, using fallback value: " + fallback)
+	e.fallbacks = append(e.fallbacks, Fallback{Key: key, Value: fallback})
 	return fallback
 }
+
+func (e *env) getList(key string) []string {
+	var out []string
+	for _, item := range strings.Split(e.get(key, ""), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (e *env) getDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(e.get(key, fallback.String()))
+	if err != nil {
+		e.fallbacks = append(e.fallbacks, Fallback{Key: key, Value: fallback.String(), Invalid: true})
+		return fallback
+	}
+	return d
+}
+
+func (e *env) getInt(key string, fallback int) int {
+	i, err := strconv.Atoi(e.get(key, strconv.Itoa(fallback)))
+	if err != nil {
+		e.fallbacks = append(e.fallbacks, Fallback{Key: key, Value: strconv.Itoa(fallback), Invalid: true})
+		return fallback
+	}
+	return i
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRecordsFallbacks(t *testing.T) {
+	t.Setenv("APP_PORT", "9090")
+	t.Setenv("SHUTDOWN_TIMEOUT", "soon")
+	t.Setenv("LOG_LEVEL", "")
+
+	cfg := Load()
+
+	require.Equal(t, "9090", cfg.Port)
+	require.Equal(t, 20*time.Second, cfg.ShutdownTimeout)
+	require.Contains(t, cfg.Fallbacks, Fallback{Key: "SHUTDOWN_TIMEOUT", Value: "20s", Invalid: true})
+	require.Contains(t, cfg.Fallbacks, Fallback{Key: "LOG_LEVEL", Value: "info"})
+	for _, f := range cfg.Fallbacks {
+		require.NotEqual(t, "APP_PORT", f.Key)
+	}
+}
@@ -1,8 +1,118 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
+
+// Kind classifies errors by what the caller can do about them. Adapters map
+// kinds to their own notion of status, such as HTTP status codes.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindUnprocessable
+	KindRateLimited
+	KindUnavailable
+)
+
+// Error is a domain error with a stable, machine-readable Code, such as
+// "template_not_found". Codes do not change between releases, so clients
+// may rely on them where the message is only meant for people. Errors
+// wrapping an Error, for instance with fmt.Errorf and %w, keep its code.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrInternal is the code and kind reported for errors that are not an
+// Error, whose text is not meant for clients.
+var ErrInternal = New(KindInternal, "internal", "internal server error")
+
+// As returns the Error err wraps, or ErrInternal when it wraps none.
+func As(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return ErrInternal
+}
 
 var (
-	ErrRateLimitExceeded   = errors.New("rate limit exceeded")
-	ErrInvalidNotification = errors.New("invalid notification")
+	ErrInvalidRequest             = New(KindInvalid, "invalid_request", "invalid request")
+	ErrRateLimitExceeded          = New(KindRateLimited, "rate_limit_exceeded", "rate limit exceeded")
+	ErrInvalidNotification        = New(KindInvalid, "invalid_notification", "invalid notification")
+	ErrNotificationNotFound       = New(KindNotFound, "notification_not_found", "notification not found")
+	ErrNotificationNotCancellable = New(KindConflict, "notification_not_cancellable", "notification is not scheduled")
+	ErrNotificationExists         = New(KindConflict, "notification_exists", "notification already exists")
+	ErrIdempotencyKeyConflict     = New(KindUnprocessable, "idempotency_key_conflict", "idempotency key was used with a different request")
+	ErrTemplateNotFound           = New(KindNotFound, "template_not_found", "template not found")
+	ErrTemplateConflict           = New(KindConflict, "template_conflict", "template was modified concurrently")
+	ErrInvalidTemplate            = New(KindInvalid, "invalid_template", "invalid template")
+	ErrTemplateMissingVariable    = New(KindInvalid, "template_missing_variable", "missing template variable")
+	ErrTemplateUnknownVariable    = New(KindInvalid, "template_unknown_variable", "undeclared template variable")
+	ErrInvalidLocale              = New(KindInvalid, "invalid_locale", "invalid locale")
+	ErrUserPreferencesNotFound    = New(KindNotFound, "user_preferences_not_found", "user preferences not found")
+	ErrInvalidCursor              = New(KindInvalid, "invalid_cursor", "invalid cursor")
+	ErrStreamUnavailable          = New(KindUnavailable, "stream_unavailable", "notification stream is not available")
+	ErrRecipientNotConnected      = New(KindUnavailable, "recipient_not_connected", "recipient is not connected")
+	ErrUnavailable                = New(KindUnavailable, "unavailable", "a service the request depends on is unavailable")
+	ErrSegmentNotFound            = New(KindNotFound, "segment_not_found", "segment not found")
+	ErrInvalidSegment             = New(KindInvalid, "invalid_segment", "invalid segment")
+	ErrUserAttributesNotFound     = New(KindNotFound, "user_attributes_not_found", "user attributes not found")
+	ErrBroadcastNotFound          = New(KindNotFound, "broadcast_not_found", "broadcast not found")
+	ErrCampaignNotFound           = New(KindNotFound, "campaign_not_found", "campaign not found")
+	ErrInvalidCampaign            = New(KindInvalid, "invalid_campaign", "invalid campaign")
+	ErrCampaignStatusConflict     = New(KindConflict, "campaign_status_conflict", "campaign cannot make this change in its current status")
+	ErrAPIKeyNotFound             = New(KindNotFound, "api_key_not_found", "api key not found")
+	ErrInvalidAPIKey              = New(KindInvalid, "invalid_api_key", "invalid api key")
+	ErrUnauthorized               = New(KindUnauthorized, "unauthorized", "missing or invalid credentials")
+	ErrForbidden                  = New(KindForbidden, "forbidden", "credentials lack the required scope")
+	ErrInvalidTenant              = New(KindInvalid, "invalid_tenant", "invalid tenant")
+	ErrTenantForbidden            = New(KindForbidden, "tenant_forbidden", "credentials cannot act on another tenant")
+	ErrRateLimitRuleNotFound      = New(KindNotFound, "rate_limit_rule_not_found", "rate limit rule not found")
+	ErrInvalidRateLimitRule       = New(KindInvalid, "invalid_rate_limit_rule", "invalid rate limit rule")
+	ErrTooManyRequests            = New(KindRateLimited, "too_many_requests", "too many requests from this client")
 )
+
+// FieldError says what is wrong with one field of a request.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError is an ErrInvalidRequest that lists the fields at fault.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// InvalidField returns a ValidationError for a single field.
+func InvalidField(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return ErrInvalidRequest.Message + ": " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidRequest
+}
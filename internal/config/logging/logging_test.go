@@ -0,0 +1,49 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSON(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(&buf, "json", "warn")
+	require.NoError(t, err)
+
+	l.Info("dropped")
+	l.Warn("kept", "user_id", "42")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "kept", line["msg"])
+	require.Equal(t, "WARN", line["level"])
+	require.Equal(t, "42", line["user_id"])
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "xml", "info")
+	require.Error(t, err)
+	_, err = New(&bytes.Buffer{}, "json", "loud")
+	require.Error(t, err)
+}
+
+func TestContextLogger(t *testing.T) {
+	require.Same(t, slog.Default(), FromContext(context.Background()))
+
+	var buf bytes.Buffer
+	l, err := New(&buf, "json", "info")
+	require.NoError(t, err)
+
+	ctx := With(WithLogger(context.Background(), l), "request_id", "abc")
+	FromContext(ctx).Info("hello", "type", "news")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "abc", line["request_id"])
+	require.Equal(t, "news", line["type"])
+}
@@ -0,0 +1,153 @@
+// Package tracing sets up OpenTelemetry tracing and helps the rest of the
+// service start spans. Until Setup runs, spans are not recorded.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Paulooo0/modak-challenge"
+
+// Exporters Setup accepts.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Setup installs a tracer provider sending the spans of serviceName to
+// exporter, and the W3C trace context and baggage propagators. The OTLP
+// exporter sends over HTTP and is configured with the standard
+// OTEL_EXPORTER_OTLP_* variables. Call the returned function to flush
+// pending spans on shutdown.
+func Setup(ctx context.Context, exporter, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exp sdktrace.SpanExporter
+		err error
+	)
+	switch exporter {
+	case ExporterNone, "":
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exp, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		exp, err = otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("invalid traces exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, err
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp), sdktrace.WithResource(res))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer of the service, for adapters that need more
+// control over their spans than Start gives, such as their kind.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named name as a child of the span in ctx, if any,
+// with args as attributes: alternating keys and values, as in slog.
+func Start(ctx context.Context, name string, args ...any) (context.Context, Span) {
+	ctx, span := Tracer().Start(ctx, name, trace.WithAttributes(attributes(args)...))
+	return ctx, Span{span: span}
+}
+
+// Span is a span started by Start.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttributes adds args, alternating keys and values, to the span.
+func (s Span) SetAttributes(args ...any) {
+	s.span.SetAttributes(attributes(args)...)
+}
+
+// End marks the span as failed with err, when it is not nil, and ends it.
+func (s Span) End(err error) {
+	End(s.span, err)
+}
+
+// End records err on span, when it is not nil, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func attributes(args []any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		key := attribute.Key(fmt.Sprint(args[i]))
+		switch v := args[i+1].(type) {
+		case string:
+			attrs = append(attrs, key.String(v))
+		case int:
+			attrs = append(attrs, key.Int(v))
+		case int64:
+			attrs = append(attrs, key.Int64(v))
+		case bool:
+			attrs = append(attrs, key.Bool(v))
+		default:
+			attrs = append(attrs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return attrs
+}
+
+// Transport returns an http.RoundTripper that sends requests through base
+// in a client span and passes the trace on to the server in the W3C
+// traceparent header.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Tracer().Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(req.Method), semconv.URLFull(req.URL.Redacted())))
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	spanErr := err
+	if err == nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			spanErr = errors.New(resp.Status)
+		}
+	}
+	End(span, spanErr)
+	return resp, err
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func record(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	rec := tracetest.NewSpanRecorder()
+	prev, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		otel.SetTextMapPropagator(prevProp)
+	})
+	return rec
+}
+
+func TestStartAndEnd(t *testing.T) {
+	rec := record(t)
+
+	_, span := Start(context.Background(), "work", "user.id", "42", "count", 3)
+	span.SetAttributes("done", true)
+	span.End(errors.New("boom"))
+
+	spans := rec.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "work", spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := map[string]any{}
+	for _, a := range spans[0].Attributes() {
+		attrs[string(a.Key)] = a.Value.AsInterface()
+	}
+	require.Equal(t, map[string]any{"user.id": "42", "count": int64(3), "done": true}, attrs)
+}
+
+func TestTransportPropagatesTrace(t *testing.T) {
+	rec := record(t)
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	ctx, parent := Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: Transport(http.DefaultTransport)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End(nil)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	client := spans[0]
+	require.Equal(t, "HTTP GET", client.Name())
+	require.Equal(t, spans[1].SpanContext().SpanID(), client.Parent().SpanID())
+	require.Contains(t, traceparent, client.SpanContext().TraceID().String())
+	require.Contains(t, traceparent, client.SpanContext().SpanID().String())
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), "zipkin", "test")
+	require.Error(t, err)
+
+	shutdown, err := Setup(context.Background(), ExporterNone, "test")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/google/uuid"
+)
+
+// Scope is a permission granted to an API key.
+type Scope string
+
+const (
+	ScopeNotificationsSend Scope = "notifications:send"
+	ScopeNotificationsRead Scope = "notifications:read"
+	// ScopeAdmin grants every other scope as well.
+	ScopeAdmin Scope = "admin"
+)
+
+func IsValidScope(s Scope) bool {
+	switch s {
+	case ScopeNotificationsSend, ScopeNotificationsRead, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+const (
+	apiKeyPrefix = "mk_"
+	// apiKeyDisplayLength is how much of a key is kept in clear to tell
+	// keys apart, including apiKeyPrefix.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 8
+)
+
+// APIKey authenticates a client of the API. Only a hash of the secret is
+// stored; Prefix is the start of the secret, kept so that people can tell
+// their keys apart. A revoked key no longer authenticates.
+type APIKey struct {
+	ID         uuid.UUID
+	TenantID   TenantID
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Validate checks that the key has a name and only known scopes.
+func (k APIKey) Validate() error {
+	if strings.TrimSpace(k.Name) == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidAPIKey)
+	}
+	if len(k.Scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", errs.ErrInvalidAPIKey)
+	}
+	for _, s := range k.Scopes {
+		if !IsValidScope(s) {
+			return fmt.Errorf("%w: unknown scope %q", errs.ErrInvalidAPIKey, s)
+		}
+	}
+	return nil
+}
+
+// HasScope reports whether the key grants s, directly or through
+// ScopeAdmin.
+func (k APIKey) HasScope(s Scope) bool {
+	for _, granted := range k.Scopes {
+		if granted == s || granted == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// SetSecret stores the prefix and hash of secret on the key.
+func (k *APIKey) SetSecret(secret string) {
+	k.Prefix = secret[:min(len(secret), apiKeyDisplayLength)]
+	k.Hash = HashAPIKey(secret)
+}
+
+// NewAPIKeySecret returns a new random secret for an API key.
+func NewAPIKeySecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hash an API key secret is stored and looked up by.
+// Secrets are random and long, so a plain SHA-256 is enough.
+func HashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
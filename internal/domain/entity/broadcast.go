@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BroadcastStatus string
+
+const (
+	BroadcastPending   BroadcastStatus = "pending"
+	BroadcastRunning   BroadcastStatus = "running"
+	BroadcastCompleted BroadcastStatus = "completed"
+)
+
+// Broadcast sends one notification to every member of a segment. The
+// segment is expanded in pages of recipients ordered by user id; LastUserID
+// is the last recipient handled so far, and the counters tally what
+// happened to each. Total is the segment size when the broadcast was
+// created, so it is an estimate for segments that change meanwhile.
+//
+// A worker holds a broadcast while processing a page until LeasedUntil,
+// after which another worker may pick it up.
+type Broadcast struct {
+	ID          uuid.UUID
+	TenantID    TenantID
+	SegmentID   uuid.UUID
+	Type        NotificationType
+	Title       string
+	Message     string
+	ActionURL   string
+	Metadata    map[string]string
+	Status      BroadcastStatus
+	LastUserID  uuid.UUID
+	Total       int
+	Processed   int
+	Sent        int
+	RateLimited int
+	Skipped     int
+	Failed      int
+	LeasedUntil *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	CompletedAt *time.Time
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/google/uuid"
+)
+
+type CampaignStatus string
+
+const (
+	CampaignScheduled CampaignStatus = "scheduled"
+	CampaignRunning   CampaignStatus = "running"
+	CampaignPaused    CampaignStatus = "paused"
+	CampaignCancelled CampaignStatus = "cancelled"
+	CampaignCompleted CampaignStatus = "completed"
+)
+
+// Campaign is a marketing send of a template to a segment, rolled out from
+// StartAt at no more than RatePerMinute recipients a minute. Recipients are
+// handled in batches ordered by user id; LastUserID is the last one handled
+// so far and NextRunAt is when the next batch is due. The counters tally
+// what happened to each recipient: sent, rate limited by the marketing
+// limit, suppressed (duplicate, held for a digest or expired) or failed.
+//
+// A worker holds a campaign while sending a batch until LeasedUntil, after
+// which another worker may pick it up.
+type Campaign struct {
+	ID              uuid.UUID
+	TenantID        TenantID
+	Name            string
+	SegmentID       uuid.UUID
+	TemplateID      uuid.UUID
+	TemplateVersion int
+	Data            map[string]any
+	StartAt         time.Time
+	RatePerMinute   int
+	Status          CampaignStatus
+	LastUserID      uuid.UUID
+	Total           int
+	Processed       int
+	Sent            int
+	RateLimited     int
+	Suppressed      int
+	Failed          int
+	NextRunAt       time.Time
+	LeasedUntil     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	CompletedAt     *time.Time
+}
+
+// Validate checks that a campaign has a name, a template, an audience and
+// a positive send rate.
+func (c Campaign) Validate() error {
+	switch {
+	case strings.TrimSpace(c.Name) == "":
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidCampaign)
+	case c.TemplateID == uuid.Nil:
+		return fmt.Errorf("%w: template_id is required", errs.ErrInvalidCampaign)
+	case c.SegmentID == uuid.Nil:
+		return fmt.Errorf("%w: segment_id is required", errs.ErrInvalidCampaign)
+	case c.RatePerMinute <= 0:
+		return fmt.Errorf("%w: rate_per_minute must be positive", errs.ErrInvalidCampaign)
+	}
+	return nil
+}
+
+// BatchInterval is how long sending n recipients takes at the campaign's
+// rate, which is how long the next batch has to wait.
+func (c Campaign) BatchInterval(n int) time.Duration {
+	return time.Duration(n) * time.Minute / time.Duration(c.RatePerMinute)
+}
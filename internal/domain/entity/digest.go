@@ -0,0 +1,24 @@
+package entity
+
+import "github.com/google/uuid"
+
+// DefaultDigestTemplate is the text/template used to roll held
+// notifications up into a single digest message.
+const DefaultDigestTemplate = `You have {{.Count}} new {{.Type}} notifications:
+{{range .Notifications}}- {{.Message}}
+{{end}}`
+
+// DigestGroup identifies the held notifications of one type for one user
+// of a tenant that are rolled up together.
+type DigestGroup struct {
+	TenantID TenantID
+	UserID   uuid.UUID
+	Type     NotificationType
+}
+
+// DigestData is the value a digest template is executed with.
+type DigestData struct {
+	Type          NotificationType
+	Count         int
+	Notifications []Notification
+}
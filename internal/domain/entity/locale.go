@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultLocale is the locale templates are written in unless they say
+// otherwise, and the last entry of every fallback chain.
+const DefaultLocale = "en"
+
+// UserPreferences holds per-user delivery settings.
+type UserPreferences struct {
+	UserID    uuid.UUID
+	Locale    string
+	UpdatedAt time.Time
+}
+
+// NormalizeLocale returns the canonical form of a BCP 47 style tag, such as
+// "pt-BR" for "pt_br", and reports false when tag is malformed.
+func NormalizeLocale(tag string) (string, bool) {
+	parts := strings.Split(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"), "-")
+	for i, p := range parts {
+		if p == "" || len(p) > 8 || !isAlphanumeric(p) {
+			return "", false
+		}
+		switch {
+		case i == 0:
+			if len(p) < 2 || len(p) > 3 || !isLetters(p) {
+				return "", false
+			}
+			parts[i] = strings.ToLower(p)
+		case len(p) == 4 && isLetters(p):
+			parts[i] = strings.ToUpper(p[:1]) + strings.ToLower(p[1:])
+		case len(p) == 2:
+			parts[i] = strings.ToUpper(p)
+		default:
+			parts[i] = strings.ToLower(p)
+		}
+	}
+	return strings.Join(parts, "-"), true
+}
+
+// LocaleFallbacks returns the locales to try for tag, most specific first,
+// ending with fallback: "pt-BR" gives pt-BR, pt, en.
+func LocaleFallbacks(tag, fallback string) []string {
+	var chain []string
+	if tag, ok := NormalizeLocale(tag); ok {
+		parts := strings.Split(tag, "-")
+		for i := len(parts); i > 0; i-- {
+			chain = append(chain, strings.Join(parts[:i], "-"))
+		}
+	}
+	for _, l := range chain {
+		if l == fallback {
+			return chain
+		}
+	}
+	return append(chain, fallback)
+}
+
+func isLetters(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, r := range s {
+		if (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -1,17 +1,69 @@
 package entity
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Notification is a message to a user. Message is its body; Title,
+// ActionURL and Metadata are optional and used by channels that support
+// them, such as push and email.
 type Notification struct {
-	ID        uuid.UUID
-	UserID    uuid.UUID
-	Type      NotificationType
-	Message   string
-	CreatedAt time.Time
+	ID              uuid.UUID
+	TenantID        TenantID
+	UserID          uuid.UUID
+	Type            NotificationType
+	Title           string
+	Message         string
+	ActionURL       string
+	Metadata        map[string]string
+	Status          NotificationStatus
+	StatusReason    string
+	SendAt          *time.Time
+	SentAt          *time.Time
+	ExpiresAt       *time.Time
+	ReadAt          *time.Time
+	ArchivedAt      *time.Time
+	IdempotencyKey  string
+	RequestHash     string
+	TemplateID      *uuid.UUID
+	TemplateVersion int
+	TemplateData    map[string]any
+	Locale          string
+	CreatedAt       time.Time
+}
+
+// IsScheduledFor reports whether the notification must be held until a
+// delivery time later than now.
+func (n Notification) IsScheduledFor(now time.Time) bool {
+	return n.SendAt != nil && n.SendAt.After(now)
+}
+
+// IsExpiredAt reports whether the notification is no longer worth
+// delivering at now.
+func (n Notification) IsExpiredAt(now time.Time) bool {
+	return n.ExpiresAt != nil && !n.ExpiresAt.After(now)
+}
+
+// ApplyTTL sets ExpiresAt to ttl after the intended delivery time, which is
+// SendAt for scheduled notifications and now otherwise.
+func (n *Notification) ApplyTTL(ttl time.Duration, now time.Time) {
+	base := now
+	if n.SendAt != nil && n.SendAt.After(now) {
+		base = *n.SendAt
+	}
+	expiresAt := base.Add(ttl)
+	n.ExpiresAt = &expiresAt
+}
+
+// ContentHash fingerprints the message so identical content sent to the
+// same user can be recognised without comparing full texts.
+func (n Notification) ContentHash() string {
+	sum := sha256.Sum256([]byte(n.Message))
+	return hex.EncodeToString(sum[:])
 }
 
 type NotificationType string
@@ -30,3 +82,17 @@ func IsValidNotificationType(s NotificationType) bool {
 		return false
 	}
 }
+
+type NotificationStatus string
+
+const (
+	StatusScheduled   NotificationStatus = "scheduled"
+	StatusSending     NotificationStatus = "sending" // scheduled and being delivered by a worker
+	StatusSent        NotificationStatus = "sent"
+	StatusRateLimited NotificationStatus = "rate_limited"
+	StatusCanceled    NotificationStatus = "canceled"
+	StatusExpired     NotificationStatus = "expired"
+	StatusDuplicate   NotificationStatus = "duplicate"
+	StatusHeld        NotificationStatus = "held"
+	StatusDigested    NotificationStatus = "digested"
+)
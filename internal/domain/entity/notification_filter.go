@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// NotificationCursor is the position of a notification in a listing, which
+// is ordered by CreatedAt and then ID, newest first.
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// NotificationFilter selects notifications of one user. Empty fields do not
+// filter; From is inclusive and To exclusive. Read and Archived select by
+// inbox state. After continues a previous
+// listing past the given position.
+type NotificationFilter struct {
+	UserID   uuid.UUID
+	Types    []NotificationType
+	Statuses []NotificationStatus
+	From     *time.Time
+	To       *time.Time
+	Read     *bool
+	Archived *bool
+	After    *NotificationCursor
+	Limit    int
+}
+
+// NotificationPage is one page of a listing. Next is nil on the last page.
+type NotificationPage struct {
+	Notifications []Notification
+	Next          *NotificationCursor
+}
@@ -1,14 +1,57 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+)
 
 type RateLimit struct {
 	Limit    int
 	Interval time.Duration
 }
 
+// RateLimitRule overrides, for one tenant, the default rate limit of a
+// notification type. Default marks rules that are the deployment's default
+// because the tenant has not overridden it.
+type RateLimitRule struct {
+	Type NotificationType
+	RateLimit
+	Default   bool
+	UpdatedAt time.Time
+}
+
+// Validate checks that the rule is for a known type, allows at least one
+// notification and spans at least a second.
+func (r RateLimitRule) Validate() error {
+	switch {
+	case !IsValidNotificationType(r.Type):
+		return fmt.Errorf("%w: unknown type %q", errs.ErrInvalidRateLimitRule, r.Type)
+	case r.Limit < 1:
+		return fmt.Errorf("%w: limit must be at least 1", errs.ErrInvalidRateLimitRule)
+	case r.Interval < time.Second:
+		return fmt.Errorf("%w: interval must be at least one second", errs.ErrInvalidRateLimitRule)
+	}
+	return nil
+}
+
 var DefaultRateLimits = map[NotificationType]RateLimit{
 	Status:    {Limit: 2, Interval: time.Minute},
 	News:      {Limit: 1, Interval: 24 * time.Hour},
 	Marketing: {Limit: 3, Interval: time.Hour},
 }
+
+// DefaultTTLs holds how long a notification of each type stays worth
+// delivering when the caller does not set an expiry. Types without an entry
+// never expire.
+var DefaultTTLs = map[NotificationType]time.Duration{
+	Status: 10 * time.Minute,
+}
+
+// DefaultDedupeWindows holds, per type, how long an identical message to the
+// same user is suppressed as a duplicate. Types without an entry are never
+// deduplicated.
+var DefaultDedupeWindows = map[NotificationType]time.Duration{
+	News: 10 * time.Minute,
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/google/uuid"
+)
+
+// UserAttributes describes a user for audience targeting: free-form
+// key/value attributes, such as a plan or country, and tags.
+type UserAttributes struct {
+	UserID     uuid.UUID
+	Attributes map[string]string
+	Tags       []string
+	UpdatedAt  time.Time
+}
+
+// Segment is an audience. A static segment is an explicit list of users;
+// any other segment matches every user whose attributes include all of
+// Attributes and whose tags include all of Tags.
+type Segment struct {
+	ID         uuid.UUID
+	Name       string
+	Static     bool
+	UserIDs    []uuid.UUID
+	Attributes map[string]string
+	Tags       []string
+	CreatedAt  time.Time
+}
+
+// Validate checks that a segment has a name and is either a non-empty
+// static list or has at least one predicate, but not both.
+func (s Segment) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: name is required", errs.ErrInvalidSegment)
+	}
+	hasPredicate := len(s.Attributes) > 0 || len(s.Tags) > 0
+	switch {
+	case s.Static && hasPredicate:
+		return fmt.Errorf("%w: a static segment cannot have attributes or tags", errs.ErrInvalidSegment)
+	case s.Static && len(s.UserIDs) == 0:
+		return fmt.Errorf("%w: a static segment needs user ids", errs.ErrInvalidSegment)
+	case !s.Static && !hasPredicate:
+		return fmt.Errorf("%w: attributes or tags are required", errs.ErrInvalidSegment)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package entity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/google/uuid"
+)
+
+// Template is one version of a reusable notification message written with
+// Go text/template. Each update stores a new version under the same ID.
+// Body is written in Locale, and Variants holds translations of it keyed by
+// locale; all of them share the declared variables.
+type Template struct {
+	ID        uuid.UUID
+	Version   int
+	Name      string
+	Body      string
+	Locale    string
+	Variants  map[string]string
+	Variables []TemplateVariable
+	CreatedAt time.Time
+}
+
+// TemplateVariable declares a value the template body expects in its data.
+type TemplateVariable struct {
+	Name     string
+	Required bool
+}
+
+// Validate checks that the body and every variant parse and only refer to
+// declared variables, and that locales are well formed.
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" || strings.TrimSpace(t.Body) == "" {
+		return fmt.Errorf("%w: name and body are required", errs.ErrInvalidTemplate)
+	}
+	if _, ok := NormalizeLocale(t.Locale); !ok {
+		return fmt.Errorf("%w: %w %q", errs.ErrInvalidTemplate, errs.ErrInvalidLocale, t.Locale)
+	}
+
+	seen := make(map[string]bool, len(t.Variables))
+	for _, v := range t.Variables {
+		if v.Name == "" || seen[v.Name] {
+			return fmt.Errorf("%w: variable names must be unique and not empty", errs.ErrInvalidTemplate)
+		}
+		seen[v.Name] = true
+	}
+
+	sample := make(map[string]any, len(t.Variables))
+	for _, v := range t.Variables {
+		sample[v.Name] = ""
+	}
+	if err := t.check(t.Body, sample); err != nil {
+		return err
+	}
+	for locale, body := range t.Variants {
+		if _, ok := NormalizeLocale(locale); !ok {
+			return fmt.Errorf("%w: %w %q", errs.ErrInvalidTemplate, errs.ErrInvalidLocale, locale)
+		}
+		if err := t.check(body, sample); err != nil {
+			return fmt.Errorf("%w (locale %s)", err, locale)
+		}
+	}
+	return nil
+}
+
+func (t Template) check(body string, sample map[string]any) error {
+	tmpl, err := parseTemplate(t.Name, body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrInvalidTemplate, err)
+	}
+	if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrInvalidTemplate, err)
+	}
+	return nil
+}
+
+// Localize returns the template with Body and Locale set to the first
+// variant found in locales, falling back to the template's own body.
+func (t Template) Localize(locales []string) Template {
+	for _, l := range locales {
+		if l == t.Locale {
+			break
+		}
+		if body, ok := t.Variants[l]; ok {
+			t.Body = body
+			t.Locale = l
+			break
+		}
+	}
+	t.Variants = nil
+	return t
+}
+
+// Render executes the template with data after checking it against the
+// declared variables. Missing optional variables render as empty strings.
+func (t Template) Render(data map[string]any) (string, error) {
+	declared := make(map[string]bool, len(t.Variables))
+	var missing []string
+	for _, v := range t.Variables {
+		declared[v.Name] = true
+		if _, ok := data[v.Name]; !ok && v.Required {
+			missing = append(missing, v.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: %s", errs.ErrTemplateMissingVariable, strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for name := range data {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("%w: %s", errs.ErrTemplateUnknownVariable, strings.Join(unknown, ", "))
+	}
+
+	values := make(map[string]any, len(t.Variables))
+	for _, v := range t.Variables {
+		values[v.Name] = ""
+	}
+	for name, value := range data {
+		values[name] = value
+	}
+
+	tmpl, err := parseTemplate(t.Name, t.Body)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrInvalidTemplate, err)
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, values); err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrInvalidTemplate, err)
+	}
+	return out.String(), nil
+}
+
+func parseTemplate(name, body string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(body)
+}
@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+)
+
+// TenantID identifies a tenant: one of the products hosted on the
+// deployment. Notifications, templates, rate limit rules, API keys,
+// broadcasts and campaigns each belong to one tenant, and are only visible
+// to it.
+type TenantID string
+
+// DefaultTenant owns everything created before tenants existed, and the
+// key created from BOOTSTRAP_API_KEY. Its admin keys may create keys for
+// other tenants.
+const DefaultTenant TenantID = "default"
+
+var tenantPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Validate checks that the id is a lowercase slug of at most 63
+// characters, such as "shop" or "acme-app".
+func (t TenantID) Validate() error {
+	if !tenantPattern.MatchString(string(t)) {
+		return fmt.Errorf("%w: %q must be a lowercase slug of letters, digits, '-' and '_'", errs.ErrInvalidTenant, t)
+	}
+	return nil
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx acting on behalf of tenant.
+// Repositories scope every read and write to it.
+func WithTenant(ctx context.Context, tenant TenantID) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// allTenants is stored in place of a TenantID by WithAllTenants.
+type allTenants struct{}
+
+// WithAllTenants returns a copy of ctx acting across tenants, for the few
+// reads that find work or credentials before their tenant is known, such
+// as claiming the next due broadcast or looking up an API key. Storage
+// only lets such a ctx reach every tenant's rows; one that names no tenant
+// reaches none.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, allTenants{})
+}
+
+// ActsForAllTenants reports whether ctx was returned by WithAllTenants,
+// and WithTenant was not called on it since.
+func ActsForAllTenants(ctx context.Context) bool {
+	_, ok := ctx.Value(tenantContextKey{}).(allTenants)
+	return ok
+}
+
+// TenantFromContext returns the tenant ctx acts on behalf of, and false
+// when it was not set or ctx acts for all tenants.
+func TenantFromContext(ctx context.Context) (TenantID, bool) {
+	t, ok := ctx.Value(tenantContextKey{}).(TenantID)
+	return t, ok
+}
+
+// TenantOf returns the tenant ctx acts on behalf of, or no tenant when it
+// was not set or ctx acts for all tenants. Storage refuses to act for no
+// tenant.
+func TenantOf(ctx context.Context) TenantID {
+	t, _ := TenantFromContext(ctx)
+	return t
+}
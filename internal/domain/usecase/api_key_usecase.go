@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+// apiKeyTouchInterval is how stale a key's last-used timestamp may get
+// before a request updates it, so busy keys do not write on every call.
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyUseCase struct {
+	repo ports.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(repo ports.APIKeyRepository) *APIKeyUseCase {
+	return &APIKeyUseCase{repo: repo}
+}
+
+// Create stores a new key and returns it along with its secret. The secret
+// is not stored and cannot be retrieved again. The key belongs to the
+// tenant of ctx unless k names another one, which only the default tenant
+// may do.
+func (s *APIKeyUseCase) Create(ctx context.Context, k entity.APIKey) (entity.APIKey, string, error) {
+	caller := entity.TenantOf(ctx)
+	if k.TenantID == "" {
+		k.TenantID = caller
+	}
+	if k.TenantID != caller && caller != entity.DefaultTenant {
+		return entity.APIKey{}, "", errs.ErrTenantForbidden
+	}
+	if err := k.TenantID.Validate(); err != nil {
+		return entity.APIKey{}, "", err
+	}
+	if err := k.Validate(); err != nil {
+		return entity.APIKey{}, "", err
+	}
+	secret, err := entity.NewAPIKeySecret()
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	k.ID = uuid.New()
+	k.SetSecret(secret)
+	saved, err := s.repo.Create(entity.WithTenant(ctx, k.TenantID), k)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+	return saved, secret, nil
+}
+
+// Bootstrap makes sure a key with the admin scope and the given secret
+// exists in the default tenant, so that a fresh deployment can create its
+// first keys.
+func (s *APIKeyUseCase) Bootstrap(ctx context.Context, secret string) error {
+	_, err := s.repo.GetActiveByHash(entity.WithAllTenants(ctx), entity.HashAPIKey(secret))
+	if !errors.Is(err, errs.ErrAPIKeyNotFound) {
+		return err
+	}
+
+	k := entity.APIKey{ID: uuid.New(), TenantID: entity.DefaultTenant, Name: "bootstrap", Scopes: []entity.Scope{entity.ScopeAdmin}}
+	k.SetSecret(secret)
+	_, err = s.repo.Create(entity.WithTenant(ctx, entity.DefaultTenant), k)
+	return err
+}
+
+func (s *APIKeyUseCase) List(ctx context.Context) ([]entity.APIKey, error) {
+	return s.repo.List(ctx)
+}
+
+// Rotate gives a key a new secret, which is returned, and stops the old
+// one from authenticating. Name and scopes are kept.
+func (s *APIKeyUseCase) Rotate(ctx context.Context, id uuid.UUID) (entity.APIKey, string, error) {
+	secret, err := entity.NewAPIKeySecret()
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+
+	var k entity.APIKey
+	k.SetSecret(secret)
+	saved, err := s.repo.Rotate(ctx, id, k.Prefix, k.Hash)
+	if err != nil {
+		return entity.APIKey{}, "", err
+	}
+	return saved, secret, nil
+}
+
+func (s *APIKeyUseCase) Revoke(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Revoke(ctx, id)
+}
+
+// Authenticate returns the active key with the given secret, or
+// errs.ErrUnauthorized, and records that it was used.
+func (s *APIKeyUseCase) Authenticate(ctx context.Context, secret string) (entity.APIKey, error) {
+	// The key is what tells the tenant, so it is looked up in all of them.
+	k, err := s.repo.GetActiveByHash(entity.WithAllTenants(ctx), entity.HashAPIKey(secret))
+	if errors.Is(err, errs.ErrAPIKeyNotFound) {
+		return entity.APIKey{}, errs.ErrUnauthorized
+	}
+	if err != nil {
+		return entity.APIKey{}, err
+	}
+
+	now := time.Now()
+	if k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= apiKeyTouchInterval {
+		if err := s.repo.Touch(entity.WithTenant(ctx, k.TenantID), k.ID, now); err != nil {
+			return entity.APIKey{}, err
+		}
+		k.LastUsedAt = &now
+	}
+	return k, nil
+}
@@ -0,0 +1,171 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+type MockAPIKeyRepo struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepo) Create(ctx context.Context, k entity.APIKey) (entity.APIKey, error) {
+	args := m.Called(ctx, k)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) GetActiveByHash(ctx context.Context, hash string) (entity.APIKey, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) List(ctx context.Context) ([]entity.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Rotate(ctx context.Context, id uuid.UUID, prefix, hash string) (entity.APIKey, error) {
+	args := m.Called(ctx, id, prefix, hash)
+	return args.Get(0).(entity.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockAPIKeyRepo) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	return m.Called(ctx, id, usedAt).Error(0)
+}
+
+func TestCreateAPIKeyStoresOnlyHash(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	var stored entity.APIKey
+	repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(entity.APIKey)
+	}).Return(entity.APIKey{Name: "backend"}, nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	_, secret, err := svc.Create(entity.WithTenant(context.Background(), entity.DefaultTenant), entity.APIKey{Name: "backend", Scopes: []entity.Scope{entity.ScopeNotificationsSend}})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(secret, "mk_"))
+	assert.Equal(t, entity.HashAPIKey(secret), stored.Hash)
+	assert.True(t, strings.HasPrefix(secret, stored.Prefix))
+	assert.NotEqual(t, secret, stored.Prefix)
+}
+
+func TestCreateAPIKeyRejectsUnknownScope(t *testing.T) {
+	svc := usecase.NewAPIKeyUseCase(new(MockAPIKeyRepo))
+	_, _, err := svc.Create(entity.WithTenant(context.Background(), entity.DefaultTenant), entity.APIKey{Name: "backend", Scopes: []entity.Scope{"notifications:delete"}})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidAPIKey)
+}
+
+func TestCreateAPIKeyForAnotherTenantFromDefault(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	repo.On("Create", mock.MatchedBy(func(ctx context.Context) bool {
+		return entity.TenantOf(ctx) == "shop"
+	}), mock.MatchedBy(func(k entity.APIKey) bool {
+		return k.TenantID == "shop"
+	})).Return(entity.APIKey{TenantID: "shop"}, nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	saved, _, err := svc.Create(entity.WithTenant(context.Background(), entity.DefaultTenant), entity.APIKey{TenantID: "shop", Name: "backend", Scopes: []entity.Scope{entity.ScopeAdmin}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.TenantID("shop"), saved.TenantID)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateAPIKeyForAnotherTenantForbidden(t *testing.T) {
+	svc := usecase.NewAPIKeyUseCase(new(MockAPIKeyRepo))
+	ctx := entity.WithTenant(context.Background(), "shop")
+	_, _, err := svc.Create(ctx, entity.APIKey{TenantID: "games", Name: "backend", Scopes: []entity.Scope{entity.ScopeAdmin}})
+
+	assert.ErrorIs(t, err, errs.ErrTenantForbidden)
+}
+
+func TestCreateAPIKeyRejectsInvalidTenant(t *testing.T) {
+	svc := usecase.NewAPIKeyUseCase(new(MockAPIKeyRepo))
+	_, _, err := svc.Create(entity.WithTenant(context.Background(), entity.DefaultTenant), entity.APIKey{TenantID: "Not A Slug", Name: "backend", Scopes: []entity.Scope{entity.ScopeAdmin}})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidTenant)
+}
+
+func TestAuthenticateAPIKeyTouchesStaleKey(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	id := uuid.New()
+	// The key is looked up across tenants, and touched in its own.
+	acrossTenants := mock.MatchedBy(entity.ActsForAllTenants)
+	inShop := mock.MatchedBy(func(ctx context.Context) bool { return entity.TenantOf(ctx) == "shop" })
+	repo.On("GetActiveByHash", acrossTenants, entity.HashAPIKey("mk_secret")).Return(entity.APIKey{ID: id, TenantID: "shop"}, nil)
+	repo.On("Touch", inShop, id, mock.Anything).Return(nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	k, err := svc.Authenticate(context.Background(), "mk_secret")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, k.LastUsedAt)
+	repo.AssertExpectations(t)
+}
+
+func TestAuthenticateAPIKeySkipsRecentTouch(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	recent := time.Now().Add(-time.Second)
+	repo.On("GetActiveByHash", mock.Anything, mock.Anything).Return(entity.APIKey{ID: uuid.New(), LastUsedAt: &recent}, nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	_, err := svc.Authenticate(context.Background(), "mk_secret")
+
+	assert.NoError(t, err)
+	repo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateUnknownAPIKey(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	repo.On("GetActiveByHash", mock.Anything, mock.Anything).Return(entity.APIKey{}, errs.ErrAPIKeyNotFound)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	_, err := svc.Authenticate(context.Background(), "mk_revoked")
+
+	assert.ErrorIs(t, err, errs.ErrUnauthorized)
+}
+
+func TestRotateAPIKeyReplacesSecret(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	id := uuid.New()
+	var hash string
+	repo.On("Rotate", mock.Anything, id, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		hash = args.String(3)
+	}).Return(entity.APIKey{ID: id}, nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	_, secret, err := svc.Rotate(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.HashAPIKey(secret), hash)
+}
+
+func TestBootstrapAPIKeyCreatesAdminOnce(t *testing.T) {
+	repo := new(MockAPIKeyRepo)
+	repo.On("GetActiveByHash", mock.Anything, entity.HashAPIKey("mk_bootstrap")).Return(entity.APIKey{}, errs.ErrAPIKeyNotFound).Once()
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(k entity.APIKey) bool {
+		return k.HasScope(entity.ScopeAdmin) && k.Hash == entity.HashAPIKey("mk_bootstrap")
+	})).Return(entity.APIKey{}, nil).Once()
+	repo.On("GetActiveByHash", mock.Anything, entity.HashAPIKey("mk_bootstrap")).Return(entity.APIKey{}, nil)
+
+	svc := usecase.NewAPIKeyUseCase(repo)
+	assert.NoError(t, svc.Bootstrap(context.Background(), "mk_bootstrap"))
+	assert.NoError(t, svc.Bootstrap(context.Background(), "mk_bootstrap"))
+	repo.AssertExpectations(t)
+}
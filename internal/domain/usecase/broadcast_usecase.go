@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+type BroadcastUseCase struct {
+	broadcasts    ports.BroadcastRepository
+	segments      ports.SegmentRepository
+	notifications *NotificationUseCase
+	pageSize      int
+	lease         time.Duration
+}
+
+// NewBroadcastUseCase expands broadcasts pageSize recipients at a time,
+// holding a broadcast for up to lease per page.
+func NewBroadcastUseCase(
+	broadcasts ports.BroadcastRepository,
+	segments ports.SegmentRepository,
+	notifications *NotificationUseCase,
+	pageSize int,
+	lease time.Duration,
+) *BroadcastUseCase {
+	return &BroadcastUseCase{
+		broadcasts:    broadcasts,
+		segments:      segments,
+		notifications: notifications,
+		pageSize:      pageSize,
+		lease:         lease,
+	}
+}
+
+// Create queues b for delivery to its segment. Recipients are expanded in
+// the background by ProcessNext.
+func (s *BroadcastUseCase) Create(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	if !entity.IsValidNotificationType(b.Type) || strings.TrimSpace(b.Message) == "" {
+		return entity.Broadcast{}, errs.ErrInvalidNotification
+	}
+
+	seg, err := s.segments.Get(ctx, b.SegmentID)
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+	total, err := s.segments.Count(ctx, seg)
+	if err != nil {
+		return entity.Broadcast{}, err
+	}
+
+	b.ID = uuid.New()
+	b.Status = entity.BroadcastPending
+	b.Total = total
+	return s.broadcasts.Create(ctx, b)
+}
+
+func (s *BroadcastUseCase) Get(ctx context.Context, id uuid.UUID) (entity.Broadcast, error) {
+	return s.broadcasts.Get(ctx, id)
+}
+
+// ProcessNext sends the next page of recipients of the unfinished broadcast
+// of any tenant that waited longest and reports whether there was one.
+// Each recipient goes through NotificationUseCase.Send in the broadcast's
+// tenant, so rate limits, dedupe and digests apply per user. Sends carry an
+// idempotency key per broadcast and user, so a page retried after a crash
+// does not notify anyone twice. A send that may succeed when retried ends
+// the page before its recipient, who is sent to again on the next claim,
+// and its error is returned so the worker backs off.
+func (s *BroadcastUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	b, err := s.broadcasts.Claim(entity.WithAllTenants(ctx), now, now.Add(s.lease))
+	if errors.Is(err, errs.ErrBroadcastNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	ctx = logging.With(withTenant(ctx, b.TenantID), "broadcast_id", b.ID)
+
+	seg, err := s.segments.Get(ctx, b.SegmentID)
+	if err != nil {
+		return false, err
+	}
+	recipients, err := s.segments.ListMembers(ctx, seg, b.LastUserID, s.pageSize)
+	if err != nil {
+		return false, err
+	}
+
+	var retry error
+	for _, userID := range recipients {
+		n, err := s.notifications.Send(ctx, entity.Notification{
+			UserID:         userID,
+			Type:           b.Type,
+			Title:          b.Title,
+			Message:        b.Message,
+			ActionURL:      b.ActionURL,
+			Metadata:       b.Metadata,
+			IdempotencyKey: fmt.Sprintf("broadcast:%s:%s", b.ID, userID),
+		})
+		if retryable(err) {
+			retry = fmt.Errorf("broadcast %s: send to user %s: %w", b.ID, userID, err)
+			break
+		}
+		b.Processed++
+		switch {
+		case err == nil && n.Status == entity.StatusSent:
+			b.Sent++
+		case err == nil:
+			// Held for a digest, duplicate or expired.
+			b.Skipped++
+		case errors.Is(err, errs.ErrRateLimitExceeded):
+			b.RateLimited++
+		default:
+			b.Failed++
+			logging.FromContext(ctx).Error("broadcast send failed", "user_id", userID, "err", err)
+		}
+		b.LastUserID = userID
+	}
+
+	if retry == nil && len(recipients) < s.pageSize {
+		b.Status = entity.BroadcastCompleted
+		b.CompletedAt = &now
+	}
+	_, err = s.broadcasts.SaveProgress(ctx, b)
+	if errors.Is(err, errs.ErrBroadcastNotFound) {
+		// The lease ran out and another worker has taken the broadcast over.
+		return true, retry
+	}
+	return true, errors.Join(retry, err)
+}
@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+type MockBroadcastRepo struct {
+	mock.Mock
+}
+
+func (m *MockBroadcastRepo) Create(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	args := m.Called(ctx, b)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) Get(ctx context.Context, id uuid.UUID) (entity.Broadcast, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Broadcast, error) {
+	args := m.Called(ctx, now, leasedUntil)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+func (m *MockBroadcastRepo) SaveProgress(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error) {
+	args := m.Called(ctx, b)
+	return args.Get(0).(entity.Broadcast), args.Error(1)
+}
+
+type MockSegmentRepo struct {
+	mock.Mock
+}
+
+func (m *MockSegmentRepo) Create(ctx context.Context, s entity.Segment) (entity.Segment, error) {
+	args := m.Called(ctx, s)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Get(ctx context.Context, id uuid.UUID) (entity.Segment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Segment), args.Error(1)
+}
+
+func (m *MockSegmentRepo) Count(ctx context.Context, s entity.Segment) (int, error) {
+	args := m.Called(ctx, s)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSegmentRepo) ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, s, after, limit)
+	ids, _ := args.Get(0).([]uuid.UUID)
+	return ids, args.Error(1)
+}
+
+func TestCreateBroadcastCountsSegment(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	segments := new(MockSegmentRepo)
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"beta"}}
+
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("Count", mock.Anything, seg).Return(42, nil)
+	broadcasts.On("Create", mock.Anything, mock.MatchedBy(func(b entity.Broadcast) bool {
+		return b.ID != uuid.Nil && b.Status == entity.BroadcastPending && b.Total == 42
+	})).Return(entity.Broadcast{Total: 42}, nil)
+
+	svc := usecase.NewBroadcastUseCase(broadcasts, segments, nil, 100, time.Minute)
+	b, err := svc.Create(context.Background(), entity.Broadcast{SegmentID: seg.ID, Type: entity.News, Message: "New release"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 42, b.Total)
+	broadcasts.AssertExpectations(t)
+}
+
+func TestCreateBroadcastUnknownSegment(t *testing.T) {
+	segments := new(MockSegmentRepo)
+	id := uuid.New()
+	segments.On("Get", mock.Anything, id).Return(entity.Segment{}, errs.ErrSegmentNotFound)
+
+	svc := usecase.NewBroadcastUseCase(new(MockBroadcastRepo), segments, nil, 100, time.Minute)
+	_, err := svc.Create(context.Background(), entity.Broadcast{SegmentID: id, Type: entity.News, Message: "New release"})
+
+	assert.ErrorIs(t, err, errs.ErrSegmentNotFound)
+}
+
+func TestProcessNextSendsPageThroughLimits(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	segments := new(MockSegmentRepo)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+
+	fresh, busy := uuid.New(), uuid.New()
+	lease := time.Now().Add(time.Minute)
+	seg := entity.Segment{ID: uuid.New(), Static: true}
+	b := entity.Broadcast{ID: uuid.New(), SegmentID: seg.ID, Type: entity.News, Message: "New release", Status: entity.BroadcastRunning, LeasedUntil: &lease}
+
+	broadcasts.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(b, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("ListMembers", mock.Anything, seg, uuid.Nil, 2).Return([]uuid.UUID{fresh, busy}, nil)
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "broadcast:"+b.ID.String()+":"+fresh.String()).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByIdempotencyKey", mock.Anything, "broadcast:"+b.ID.String()+":"+busy.String()).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("CountInTimeWindow", mock.Anything, fresh, entity.News, mock.Anything).Return(0, nil)
+	repo.On("CountInTimeWindow", mock.Anything, busy, entity.News, mock.Anything).Return(1, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool { return n.UserID == fresh })).
+		Return(entity.Notification{UserID: fresh, Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.Anything).Return(nil).Once()
+
+	broadcasts.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Broadcast) bool {
+		return got.Status == entity.BroadcastRunning && got.LastUserID == busy &&
+			got.Processed == 2 && got.Sent == 1 && got.RateLimited == 1 && got.CompletedAt == nil
+	})).Return(b, nil)
+
+	notifications := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	svc := usecase.NewBroadcastUseCase(broadcasts, segments, notifications, 2, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	broadcasts.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestProcessNextStopsBeforeRetryableFailure(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	segments := new(MockSegmentRepo)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	lease := time.Now().Add(time.Minute)
+	seg := entity.Segment{ID: uuid.New(), Static: true}
+	b := entity.Broadcast{ID: uuid.New(), SegmentID: seg.ID, Type: entity.News, Message: "New release", Status: entity.BroadcastRunning, LeasedUntil: &lease}
+
+	broadcasts.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(b, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("ListMembers", mock.Anything, seg, uuid.Nil, 5).Return([]uuid.UUID{first, second, third}, nil)
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "broadcast:"+b.ID.String()+":"+first.String()).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByIdempotencyKey", mock.Anything, "broadcast:"+b.ID.String()+":"+second.String()).Return(entity.Notification{}, errors.New("connection reset"))
+	repo.On("CountInTimeWindow", mock.Anything, first, entity.News, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.Anything).Return(entity.Notification{UserID: first, Status: entity.StatusSent}, nil).Once()
+	gw.On("Send", mock.Anything).Return(nil).Once()
+
+	// The page stops before second, so the next claim sends to it again,
+	// and the short page does not complete the broadcast.
+	broadcasts.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Broadcast) bool {
+		return got.Status == entity.BroadcastRunning && got.LastUserID == first &&
+			got.Processed == 1 && got.Sent == 1 && got.Failed == 0 && got.CompletedAt == nil
+	})).Return(b, nil)
+
+	notifications := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	svc := usecase.NewBroadcastUseCase(broadcasts, segments, notifications, 5, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.ErrorContains(t, err, "connection reset")
+	assert.True(t, processed)
+	broadcasts.AssertExpectations(t)
+	repo.AssertNotCalled(t, "GetByIdempotencyKey", mock.Anything, "broadcast:"+b.ID.String()+":"+third.String())
+}
+
+func TestProcessNextCompletesOnShortPage(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	segments := new(MockSegmentRepo)
+	lease := time.Now().Add(time.Minute)
+	last := uuid.New()
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"beta"}}
+	b := entity.Broadcast{ID: uuid.New(), SegmentID: seg.ID, Type: entity.News, Message: "hi", LastUserID: last, LeasedUntil: &lease}
+
+	broadcasts.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(b, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("ListMembers", mock.Anything, seg, last, 100).Return(nil, nil)
+	broadcasts.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Broadcast) bool {
+		return got.Status == entity.BroadcastCompleted && got.CompletedAt != nil
+	})).Return(entity.Broadcast{}, errs.ErrBroadcastNotFound)
+
+	svc := usecase.NewBroadcastUseCase(broadcasts, segments, nil, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	broadcasts.AssertExpectations(t)
+}
+
+func TestProcessNextIdle(t *testing.T) {
+	broadcasts := new(MockBroadcastRepo)
+	broadcasts.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(entity.Broadcast{}, errs.ErrBroadcastNotFound)
+
+	svc := usecase.NewBroadcastUseCase(broadcasts, new(MockSegmentRepo), nil, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestCreateSegmentDropsRepeatedMembers(t *testing.T) {
+	segments := new(MockSegmentRepo)
+	a, b := uuid.New(), uuid.New()
+	segments.On("Create", mock.Anything, mock.MatchedBy(func(s entity.Segment) bool {
+		return s.ID != uuid.Nil && len(s.UserIDs) == 2
+	})).Return(entity.Segment{}, nil)
+
+	_, err := usecase.NewSegmentUseCase(segments).Create(context.Background(), entity.Segment{Name: "pilot", Static: true, UserIDs: []uuid.UUID{a, b, a}})
+
+	assert.NoError(t, err)
+	segments.AssertExpectations(t)
+}
+
+func TestCreateSegmentRejectsEmptyPredicate(t *testing.T) {
+	segments := new(MockSegmentRepo)
+
+	_, err := usecase.NewSegmentUseCase(segments).Create(context.Background(), entity.Segment{Name: "everyone", Tags: []string{" "}})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidSegment)
+	segments.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
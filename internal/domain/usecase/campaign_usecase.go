@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+// campaignBatchWindow is how much of a campaign's rate one batch covers, so
+// a campaign goes out in small steady batches rather than a burst a minute.
+const campaignBatchWindow = 5 * time.Second
+
+type CampaignUseCase struct {
+	campaigns     ports.CampaignRepository
+	segments      ports.SegmentRepository
+	templates     ports.TemplateRepository
+	notifications *NotificationUseCase
+	maxBatch      int
+	lease         time.Duration
+}
+
+// NewCampaignUseCase rolls campaigns out in batches of at most maxBatch
+// recipients, holding a campaign for up to lease per batch.
+func NewCampaignUseCase(
+	campaigns ports.CampaignRepository,
+	segments ports.SegmentRepository,
+	templates ports.TemplateRepository,
+	notifications *NotificationUseCase,
+	maxBatch int,
+	lease time.Duration,
+) *CampaignUseCase {
+	return &CampaignUseCase{
+		campaigns:     campaigns,
+		segments:      segments,
+		templates:     templates,
+		notifications: notifications,
+		maxBatch:      maxBatch,
+		lease:         lease,
+	}
+}
+
+// Create schedules c to start at c.StartAt, or now when it is not set. The
+// template version is pinned when c does not name one, and the campaign
+// data is checked against the template's variables up front so a bad
+// campaign is rejected instead of failing for every recipient.
+func (s *CampaignUseCase) Create(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	if err := c.Validate(); err != nil {
+		return entity.Campaign{}, err
+	}
+
+	tmpl, err := s.templates.Get(ctx, c.TemplateID, c.TemplateVersion)
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+	if _, err := tmpl.Render(c.Data); err != nil {
+		return entity.Campaign{}, err
+	}
+
+	seg, err := s.segments.Get(ctx, c.SegmentID)
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+	total, err := s.segments.Count(ctx, seg)
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+
+	c.ID = uuid.New()
+	c.TemplateVersion = tmpl.Version
+	c.Status = entity.CampaignScheduled
+	c.Total = total
+	if c.StartAt.IsZero() {
+		c.StartAt = time.Now()
+	}
+	return s.campaigns.Create(ctx, c)
+}
+
+func (s *CampaignUseCase) Get(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	return s.campaigns.Get(ctx, id)
+}
+
+// Pause stops a scheduled or running campaign after its current batch.
+func (s *CampaignUseCase) Pause(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	return s.transition(ctx, id,
+		[]entity.CampaignStatus{entity.CampaignScheduled, entity.CampaignRunning}, entity.CampaignPaused)
+}
+
+// Resume continues a paused campaign where it stopped, or puts it back on
+// schedule when it had not started yet.
+func (s *CampaignUseCase) Resume(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	c, err := s.campaigns.Get(ctx, id)
+	if err != nil {
+		return entity.Campaign{}, err
+	}
+	if c.Status != entity.CampaignPaused {
+		return entity.Campaign{}, errs.ErrCampaignStatusConflict
+	}
+
+	to := entity.CampaignRunning
+	if c.Processed == 0 {
+		to = entity.CampaignScheduled
+	}
+	return s.transition(ctx, id, []entity.CampaignStatus{entity.CampaignPaused}, to)
+}
+
+// Cancel stops a campaign for good after its current batch. Recipients
+// already handled keep their notifications.
+func (s *CampaignUseCase) Cancel(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	return s.transition(ctx, id,
+		[]entity.CampaignStatus{entity.CampaignScheduled, entity.CampaignRunning, entity.CampaignPaused}, entity.CampaignCancelled)
+}
+
+func (s *CampaignUseCase) transition(ctx context.Context, id uuid.UUID, from []entity.CampaignStatus, to entity.CampaignStatus) (entity.Campaign, error) {
+	c, err := s.campaigns.UpdateStatus(ctx, id, from, to)
+	if !errors.Is(err, errs.ErrCampaignNotFound) {
+		return c, err
+	}
+
+	if _, err := s.campaigns.Get(ctx, id); err != nil {
+		return entity.Campaign{}, err
+	}
+	return entity.Campaign{}, errs.ErrCampaignStatusConflict
+}
+
+// ProcessNext sends the next batch of the campaign, of any tenant, that has
+// been due longest and reports whether there was one. A batch covers
+// campaignBatchWindow of the campaign's rate, and the campaign is not due
+// again until that batch's share of the rate has passed. Each recipient
+// gets a marketing notification through NotificationUseCase.Send, so the
+// marketing rate limit, dedupe and digests apply per user. Sends carry an
+// idempotency key per campaign and user, so a batch retried after a crash
+// does not notify anyone twice. A send that may succeed when retried ends
+// the batch before its recipient, who is sent to again in the next batch,
+// and its error is returned so the worker backs off.
+func (s *CampaignUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	now := time.Now()
+	c, err := s.campaigns.Claim(entity.WithAllTenants(ctx), now, now.Add(s.lease))
+	if errors.Is(err, errs.ErrCampaignNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	ctx = logging.With(withTenant(ctx, c.TenantID), "campaign_id", c.ID)
+
+	seg, err := s.segments.Get(ctx, c.SegmentID)
+	if err != nil {
+		return false, err
+	}
+	batch := s.batchSize(c)
+	recipients, err := s.segments.ListMembers(ctx, seg, c.LastUserID, batch)
+	if err != nil {
+		return false, err
+	}
+
+	var retry error
+	for _, userID := range recipients {
+		templateID := c.TemplateID
+		n, err := s.notifications.Send(ctx, entity.Notification{
+			UserID:          userID,
+			Type:            entity.Marketing,
+			TemplateID:      &templateID,
+			TemplateVersion: c.TemplateVersion,
+			TemplateData:    c.Data,
+			IdempotencyKey:  fmt.Sprintf("campaign:%s:%s", c.ID, userID),
+		})
+		if retryable(err) {
+			retry = fmt.Errorf("campaign %s: send to user %s: %w", c.ID, userID, err)
+			break
+		}
+		c.Processed++
+		switch {
+		case err == nil && n.Status == entity.StatusSent:
+			c.Sent++
+		case err == nil:
+			// Held for a digest, duplicate or expired.
+			c.Suppressed++
+		case errors.Is(err, errs.ErrRateLimitExceeded):
+			c.RateLimited++
+		default:
+			c.Failed++
+			logging.FromContext(ctx).Error("campaign send failed", "user_id", userID, "err", err)
+		}
+		c.LastUserID = userID
+	}
+
+	c.NextRunAt = now.Add(c.BatchInterval(len(recipients)))
+	if retry == nil && len(recipients) < batch {
+		c.Status = entity.CampaignCompleted
+		c.CompletedAt = &now
+	}
+	_, err = s.campaigns.SaveProgress(ctx, c)
+	if errors.Is(err, errs.ErrCampaignNotFound) {
+		// The lease ran out and another worker has taken the campaign over.
+		return true, retry
+	}
+	return true, errors.Join(retry, err)
+}
+
+// batchSize is how many recipients of c fit in campaignBatchWindow, at
+// least one and at most maxBatch.
+func (s *CampaignUseCase) batchSize(c entity.Campaign) int {
+	n := int(time.Duration(c.RatePerMinute) * campaignBatchWindow / time.Minute)
+	return max(1, min(n, s.maxBatch))
+}
@@ -0,0 +1,248 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+type MockCampaignRepo struct {
+	mock.Mock
+}
+
+func (m *MockCampaignRepo) Create(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) Get(ctx context.Context, id uuid.UUID) (entity.Campaign, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Campaign, error) {
+	args := m.Called(ctx, now, leasedUntil)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) SaveProgress(ctx context.Context, c entity.Campaign) (entity.Campaign, error) {
+	args := m.Called(ctx, c)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+func (m *MockCampaignRepo) UpdateStatus(ctx context.Context, id uuid.UUID, from []entity.CampaignStatus, to entity.CampaignStatus) (entity.Campaign, error) {
+	args := m.Called(ctx, id, from, to)
+	return args.Get(0).(entity.Campaign), args.Error(1)
+}
+
+var saleTemplate = entity.Template{
+	Version:   3,
+	Name:      "sale",
+	Body:      "{{.discount}} off everything",
+	Locale:    "en",
+	Variables: []entity.TemplateVariable{{Name: "discount", Required: true}},
+}
+
+func TestCreateCampaignPinsTemplateVersion(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	segments := new(MockSegmentRepo)
+	templates := new(MockTemplateRepo)
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"vip"}}
+	tmplID := uuid.New()
+
+	templates.On("Get", mock.Anything, tmplID, 0).Return(saleTemplate, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("Count", mock.Anything, seg).Return(500, nil)
+	campaigns.On("Create", mock.Anything, mock.MatchedBy(func(c entity.Campaign) bool {
+		return c.ID != uuid.Nil && c.TemplateVersion == 3 && c.Status == entity.CampaignScheduled &&
+			c.Total == 500 && !c.StartAt.IsZero()
+	})).Return(entity.Campaign{Total: 500}, nil)
+
+	svc := usecase.NewCampaignUseCase(campaigns, segments, templates, nil, 100, time.Minute)
+	c, err := svc.Create(context.Background(), entity.Campaign{
+		Name:          "spring sale",
+		SegmentID:     seg.ID,
+		TemplateID:    tmplID,
+		Data:          map[string]any{"discount": "20%"},
+		RatePerMinute: 60,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500, c.Total)
+	campaigns.AssertExpectations(t)
+}
+
+func TestCreateCampaignRejectsMissingTemplateData(t *testing.T) {
+	templates := new(MockTemplateRepo)
+	tmplID := uuid.New()
+	templates.On("Get", mock.Anything, tmplID, 0).Return(saleTemplate, nil)
+
+	svc := usecase.NewCampaignUseCase(new(MockCampaignRepo), new(MockSegmentRepo), templates, nil, 100, time.Minute)
+	_, err := svc.Create(context.Background(), entity.Campaign{
+		Name:          "spring sale",
+		SegmentID:     uuid.New(),
+		TemplateID:    tmplID,
+		RatePerMinute: 60,
+	})
+
+	assert.ErrorIs(t, err, errs.ErrTemplateMissingVariable)
+}
+
+func TestCreateCampaignRequiresRate(t *testing.T) {
+	svc := usecase.NewCampaignUseCase(new(MockCampaignRepo), new(MockSegmentRepo), new(MockTemplateRepo), nil, 100, time.Minute)
+	_, err := svc.Create(context.Background(), entity.Campaign{Name: "sale", SegmentID: uuid.New(), TemplateID: uuid.New()})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidCampaign)
+}
+
+func TestProcessNextCampaignThrottlesBatch(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	segments := new(MockSegmentRepo)
+	templates := new(MockTemplateRepo)
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+
+	fresh, busy := uuid.New(), uuid.New()
+	lease := time.Now().Add(time.Minute)
+	seg := entity.Segment{ID: uuid.New(), Static: true}
+	c := entity.Campaign{
+		ID:              uuid.New(),
+		SegmentID:       seg.ID,
+		TemplateID:      uuid.New(),
+		TemplateVersion: 3,
+		Data:            map[string]any{"discount": "20%"},
+		RatePerMinute:   24,
+		Status:          entity.CampaignRunning,
+		LeasedUntil:     &lease,
+	}
+
+	campaigns.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(c, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	// 24 a minute is 2 recipients per 5 second batch.
+	segments.On("ListMembers", mock.Anything, seg, uuid.Nil, 2).Return([]uuid.UUID{fresh, busy}, nil)
+	templates.On("Get", mock.Anything, c.TemplateID, 3).Return(saleTemplate, nil)
+
+	repo.On("GetByIdempotencyKey", mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("CountInTimeWindow", mock.Anything, fresh, entity.Marketing, mock.Anything).Return(0, nil)
+	repo.On("CountInTimeWindow", mock.Anything, busy, entity.Marketing, mock.Anything).Return(3, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.UserID == fresh && n.Type == entity.Marketing && n.Message == "20% off everything" &&
+			n.IdempotencyKey == "campaign:"+c.ID.String()+":"+fresh.String()
+	})).Return(entity.Notification{UserID: fresh, Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.Anything).Return(nil).Once()
+
+	before := time.Now()
+	campaigns.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Campaign) bool {
+		return got.Status == entity.CampaignRunning && got.LastUserID == busy &&
+			got.Processed == 2 && got.Sent == 1 && got.RateLimited == 1 &&
+			!got.NextRunAt.Before(before.Add(5*time.Second))
+	})).Return(c, nil)
+
+	notifications := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTemplates(templates))
+	svc := usecase.NewCampaignUseCase(campaigns, segments, templates, notifications, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	campaigns.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestProcessNextCampaignStopsBeforeRetryableFailure(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	segments := new(MockSegmentRepo)
+	templates := new(MockTemplateRepo)
+
+	first := uuid.New()
+	lease := time.Now().Add(time.Minute)
+	seg := entity.Segment{ID: uuid.New(), Static: true}
+	c := entity.Campaign{ID: uuid.New(), SegmentID: seg.ID, TemplateID: uuid.New(), TemplateVersion: 3, RatePerMinute: 6000, Status: entity.CampaignRunning, LeasedUntil: &lease}
+
+	campaigns.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(c, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("ListMembers", mock.Anything, seg, uuid.Nil, 100).Return([]uuid.UUID{first}, nil)
+	repo := new(MockRepo)
+	repo.On("GetByIdempotencyKey", mock.Anything, mock.Anything).Return(entity.Notification{}, errors.New("connection reset"))
+
+	// The short batch neither skips first nor completes the campaign.
+	campaigns.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Campaign) bool {
+		return got.Status == entity.CampaignRunning && got.LastUserID == uuid.Nil &&
+			got.Processed == 0 && got.Failed == 0 && got.CompletedAt == nil
+	})).Return(c, nil)
+
+	notifications := usecase.NewNotificationUseCase(repo, new(MockGateway), entity.DefaultRateLimits, usecase.WithTemplates(templates))
+	svc := usecase.NewCampaignUseCase(campaigns, segments, templates, notifications, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.ErrorContains(t, err, "connection reset")
+	assert.True(t, processed)
+	campaigns.AssertExpectations(t)
+}
+
+func TestProcessNextCampaignCompletesOnShortBatch(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	segments := new(MockSegmentRepo)
+	lease := time.Now().Add(time.Minute)
+	seg := entity.Segment{ID: uuid.New(), Tags: []string{"vip"}}
+	c := entity.Campaign{ID: uuid.New(), SegmentID: seg.ID, RatePerMinute: 6000, Status: entity.CampaignRunning, LeasedUntil: &lease}
+
+	campaigns.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(c, nil)
+	segments.On("Get", mock.Anything, seg.ID).Return(seg, nil)
+	segments.On("ListMembers", mock.Anything, seg, uuid.Nil, 100).Return(nil, nil)
+	campaigns.On("SaveProgress", mock.Anything, mock.MatchedBy(func(got entity.Campaign) bool {
+		return got.Status == entity.CampaignCompleted && got.CompletedAt != nil
+	})).Return(c, nil)
+
+	svc := usecase.NewCampaignUseCase(campaigns, segments, new(MockTemplateRepo), nil, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.True(t, processed)
+	campaigns.AssertExpectations(t)
+}
+
+func TestProcessNextCampaignNoneDue(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	campaigns.On("Claim", mock.Anything, mock.Anything, mock.Anything).Return(entity.Campaign{}, errs.ErrCampaignNotFound)
+
+	svc := usecase.NewCampaignUseCase(campaigns, new(MockSegmentRepo), new(MockTemplateRepo), nil, 100, time.Minute)
+	processed, err := svc.ProcessNext(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, processed)
+}
+
+func TestPauseCompletedCampaignConflicts(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	id := uuid.New()
+	campaigns.On("UpdateStatus", mock.Anything, id, mock.Anything, entity.CampaignPaused).Return(entity.Campaign{}, errs.ErrCampaignNotFound)
+	campaigns.On("Get", mock.Anything, id).Return(entity.Campaign{ID: id, Status: entity.CampaignCompleted}, nil)
+
+	svc := usecase.NewCampaignUseCase(campaigns, new(MockSegmentRepo), new(MockTemplateRepo), nil, 100, time.Minute)
+	_, err := svc.Pause(context.Background(), id)
+
+	assert.ErrorIs(t, err, errs.ErrCampaignStatusConflict)
+}
+
+func TestResumeCampaignNotStartedIsScheduled(t *testing.T) {
+	campaigns := new(MockCampaignRepo)
+	id := uuid.New()
+	campaigns.On("Get", mock.Anything, id).Return(entity.Campaign{ID: id, Status: entity.CampaignPaused}, nil)
+	campaigns.On("UpdateStatus", mock.Anything, id, []entity.CampaignStatus{entity.CampaignPaused}, entity.CampaignScheduled).
+		Return(entity.Campaign{ID: id, Status: entity.CampaignScheduled}, nil)
+
+	svc := usecase.NewCampaignUseCase(campaigns, new(MockSegmentRepo), new(MockTemplateRepo), nil, 100, time.Minute)
+	c, err := svc.Resume(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CampaignScheduled, c.Status)
+}
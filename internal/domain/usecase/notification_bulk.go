@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// BulkResult is the outcome of one notification of a bulk send: the stored
+// notification, or the error that kept it from being stored or delivered.
+type BulkResult struct {
+	Notification entity.Notification
+	Err          error
+}
+
+// SendBulk sends many notifications at once and stores them in a single
+// batch. Results are in input order. Each recipient's rate limits apply as
+// in Send, counting earlier notifications of the same batch; an invalid or
+// over-limit notification gets errs.ErrInvalidNotification or
+// errs.ErrRateLimitExceeded without affecting the rest, and over-limit
+// notifications of digest types are held. Bulk sends are delivered
+// immediately: scheduling, templates, idempotency keys and content dedupe
+// are not applied.
+func (s *NotificationUseCase) SendBulk(ctx context.Context, ns []entity.Notification) ([]BulkResult, error) {
+	ctx, span := s.tracer.Start(ctx, "NotificationUseCase.SendBulk", "notification.count", len(ns))
+	results, err := s.sendBulk(ctx, ns)
+	span.End(err)
+	if err != nil {
+		return nil, err
+	}
+	for i, r := range results {
+		s.countNotification(ns[i].Type, r.Err)
+	}
+	return results, nil
+}
+
+func (s *NotificationUseCase) sendBulk(ctx context.Context, ns []entity.Notification) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ns))
+
+	// Group by type, in order of first appearance, so each type's limits are
+	// counted with one query for all of its recipients.
+	var types []entity.NotificationType
+	byType := make(map[entity.NotificationType][]int)
+	for i, n := range ns {
+		if _, ok := s.rules[n.Type]; !ok || n.UserID == uuid.Nil || n.Message == "" {
+			results[i].Err = errs.ErrInvalidNotification
+			continue
+		}
+		if byType[n.Type] == nil {
+			types = append(types, n.Type)
+		}
+		byType[n.Type] = append(byType[n.Type], i)
+	}
+
+	// Rows are stamped from the clock the rate limit windows are counted
+	// with, in UTC like the rows Send stores.
+	now := time.Now().UTC()
+	var (
+		batch   []entity.Notification
+		batched []int
+	)
+	tenant := entity.TenantOf(ctx)
+	for _, notifType := range types {
+		rule, err := s.rateLimit(ctx, notifType)
+		if err != nil {
+			return nil, err
+		}
+
+		var users []uuid.UUID
+		seen := make(map[uuid.UUID]bool)
+		for _, i := range byType[notifType] {
+			if !seen[ns[i].UserID] {
+				seen[ns[i].UserID] = true
+				users = append(users, ns[i].UserID)
+			}
+		}
+
+		counts, err := s.repo.CountInTimeWindowByUser(ctx, users, notifType, now.Add(-rule.Interval))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range byType[notifType] {
+			n := ns[i]
+			n.ID = uuid.New()
+			n.TenantID = tenant
+			n.CreatedAt = now
+			if ttl, ok := s.ttls[n.Type]; ok && n.ExpiresAt == nil {
+				n.ApplyTTL(ttl, now)
+			}
+
+			switch {
+			case counts[n.UserID] < rule.Limit:
+				counts[n.UserID]++
+				sentAt := now
+				n.Status = entity.StatusSent
+				n.SentAt = &sentAt
+			case s.digests[n.Type] != nil:
+				n.Status = entity.StatusHeld
+				n.StatusReason = heldReason
+			default:
+				results[i].Err = errs.ErrRateLimitExceeded
+				continue
+			}
+			batch = append(batch, n)
+			batched = append(batched, i)
+		}
+	}
+
+	if len(batch) == 0 {
+		return results, nil
+	}
+	if err := s.repo.CreateMany(ctx, batch); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("bulk notifications stored", "count", len(batch))
+
+	for j, i := range batched {
+		results[i].Notification = batch[j]
+		if batch[j].Status == entity.StatusSent {
+			results[i].Err = s.deliver(withNotification(ctx, batch[j]), batch[j])
+		}
+	}
+	return results, nil
+}
@@ -2,39 +2,681 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
 )
 
 type NotificationUseCase struct {
-	repo    ports.NotificationRepository
-	gateway ports.NotificationGateway
-	rules   map[entity.NotificationType]entity.RateLimit
+	repo      ports.NotificationRepository
+	gateway   ports.NotificationGateway
+	templates ports.TemplateRepository
+	prefs     ports.UserPreferencesRepository
+	stream    ports.NotificationStream
+	rules     map[entity.NotificationType]entity.RateLimit
+	overrides ports.RateLimitRuleRepository
+	metrics   ports.Metrics
+	tracer    ports.Tracer
+	ttls      map[entity.NotificationType]time.Duration
+	dedupe    map[entity.NotificationType]time.Duration
+	digests   map[entity.NotificationType]*template.Template
+
+	idempotencyRetention time.Duration
+	defaultLocale        string
+	dispatchLease        time.Duration
+
+	// digestCursor is the last held group FlushDigests listed, so that the
+	// next flush carries on after it instead of retrying the same groups
+	// while they are over their rate limit.
+	digestMu     sync.Mutex
+	digestCursor entity.DigestGroup
+}
+
+// Option configures optional per-type behaviour of the use case.
+type Option func(*NotificationUseCase)
+
+// WithTTLs sets the per-type expiry applied to notifications that do not
+// carry their own.
+func WithTTLs(ttls map[entity.NotificationType]time.Duration) Option {
+	return func(s *NotificationUseCase) {
+		s.ttls = ttls
+	}
+}
+
+// WithDedupeWindows sets, per type, how long an identical message to the
+// same user is suppressed as a duplicate.
+func WithDedupeWindows(windows map[entity.NotificationType]time.Duration) Option {
+	return func(s *NotificationUseCase) {
+		s.dedupe = windows
+	}
+}
+
+// WithDigests enables digest mode for the given types: notifications over
+// the rate limit are held and later rolled up into one message rendered
+// with tmpl instead of being rejected.
+func WithDigests(tmpl *template.Template, types ...entity.NotificationType) Option {
+	return func(s *NotificationUseCase) {
+		s.digests = make(map[entity.NotificationType]*template.Template, len(types))
+		for _, t := range types {
+			s.digests[t] = tmpl
+		}
+	}
+}
+
+// WithTemplates lets notifications reference a stored template instead of
+// carrying a rendered message.
+func WithTemplates(templates ports.TemplateRepository) Option {
+	return func(s *NotificationUseCase) {
+		s.templates = templates
+	}
+}
+
+// WithLocales renders templates in the locale preferred by the recipient,
+// trying less specific locales and then fallback when a template has no
+// variant for it.
+func WithLocales(prefs ports.UserPreferencesRepository, fallback string) Option {
+	return func(s *NotificationUseCase) {
+		s.prefs = prefs
+		s.defaultLocale = fallback
+	}
+}
+
+// WithStream publishes every delivered notification to stream so that
+// connected clients receive it in real time.
+func WithStream(stream ports.NotificationStream) Option {
+	return func(s *NotificationUseCase) {
+		s.stream = stream
+	}
+}
+
+// WithIdempotencyRetention sets how long an idempotency key replays the
+// original result. A zero retention keeps keys forever.
+func WithIdempotencyRetention(d time.Duration) Option {
+	return func(s *NotificationUseCase) {
+		s.idempotencyRetention = d
+	}
+}
+
+// WithDispatchLease sets how long DispatchDue leases the scheduled
+// notifications it delivers. Those it has not settled by then, because
+// the worker stopped, are delivered again by a later dispatch.
+func WithDispatchLease(d time.Duration) Option {
+	return func(s *NotificationUseCase) {
+		s.dispatchLease = d
+	}
+}
+
+// WithTenantRateLimits lets each tenant override the default rate limit of
+// a type with a rule of its own.
+func WithTenantRateLimits(overrides ports.RateLimitRuleRepository) Option {
+	return func(s *NotificationUseCase) {
+		s.overrides = overrides
+	}
+}
+
+// WithMetrics records the outcome of every notification and the time
+// spent sending it in m.
+func WithMetrics(m ports.Metrics) Option {
+	return func(s *NotificationUseCase) {
+		s.metrics = m
+	}
+}
+
+// WithTracer starts a span in t for every notification sent and every
+// batch of scheduled or held notifications dispatched.
+func WithTracer(t ports.Tracer) Option {
+	return func(s *NotificationUseCase) {
+		s.tracer = t
+	}
 }
 
 func NewNotificationUseCase(
 	repo ports.NotificationRepository,
 	gateway ports.NotificationGateway,
 	rules map[entity.NotificationType]entity.RateLimit,
+	opts ...Option,
 ) *NotificationUseCase {
-	return &NotificationUseCase{
-		repo:    repo,
-		gateway: gateway,
-		rules:   rules,
+	s := &NotificationUseCase{
+		repo:          repo,
+		gateway:       gateway,
+		rules:         rules,
+		metrics:       noMetrics{},
+		tracer:        noTracer{},
+		defaultLocale: entity.DefaultLocale,
+		dispatchLease: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Send delivers n immediately, or persists it as scheduled when its SendAt
+// lies in the future. Rate limits and expiry are applied at delivery time.
+// A repeated request carrying the same idempotency key returns the original
+// notification without being rate limited or delivered again, and a message
+// repeated within its type's dedupe window is recorded as a duplicate.
+// When n references a template, its message is rendered from it first.
+// The notification belongs to the tenant of ctx.
+func (s *NotificationUseCase) Send(ctx context.Context, n entity.Notification) (entity.Notification, error) {
+	ctx, span := s.tracer.Start(ctx, "NotificationUseCase.Send", "notification.type", n.Type, "user.id", n.UserID)
+	start := time.Now()
+	saved, err := s.send(ctx, n)
+	s.metrics.ObserveDuration(ports.OpSend, time.Since(start))
+	outcome := s.countNotification(n.Type, err)
+
+	span.SetAttributes("notification.outcome", outcome)
+	if err == nil {
+		span.SetAttributes("notification.id", saved.ID, "notification.status", saved.Status)
+	}
+	if outcome == ports.OutcomeFailed {
+		span.End(err)
+	} else {
+		span.End(nil)
+	}
+	return saved, err
+}
+
+func (s *NotificationUseCase) send(ctx context.Context, n entity.Notification) (entity.Notification, error) {
+	if _, ok := s.rules[n.Type]; !ok {
+		return entity.Notification{}, errs.ErrInvalidNotification
+	}
+
+	if original, replayed, err := s.replay(ctx, n); err != nil || replayed {
+		if replayed {
+			logging.FromContext(ctx).Info("idempotency key replayed", "notification_id", original.ID)
+		}
+		return original, err
+	}
+	if err := s.render(ctx, &n); err != nil {
+		return entity.Notification{}, err
+	}
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	ctx = withNotification(ctx, n)
+
+	now := time.Now().UTC()
+	if ttl, ok := s.ttls[n.Type]; ok && n.ExpiresAt == nil {
+		n.ApplyTTL(ttl, now)
+	}
+
+	original, err := s.findDuplicate(ctx, n, now)
+	if err != nil {
+		return entity.Notification{}, err
+	}
+	if original != nil {
+		n.Status = entity.StatusDuplicate
+		n.StatusReason = fmt.Sprintf("duplicate of notification %s", original.ID)
+		saved, _, err := s.create(ctx, n)
+		return saved, err
+	}
+
+	if n.IsScheduledFor(now) {
+		n.Status = entity.StatusScheduled
+		saved, _, err := s.create(ctx, n)
+		return saved, err
+	}
+
+	if n.IsExpiredAt(now) {
+		n.Status = entity.StatusExpired
+		n.StatusReason = expiredReason(n)
+		saved, _, err := s.create(ctx, n)
+		return saved, err
+	}
+
+	err = s.checkRateLimit(ctx, n.UserID, n.Type)
+	if errors.Is(err, errs.ErrRateLimitExceeded) && s.digests[n.Type] != nil {
+		n.Status = entity.StatusHeld
+		n.StatusReason = heldReason
+		saved, _, err := s.create(ctx, n)
+		return saved, err
+	}
+	if err != nil {
+		if errors.Is(err, errs.ErrRateLimitExceeded) {
+			logging.FromContext(ctx).Info("notification rate limited")
+		}
+		return entity.Notification{}, err
+	}
+
+	sentAt := time.Now()
+	n.Status = entity.StatusSent
+	n.SentAt = &sentAt
+	saved, replayed, err := s.create(ctx, n)
+	if err != nil || replayed {
+		return saved, err
+	}
+
+	return saved, s.deliver(ctx, saved)
+}
+
+// DispatchDue delivers up to limit scheduled notifications of any tenant
+// that are due at now and returns how many were sent. Each is leased while
+// it is delivered, and scheduled again when its gateway fails, so that a
+// later dispatch retries it. Failures on individual notifications do not
+// stop the batch; they are joined into the returned error.
+func (s *NotificationUseCase) DispatchDue(ctx context.Context, now time.Time, limit int) (sent int, err error) {
+	ctx, span := s.tracer.Start(ctx, "NotificationUseCase.DispatchDue")
+	defer func() {
+		span.SetAttributes("notification.sent", sent)
+		span.End(err)
+	}()
+
+	due, err := s.repo.ClaimDue(entity.WithAllTenants(ctx), now, now.Add(s.dispatchLease), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures []error
+	for _, n := range due {
+		nctx := withNotification(withTenant(ctx, n.TenantID), n)
+		delivered, err := s.deliverScheduled(nctx, n)
+		if err != nil {
+			s.countNotification(n.Type, err)
+			failures = append(failures, fmt.Errorf("notification %s: %w", n.ID, err))
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(failures...)
+}
+
+// FlushDigests rolls the held notifications of up to limit users and
+// types, of any tenant, into a single digest each, for those whose rate
+// limit window has freed up, and returns how many digests were sent.
+func (s *NotificationUseCase) FlushDigests(ctx context.Context, limit int) (sent int, err error) {
+	ctx, span := s.tracer.Start(ctx, "NotificationUseCase.FlushDigests")
+	defer func() {
+		span.SetAttributes("notification.sent", sent)
+		span.End(err)
+	}()
+
+	s.digestMu.Lock()
+	after := s.digestCursor
+	s.digestMu.Unlock()
+
+	groups, err := s.repo.ListHeldGroups(entity.WithAllTenants(ctx), after, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	// A short page means the end was reached; start over next time.
+	next := entity.DigestGroup{}
+	if len(groups) == limit {
+		next = groups[len(groups)-1]
+	}
+	s.digestMu.Lock()
+	s.digestCursor = next
+	s.digestMu.Unlock()
+
+	var failures []error
+	for _, g := range groups {
+		gctx := logging.With(withTenant(ctx, g.TenantID), "user_id", g.UserID, "type", g.Type)
+		delivered, err := s.flushDigest(gctx, g)
+		if err != nil {
+			failures = append(failures, fmt.Errorf("digest %s/%s: %w", g.UserID, g.Type, err))
+			continue
+		}
+		if delivered {
+			sent++
+		}
+	}
+
+	return sent, errors.Join(failures...)
+}
+
+// Cancel stops a scheduled notification from being delivered.
+func (s *NotificationUseCase) Cancel(ctx context.Context, id uuid.UUID) error {
+	ctx = logging.With(ctx, "notification_id", id)
+	_, err := s.repo.UpdateStatus(ctx, id, entity.StatusScheduled, entity.StatusCanceled, "cancelled by client", time.Now())
+	if err == nil {
+		logging.FromContext(ctx).Info("notification cancelled")
+	}
+	if !errors.Is(err, errs.ErrNotificationNotFound) {
+		return err
+	}
+
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return errs.ErrNotificationNotCancellable
+}
+
+// List returns a page of filter.UserID's notifications, newest first, and
+// the cursor to continue from when more remain.
+func (s *NotificationUseCase) List(ctx context.Context, filter entity.NotificationFilter) (entity.NotificationPage, error) {
+	limit := filter.Limit
+	switch {
+	case limit <= 0:
+		limit = entity.DefaultPageSize
+	case limit > entity.MaxPageSize:
+		limit = entity.MaxPageSize
+	}
+	filter.Limit = limit + 1
+
+	rows, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return entity.NotificationPage{}, err
+	}
+
+	page := entity.NotificationPage{Notifications: rows}
+	if len(rows) > limit {
+		page.Notifications = rows[:limit]
+		last := page.Notifications[limit-1]
+		page.Next = &entity.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+// Subscribe streams the notifications of the user, in the tenant of ctx,
+// as they are delivered. The channel is closed when the subscriber falls
+// behind; call the returned function to stop listening.
+func (s *NotificationUseCase) Subscribe(ctx context.Context, userID uuid.UUID) (<-chan entity.Notification, func(), error) {
+	if s.stream == nil {
+		return nil, nil, errs.ErrStreamUnavailable
+	}
+	events, unsubscribe := s.stream.Subscribe(entity.TenantOf(ctx), userID)
+	return events, unsubscribe, nil
+}
+
+// Replay returns up to limit notifications delivered to the user after the
+// one with lastID, oldest first, so that a reconnecting stream can catch up.
+func (s *NotificationUseCase) Replay(ctx context.Context, userID, lastID uuid.UUID, limit int) ([]entity.Notification, error) {
+	last, err := s.repo.GetByID(ctx, lastID)
+	if err != nil {
+		return nil, err
+	}
+	if last.UserID != userID || last.Status != entity.StatusSent {
+		return nil, errs.ErrNotificationNotFound
+	}
+	return s.repo.ListSentAfter(ctx, last, limit)
+}
+
+// CountUnread returns how many delivered notifications of the user are
+// neither read nor archived.
+func (s *NotificationUseCase) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks a notification of the user as read.
+func (s *NotificationUseCase) MarkRead(ctx context.Context, userID, id uuid.UUID) (entity.Notification, error) {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+// MarkManyRead marks the given notifications of the user as read and
+// returns how many were unread.
+func (s *NotificationUseCase) MarkManyRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return s.repo.MarkManyRead(ctx, userID, ids)
+}
+
+// MarkAllRead marks every delivered notification of the user as read and
+// returns how many were unread.
+func (s *NotificationUseCase) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// Archive moves a sent notification of the user out of the inbox, or back
+// into it when archived is false.
+func (s *NotificationUseCase) Archive(ctx context.Context, userID, id uuid.UUID, archived bool) (entity.Notification, error) {
+	return s.repo.SetArchived(ctx, userID, id, archived)
+}
+
+// deliver hands a sent notification to the gateway and to live streams.
+func (s *NotificationUseCase) deliver(ctx context.Context, n entity.Notification) error {
+	if s.stream != nil {
+		s.stream.Publish(n)
 	}
+	start := time.Now()
+	err := s.gateway.Send(ctx, n)
+	s.metrics.ObserveDuration(ports.OpGatewaySend, time.Since(start))
+	return err
 }
 
-func (s *NotificationUseCase) Send(ctx context.Context, n entity.Notification) error {
-	rule, ok := s.rules[entity.NotificationType(n.Type)]
+// render fills n.Message from the template n references, if any, in the
+// best locale available for the recipient, and pins the template version
+// and locale that were used.
+func (s *NotificationUseCase) render(ctx context.Context, n *entity.Notification) error {
+	if n.TemplateID == nil {
+		return nil
+	}
+	if s.templates == nil {
+		return errs.ErrTemplateNotFound
+	}
+
+	tmpl, err := s.templates.Get(ctx, *n.TemplateID, n.TemplateVersion)
+	if err != nil {
+		return err
+	}
+
+	locale, err := s.preferredLocale(ctx, *n)
+	if err != nil {
+		return err
+	}
+	tmpl = tmpl.Localize(entity.LocaleFallbacks(locale, s.defaultLocale))
+
+	msg, err := tmpl.Render(n.TemplateData)
+	if err != nil {
+		return err
+	}
+	n.Message = msg
+	n.TemplateVersion = tmpl.Version
+	n.Locale = tmpl.Locale
+	return nil
+}
+
+// preferredLocale returns the locale requested on n, or else the one the
+// recipient chose in their preferences. It is empty when neither is set.
+func (s *NotificationUseCase) preferredLocale(ctx context.Context, n entity.Notification) (string, error) {
+	if n.Locale != "" || s.prefs == nil {
+		return n.Locale, nil
+	}
+
+	p, err := s.prefs.Get(ctx, n.UserID)
+	if errors.Is(err, errs.ErrUserPreferencesNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return p.Locale, nil
+}
+
+// replay looks up the notification stored under n's idempotency key. It
+// reports false when the key is unused or its retention has elapsed, and
+// fails with errs.ErrIdempotencyKeyConflict when the key was used for a
+// different request.
+func (s *NotificationUseCase) replay(ctx context.Context, n entity.Notification) (entity.Notification, bool, error) {
+	if n.IdempotencyKey == "" {
+		return entity.Notification{}, false, nil
+	}
+
+	original, err := s.repo.GetByIdempotencyKey(ctx, n.IdempotencyKey)
+	if errors.Is(err, errs.ErrNotificationNotFound) {
+		return entity.Notification{}, false, nil
+	}
+	if err != nil {
+		return entity.Notification{}, false, err
+	}
+
+	if s.idempotencyRetention > 0 && original.CreatedAt.Before(time.Now().Add(-s.idempotencyRetention)) {
+		return entity.Notification{}, false, s.repo.ReleaseIdempotencyKey(ctx, original.ID)
+	}
+	if original.RequestHash != n.RequestHash {
+		return entity.Notification{}, false, errs.ErrIdempotencyKeyConflict
+	}
+	return original, true, nil
+}
+
+// findDuplicate returns the notification with the same content sent or
+// scheduled for the user within the dedupe window of n's type, if any.
+func (s *NotificationUseCase) findDuplicate(ctx context.Context, n entity.Notification, now time.Time) (*entity.Notification, error) {
+	window, ok := s.dedupe[n.Type]
 	if !ok {
-		return errs.ErrInvalidNotification
+		return nil, nil
+	}
+
+	original, err := s.repo.FindDuplicate(ctx, n, now.Add(-window))
+	if errors.Is(err, errs.ErrNotificationNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &original, nil
+}
+
+// create persists n. When a concurrent request with the same idempotency key
+// was stored first, that notification is returned and replayed is true.
+func (s *NotificationUseCase) create(ctx context.Context, n entity.Notification) (entity.Notification, bool, error) {
+	saved, err := s.repo.Create(ctx, n)
+	if err == nil {
+		logging.FromContext(ctx).Info("notification stored", "status", saved.Status)
+	}
+	if !errors.Is(err, errs.ErrNotificationExists) || n.IdempotencyKey == "" {
+		return saved, false, err
+	}
+
+	original, replayed, rerr := s.replay(ctx, n)
+	if rerr != nil {
+		return entity.Notification{}, false, rerr
+	}
+	if !replayed {
+		return entity.Notification{}, false, err
+	}
+	return original, true, nil
+}
+
+func (s *NotificationUseCase) deliverScheduled(ctx context.Context, n entity.Notification) (bool, error) {
+	if _, ok := s.rules[n.Type]; !ok {
+		return false, errs.ErrInvalidNotification
 	}
 
-	since := time.Now().Add(-rule.Interval)
-	count, err := s.repo.CountInTimeWindow(ctx, n.UserID, n.Type, since)
+	log := logging.FromContext(ctx)
+	if now := time.Now().UTC(); n.IsExpiredAt(now) {
+		log.Info("scheduled notification expired")
+		_, err := s.repo.UpdateStatus(ctx, n.ID, entity.StatusSending, entity.StatusExpired, expiredReason(n), now)
+		return false, ignoreNotFound(err)
+	}
+
+	err := s.checkRateLimit(ctx, n.UserID, n.Type)
+	if errors.Is(err, errs.ErrRateLimitExceeded) {
+		to, reason := entity.StatusRateLimited, errs.ErrRateLimitExceeded.Error()
+		if s.digests[n.Type] != nil {
+			to, reason = entity.StatusHeld, heldReason
+		}
+		log.Info("scheduled notification rate limited", "status", to)
+		if to == entity.StatusRateLimited {
+			s.countNotification(n.Type, errs.ErrRateLimitExceeded)
+		}
+		_, err = s.repo.UpdateStatus(ctx, n.ID, entity.StatusSending, to, reason, time.Now())
+		return false, ignoreNotFound(err)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	sentAt := time.Now()
+	n.Status = entity.StatusSent
+	n.SentAt = &sentAt
+	if err := s.deliver(ctx, n); err != nil {
+		log.Warn("scheduled notification not delivered, retrying", "err", err)
+		_, rerr := s.repo.UpdateStatus(ctx, n.ID, entity.StatusSending, entity.StatusScheduled, retryReason, time.Now())
+		return false, errors.Join(err, ignoreNotFound(rerr))
+	}
+
+	// Not found when the lease ran out and another dispatch claimed it
+	// again, which then delivers it a second time.
+	_, err = s.repo.UpdateStatus(ctx, n.ID, entity.StatusSending, entity.StatusSent, "", sentAt)
+	return true, ignoreNotFound(err)
+}
+
+func (s *NotificationUseCase) flushDigest(ctx context.Context, g entity.DigestGroup) (bool, error) {
+	tmpl, ok := s.digests[g.Type]
+	if !ok {
+		return false, errs.ErrInvalidNotification
+	}
+
+	err := s.checkRateLimit(ctx, g.UserID, g.Type)
+	if errors.Is(err, errs.ErrRateLimitExceeded) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	digestID := uuid.New()
+	held, err := s.repo.ClaimHeld(ctx, g, fmt.Sprintf("rolled up into digest %s", digestID))
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	live := make([]entity.Notification, 0, len(held))
+	for _, n := range held {
+		if n.IsExpiredAt(now) {
+			if _, err := s.repo.UpdateStatus(ctx, n.ID, entity.StatusDigested, entity.StatusExpired, expiredReason(n), now); err != nil {
+				return false, err
+			}
+			continue
+		}
+		live = append(live, n)
+	}
+	if len(live) == 0 {
+		return false, nil
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].CreatedAt.Before(live[j].CreatedAt) })
+
+	var msg strings.Builder
+	data := entity.DigestData{Type: g.Type, Count: len(live), Notifications: live}
+	if err := tmpl.Execute(&msg, data); err != nil {
+		return false, err
+	}
+
+	digest, err := s.repo.Create(ctx, entity.Notification{
+		ID:      digestID,
+		UserID:  g.UserID,
+		Type:    g.Type,
+		Message: msg.String(),
+		Status:  entity.StatusSent,
+		SentAt:  &now,
+	})
+	if err != nil {
+		return false, err
+	}
+	ctx = logging.With(ctx, "notification_id", digest.ID)
+	logging.FromContext(ctx).Info("digest stored", "count", len(live))
+
+	if err := s.deliver(ctx, digest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *NotificationUseCase) checkRateLimit(ctx context.Context, userID uuid.UUID, notifType entity.NotificationType) error {
+	rule, err := s.rateLimit(ctx, notifType)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	count, err := s.repo.CountInTimeWindow(ctx, userID, notifType, start.Add(-rule.Interval))
+	s.metrics.ObserveDuration(ports.OpCountInTimeWindow, time.Since(start))
 	if err != nil {
 		return err
 	}
@@ -42,11 +684,107 @@ func (s *NotificationUseCase) Send(ctx context.Context, n entity.Notification) e
 	if count >= rule.Limit {
 		return errs.ErrRateLimitExceeded
 	}
+	return nil
+}
 
-	saved, err := s.repo.Create(ctx, n)
+// rateLimit returns the rate limit of notifType for the tenant of ctx: the
+// tenant's own rule when it has one, or else the default.
+func (s *NotificationUseCase) rateLimit(ctx context.Context, notifType entity.NotificationType) (entity.RateLimit, error) {
+	rule, ok := s.rules[notifType]
+	if !ok {
+		return entity.RateLimit{}, errs.ErrInvalidNotification
+	}
+	if s.overrides == nil {
+		return rule, nil
+	}
+
+	override, err := s.overrides.Get(ctx, notifType)
+	if errors.Is(err, errs.ErrRateLimitRuleNotFound) {
+		return rule, nil
+	}
 	if err != nil {
-		return err
+		return entity.RateLimit{}, err
+	}
+	return override.RateLimit, nil
+}
+
+// countNotification records that a notification of notifType ended with
+// err and returns its outcome. Types without a rate limit are counted as
+// unknown, so that clients cannot make up new series.
+func (s *NotificationUseCase) countNotification(notifType entity.NotificationType, err error) ports.NotificationOutcome {
+	if _, ok := s.rules[notifType]; !ok {
+		notifType = "unknown"
+	}
+	outcome := outcomeOf(err)
+	s.metrics.CountNotification(notifType, outcome)
+	return outcome
+}
+
+func outcomeOf(err error) ports.NotificationOutcome {
+	switch {
+	case err == nil:
+		return ports.OutcomeAccepted
+	case errors.Is(err, errs.ErrRateLimitExceeded):
+		return ports.OutcomeRateLimited
 	}
+	switch errs.As(err).Kind {
+	case errs.KindInternal, errs.KindUnavailable:
+		return ports.OutcomeFailed
+	default:
+		return ports.OutcomeInvalid
+	}
+}
+
+// retryable reports whether a send that failed with err may succeed when
+// tried again, such as when the database or a gateway is unavailable.
+func retryable(err error) bool {
+	return outcomeOf(err) == ports.OutcomeFailed
+}
+
+// noMetrics discards metrics until WithMetrics sets where they go.
+type noMetrics struct{}
+
+func (noMetrics) CountNotification(entity.NotificationType, ports.NotificationOutcome) {}
+
+func (noMetrics) ObserveDuration(ports.Operation, time.Duration) {}
 
-	return s.gateway.Send(saved)
+// noTracer records no spans until WithTracer sets where they go.
+type noTracer struct{}
+
+func (noTracer) Start(ctx context.Context, _ string, _ ...any) (context.Context, ports.Span) {
+	return ctx, noSpan{}
+}
+
+type noSpan struct{}
+
+func (noSpan) SetAttributes(...any) {}
+
+func (noSpan) End(error) {}
+
+// withNotification returns a copy of ctx whose logger identifies n, so
+// that the repository and gateways log which notification they act on.
+func withNotification(ctx context.Context, n entity.Notification) context.Context {
+	return logging.With(ctx, "notification_id", n.ID, "user_id", n.UserID, "type", n.Type)
+}
+
+// withTenant returns a copy of ctx acting in, and logging, tenant.
+func withTenant(ctx context.Context, tenant entity.TenantID) context.Context {
+	ctx = entity.WithTenant(ctx, tenant)
+	return logging.With(ctx, "tenant_id", entity.TenantOf(ctx))
+}
+
+const (
+	heldReason  = "rate limit exceeded, held for digest"
+	retryReason = "delivery failed, retrying"
+)
+
+func expiredReason(n entity.Notification) string {
+	return fmt.Sprintf("expired at %s before delivery", n.ExpiresAt.UTC().Format(time.RFC3339))
+}
+
+func ignoreNotFound(err error) error {
+	if errors.Is(err, errs.ErrNotificationNotFound) {
+		return nil
+	}
+	return err
 }
@@ -1,9 +1,12 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
+	"text/template"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,8 +14,10 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/config/logging"
 	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
 	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
 )
 
 type MockRepo struct {
@@ -29,11 +34,97 @@ func (m *MockRepo) CountInTimeWindow(ctx context.Context, userID uuid.UUID, noti
 	return args.Get(0).(int), args.Error(1)
 }
 
+func (m *MockRepo) CreateMany(ctx context.Context, ns []entity.Notification) error {
+	args := m.Called(ctx, ns)
+	return args.Error(0)
+}
+
+func (m *MockRepo) CountInTimeWindowByUser(ctx context.Context, userIDs []uuid.UUID, notifType entity.NotificationType, since time.Time) (map[uuid.UUID]int, error) {
+	args := m.Called(ctx, userIDs, notifType, since)
+	counts, _ := args.Get(0).(map[uuid.UUID]int)
+	return counts, args.Error(1)
+}
+
+func (m *MockRepo) FindDuplicate(ctx context.Context, n entity.Notification, since time.Time) (entity.Notification, error) {
+	args := m.Called(ctx, n, since)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) GetByID(ctx context.Context, id uuid.UUID) (entity.Notification, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) GetByIdempotencyKey(ctx context.Context, key string) (entity.Notification, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ReleaseIdempotencyKey(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepo) ClaimDue(ctx context.Context, now, leasedUntil time.Time, limit int) ([]entity.Notification, error) {
+	args := m.Called(ctx, now, leasedUntil, limit)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ListHeldGroups(ctx context.Context, after entity.DigestGroup, limit int) ([]entity.DigestGroup, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]entity.DigestGroup), args.Error(1)
+}
+
+func (m *MockRepo) ClaimHeld(ctx context.Context, g entity.DigestGroup, reason string) ([]entity.Notification, error) {
+	args := m.Called(ctx, g, reason)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) List(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) ListSentAfter(ctx context.Context, last entity.Notification, limit int) ([]entity.Notification, error) {
+	args := m.Called(ctx, last, limit)
+	return args.Get(0).([]entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) MarkRead(ctx context.Context, userID, id uuid.UUID) (entity.Notification, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) MarkManyRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int), args.Error(1)
+}
+
+func (m *MockRepo) SetArchived(ctx context.Context, userID, id uuid.UUID, archived bool) (entity.Notification, error) {
+	args := m.Called(ctx, userID, id, archived)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
+func (m *MockRepo) UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.NotificationStatus, reason string, at time.Time) (entity.Notification, error) {
+	args := m.Called(ctx, id, from, to, reason, at)
+	return args.Get(0).(entity.Notification), args.Error(1)
+}
+
 type MockGateway struct {
 	mock.Mock
 }
 
-func (m *MockGateway) Send(n entity.Notification) error {
+func (m *MockGateway) Send(_ context.Context, n entity.Notification) error {
 	args := m.Called(n)
 	return args.Error(0)
 }
@@ -52,7 +143,7 @@ func TestSendNotificationSuccess(t *testing.T) {
 
 	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
 
-	err := svc.Send(context.Background(), entity.Notification{
+	_, err := svc.Send(context.Background(), entity.Notification{
 		UserID:  userID,
 		Type:    entity.Status,
 		Message: "hello",
@@ -63,6 +154,74 @@ func TestSendNotificationSuccess(t *testing.T) {
 	gw.AssertExpectations(t)
 }
 
+func TestSendNotificationLogsNotification(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	id := uuid.New()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "json", "info")
+	assert.NoError(t, err)
+	ctx := logging.WithLogger(context.Background(), logger)
+
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	saved := entity.Notification{ID: id, UserID: userID, Type: entity.Status, Status: entity.StatusSent}
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(saved, nil)
+	gw.On("Send", saved).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	_, err = svc.Send(ctx, entity.Notification{ID: id, UserID: userID, Type: entity.Status, Message: "hello"})
+	assert.NoError(t, err)
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "notification stored", line["msg"])
+	assert.Equal(t, id.String(), line["notification_id"])
+	assert.Equal(t, userID.String(), line["user_id"])
+	assert.Equal(t, "status", line["type"])
+	assert.Equal(t, "sent", line["status"])
+}
+
+type MockMetrics struct {
+	mock.Mock
+}
+
+func (m *MockMetrics) CountNotification(notifType entity.NotificationType, outcome ports.NotificationOutcome) {
+	m.Called(notifType, outcome)
+}
+
+func (m *MockMetrics) ObserveDuration(op ports.Operation, d time.Duration) {
+	m.Called(op, d)
+}
+
+func TestSendNotificationRecordsMetrics(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	m := new(MockMetrics)
+	userID := uuid.New()
+
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil).Once()
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(2, nil).Once()
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{UserID: userID, Type: entity.Status}, nil)
+	gw.On("Send", mock.Anything).Return(nil)
+	m.On("ObserveDuration", mock.Anything, mock.Anything)
+	m.On("CountNotification", mock.Anything, mock.Anything)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithMetrics(m))
+	svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, Message: "hello"})
+	svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, Message: "hello"})
+	svc.Send(context.Background(), entity.Notification{UserID: userID, Type: "bogus", Message: "hello"})
+
+	m.AssertCalled(t, "CountNotification", entity.Status, ports.OutcomeAccepted)
+	m.AssertCalled(t, "CountNotification", entity.Status, ports.OutcomeRateLimited)
+	m.AssertCalled(t, "CountNotification", entity.NotificationType("unknown"), ports.OutcomeInvalid)
+	m.AssertNumberOfCalls(t, "CountNotification", 3)
+	for _, op := range []ports.Operation{ports.OpSend, ports.OpCountInTimeWindow, ports.OpGatewaySend} {
+		m.AssertCalled(t, "ObserveDuration", op, mock.AnythingOfType("time.Duration"))
+	}
+}
+
 func TestNotificationRateLimitExceeded(t *testing.T) {
 	repo := new(MockRepo)
 	gw := new(MockGateway)
@@ -72,7 +231,7 @@ func TestNotificationRateLimitExceeded(t *testing.T) {
 
 	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
 
-	err := svc.Send(context.Background(), entity.Notification{
+	_, err := svc.Send(context.Background(), entity.Notification{
 		UserID:  userID,
 		Type:    entity.Status,
 		Message: "hello",
@@ -84,6 +243,46 @@ func TestNotificationRateLimitExceeded(t *testing.T) {
 	gw.AssertNotCalled(t, "Send", mock.Anything)
 }
 
+func TestNotificationTenantRateLimitOverridesDefault(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	rules := new(MockRateLimitRuleRepo)
+	userID := uuid.New()
+	ctx := entity.WithTenant(context.Background(), "shop")
+	saved := entity.Notification{ID: uuid.New(), TenantID: "shop", UserID: userID, Type: entity.Status, Status: entity.StatusSent}
+
+	inShop := mock.MatchedBy(func(ctx context.Context) bool { return entity.TenantOf(ctx) == "shop" })
+	rules.On("Get", inShop, entity.Status).Return(entity.RateLimitRule{
+		Type: entity.Status, RateLimit: entity.RateLimit{Limit: 5, Interval: time.Minute},
+	}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(2, nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(saved, nil)
+	gw.On("Send", saved).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTenantRateLimits(rules))
+	_, err := svc.Send(ctx, entity.Notification{UserID: userID, Type: entity.Status, Message: "hello"})
+
+	assert.NoError(t, err)
+	rules.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestNotificationTenantWithoutRuleUsesDefault(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	rules := new(MockRateLimitRuleRepo)
+	userID := uuid.New()
+
+	rules.On("Get", mock.Anything, entity.Status).Return(entity.RateLimitRule{}, errs.ErrRateLimitRuleNotFound)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(2, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTenantRateLimits(rules))
+	_, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, Message: "hello"})
+
+	assert.ErrorIs(t, err, errs.ErrRateLimitExceeded)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
 func TestSendNotificationGatewayError(t *testing.T) {
 	repo := new(MockRepo)
 	gw := new(MockGateway)
@@ -98,7 +297,7 @@ func TestSendNotificationGatewayError(t *testing.T) {
 
 	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
 
-	err := svc.Send(context.Background(), entity.Notification{
+	_, err := svc.Send(context.Background(), entity.Notification{
 		UserID:  userID,
 		Type:    entity.Status,
 		Message: "hello",
@@ -110,3 +309,586 @@ func TestSendNotificationGatewayError(t *testing.T) {
 	repo.AssertExpectations(t)
 	gw.AssertExpectations(t)
 }
+
+func TestSendNotificationScheduled(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	sendAt := time.Now().Add(time.Hour)
+
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Status == entity.StatusScheduled && n.SendAt.Equal(sendAt) && n.SentAt == nil
+	})).Return(entity.Notification{ID: uuid.New(), UserID: userID, Status: entity.StatusScheduled}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	saved, err := svc.Send(context.Background(), entity.Notification{
+		UserID:  userID,
+		Type:    entity.Status,
+		Message: "hello",
+		SendAt:  &sendAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusScheduled, saved.Status)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestDispatchDueSendsAndRateLimits(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	now := time.Now()
+	allowed := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.Status, Status: entity.StatusSending}
+	limited := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.Status, Status: entity.StatusSending}
+
+	repo.On("ClaimDue", mock.MatchedBy(entity.ActsForAllTenants), now, now.Add(time.Minute), 10).Return([]entity.Notification{allowed, limited}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, allowed.UserID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("CountInTimeWindow", mock.Anything, limited.UserID, entity.Status, mock.Anything).Return(2, nil)
+
+	var sentAt time.Time
+	gw.On("Send", mock.MatchedBy(func(n entity.Notification) bool {
+		if n.ID != allowed.ID || n.Status != entity.StatusSent || n.SentAt == nil || n.SentAt.Before(now) {
+			return false
+		}
+		sentAt = *n.SentAt
+		return true
+	})).Return(nil)
+	repo.On("UpdateStatus", mock.Anything, allowed.ID, entity.StatusSending, entity.StatusSent, mock.Anything, mock.MatchedBy(func(at time.Time) bool {
+		return at.Equal(sentAt)
+	})).Return(allowed, nil)
+	repo.On("UpdateStatus", mock.Anything, limited.ID, entity.StatusSending, entity.StatusRateLimited, mock.Anything, mock.Anything).Return(limited, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	count, err := svc.DispatchDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestDispatchDueReschedulesWhenTheGatewayFails(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	now := time.Now()
+	n := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.News, Status: entity.StatusSending}
+	failure := errors.New("gateway down")
+
+	repo.On("ClaimDue", mock.Anything, now, now.Add(30*time.Second), 10).Return([]entity.Notification{n}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, n.UserID, entity.News, mock.Anything).Return(0, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(failure)
+	repo.On("UpdateStatus", mock.Anything, n.ID, entity.StatusSending, entity.StatusScheduled, mock.Anything, mock.Anything).Return(n, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDispatchLease(30*time.Second))
+
+	count, err := svc.DispatchDue(context.Background(), now, 10)
+
+	assert.ErrorIs(t, err, failure)
+	assert.Equal(t, 0, count)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, n.ID, entity.StatusSending, entity.StatusSent, mock.Anything, mock.Anything)
+}
+
+func TestDispatchDueCountsNotificationsReclaimedAfterDelivery(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	now := time.Now()
+	n := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.News, Status: entity.StatusSending}
+
+	repo.On("ClaimDue", mock.Anything, now, now.Add(time.Minute), 10).Return([]entity.Notification{n}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, n.UserID, entity.News, mock.Anything).Return(0, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+	repo.On("UpdateStatus", mock.Anything, n.ID, entity.StatusSending, entity.StatusSent, mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	count, err := svc.DispatchDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestCancelNotification(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	id := uuid.New()
+
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{ID: id, Status: entity.StatusCanceled}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	assert.NoError(t, svc.Cancel(context.Background(), id))
+	repo.AssertExpectations(t)
+}
+
+func TestCancelNotificationAlreadySent(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	id := uuid.New()
+
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByID", mock.Anything, id).Return(entity.Notification{ID: id, Status: entity.StatusSent}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	err := svc.Cancel(context.Background(), id)
+	assert.True(t, errors.Is(err, errs.ErrNotificationNotCancellable))
+	repo.AssertExpectations(t)
+}
+
+func TestCancelNotificationNotFound(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	id := uuid.New()
+
+	repo.On("UpdateStatus", mock.Anything, id, entity.StatusScheduled, entity.StatusCanceled, mock.Anything, mock.Anything).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("GetByID", mock.Anything, id).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	err := svc.Cancel(context.Background(), id)
+	assert.True(t, errors.Is(err, errs.ErrNotificationNotFound))
+}
+
+func TestSendNotificationAppliesDefaultTTL(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.ExpiresAt != nil && n.ExpiresAt.Location() == time.UTC && n.ExpiresAt.After(time.Now().Add(9*time.Minute))
+	})).Return(entity.Notification{UserID: userID, Type: entity.Status, Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTTLs(entity.DefaultTTLs))
+
+	_, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, Message: "hello"})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationAlreadyExpired(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	expiresAt := time.Now().Add(-time.Second)
+
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Status == entity.StatusExpired && n.StatusReason != ""
+	})).Return(entity.Notification{Status: entity.StatusExpired}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	saved, err := svc.Send(context.Background(), entity.Notification{
+		UserID:    uuid.New(),
+		Type:      entity.Status,
+		Message:   "driver is 2 minutes away",
+		ExpiresAt: &expiresAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusExpired, saved.Status)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestDispatchDueExpiresStaleNotification(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	now := time.Now()
+	expiresAt := now.Add(-time.Minute)
+	n := entity.Notification{ID: uuid.New(), UserID: uuid.New(), Type: entity.Status, Status: entity.StatusSending, ExpiresAt: &expiresAt}
+
+	repo.On("ClaimDue", mock.Anything, now, now.Add(time.Minute), 10).Return([]entity.Notification{n}, nil)
+	repo.On("UpdateStatus", mock.Anything, n.ID, entity.StatusSending, entity.StatusExpired, mock.MatchedBy(func(reason string) bool {
+		return reason != ""
+	}), mock.Anything).Return(entity.Notification{}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	count, err := svc.DispatchDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationIdempotentReplay(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	original := entity.Notification{ID: uuid.New(), Status: entity.StatusSent, IdempotencyKey: "key-1", RequestHash: "hash", CreatedAt: time.Now()}
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(original, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithIdempotencyRetention(time.Hour))
+
+	saved, err := svc.Send(context.Background(), entity.Notification{
+		UserID:         uuid.New(),
+		Type:           entity.Status,
+		Message:        "hello",
+		IdempotencyKey: "key-1",
+		RequestHash:    "hash",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, original.ID, saved.ID)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationIdempotencyConflict(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	original := entity.Notification{ID: uuid.New(), IdempotencyKey: "key-1", RequestHash: "hash", CreatedAt: time.Now()}
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(original, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	_, err := svc.Send(context.Background(), entity.Notification{
+		UserID:         uuid.New(),
+		Type:           entity.Status,
+		Message:        "something else",
+		IdempotencyKey: "key-1",
+		RequestHash:    "other",
+	})
+
+	assert.True(t, errors.Is(err, errs.ErrIdempotencyKeyConflict))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestSendNotificationIdempotencyKeyPastRetention(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	stale := entity.Notification{ID: uuid.New(), IdempotencyKey: "key-1", RequestHash: "hash", CreatedAt: time.Now().Add(-2 * time.Hour)}
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(stale, nil)
+	repo.On("ReleaseIdempotencyKey", mock.Anything, stale.ID).Return(nil)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{ID: uuid.New(), Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithIdempotencyRetention(time.Hour))
+
+	saved, err := svc.Send(context.Background(), entity.Notification{
+		UserID:         userID,
+		Type:           entity.Status,
+		Message:        "hello",
+		IdempotencyKey: "key-1",
+		RequestHash:    "hash",
+	})
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, stale.ID, saved.ID)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestSendNotificationIdempotentConcurrentInsert(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	winner := entity.Notification{ID: uuid.New(), Status: entity.StatusSent, IdempotencyKey: "key-1", RequestHash: "hash", CreatedAt: time.Now()}
+
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(entity.Notification{}, errs.ErrNotificationNotFound).Once()
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{}, errs.ErrNotificationExists)
+	repo.On("GetByIdempotencyKey", mock.Anything, "key-1").Return(winner, nil).Once()
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+
+	saved, err := svc.Send(context.Background(), entity.Notification{
+		UserID:         userID,
+		Type:           entity.Status,
+		Message:        "hello",
+		IdempotencyKey: "key-1",
+		RequestHash:    "hash",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, winner.ID, saved.ID)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationDuplicateSuppressed(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	original := entity.Notification{ID: uuid.New(), UserID: userID, Type: entity.News, Message: "breaking", Status: entity.StatusSent}
+
+	repo.On("FindDuplicate", mock.Anything, mock.AnythingOfType("entity.Notification"), mock.AnythingOfType("time.Time")).Return(original, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Status == entity.StatusDuplicate && n.StatusReason == "duplicate of notification "+original.ID.String()
+	})).Return(entity.Notification{ID: uuid.New(), Status: entity.StatusDuplicate}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDedupeWindows(entity.DefaultDedupeWindows))
+
+	saved, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.News, Message: "breaking"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusDuplicate, saved.Status)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "CountInTimeWindow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestSendNotificationNoDuplicateWithinWindow(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	before := time.Now()
+
+	repo.On("FindDuplicate", mock.Anything, mock.AnythingOfType("entity.Notification"), mock.MatchedBy(func(since time.Time) bool {
+		return !since.After(before.Add(-10 * time.Minute).Add(time.Second))
+	})).Return(entity.Notification{}, errs.ErrNotificationNotFound)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.News, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDedupeWindows(entity.DefaultDedupeWindows))
+
+	saved, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.News, Message: "breaking"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusSent, saved.Status)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestSendNotificationHeldForDigest(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Marketing, mock.Anything).Return(3, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Status == entity.StatusHeld
+	})).Return(entity.Notification{Status: entity.StatusHeld}, nil)
+
+	tmpl := template.Must(template.New("digest").Parse(entity.DefaultDigestTemplate))
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDigests(tmpl, entity.Marketing))
+
+	saved, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Marketing, Message: "sale"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusHeld, saved.Status)
+	repo.AssertExpectations(t)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestFlushDigestsSendsRollup(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	g := entity.DigestGroup{UserID: userID, Type: entity.Marketing}
+	first := time.Now().Add(-time.Hour)
+	held := []entity.Notification{
+		{ID: uuid.New(), UserID: userID, Type: entity.Marketing, Message: "second", CreatedAt: first.Add(time.Minute)},
+		{ID: uuid.New(), UserID: userID, Type: entity.Marketing, Message: "first", CreatedAt: first},
+	}
+
+	repo.On("ListHeldGroups", mock.Anything, entity.DigestGroup{}, 10).Return([]entity.DigestGroup{g}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Marketing, mock.Anything).Return(0, nil)
+	repo.On("ClaimHeld", mock.Anything, g, mock.AnythingOfType("string")).Return(held, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Status == entity.StatusSent && n.Message == "You have 2 new marketing notifications:\n- first\n- second\n"
+	})).Return(entity.Notification{UserID: userID, Type: entity.Marketing, Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	tmpl := template.Must(template.New("digest").Parse(entity.DefaultDigestTemplate))
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDigests(tmpl, entity.Marketing))
+
+	count, err := svc.FlushDigests(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestFlushDigestsWaitsForWindow(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	g := entity.DigestGroup{UserID: uuid.New(), Type: entity.Marketing}
+
+	repo.On("ListHeldGroups", mock.Anything, entity.DigestGroup{}, 10).Return([]entity.DigestGroup{g}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, g.UserID, entity.Marketing, mock.Anything).Return(3, nil)
+
+	tmpl := template.Must(template.New("digest").Parse(entity.DefaultDigestTemplate))
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDigests(tmpl, entity.Marketing))
+
+	count, err := svc.FlushDigests(context.Background(), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	repo.AssertNotCalled(t, "ClaimHeld", mock.Anything, mock.Anything, mock.Anything)
+	gw.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestFlushDigestsPagesPastGroupsOverTheirLimit(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	first := entity.DigestGroup{TenantID: "default", UserID: uuid.New(), Type: entity.Marketing}
+	second := entity.DigestGroup{TenantID: "shop", UserID: uuid.New(), Type: entity.Marketing}
+
+	repo.On("ListHeldGroups", mock.Anything, entity.DigestGroup{}, 1).Return([]entity.DigestGroup{first}, nil).Once()
+	repo.On("ListHeldGroups", mock.Anything, first, 1).Return([]entity.DigestGroup{second}, nil).Once()
+	repo.On("ListHeldGroups", mock.Anything, second, 1).Return([]entity.DigestGroup{}, nil).Once()
+	repo.On("ListHeldGroups", mock.Anything, entity.DigestGroup{}, 1).Return([]entity.DigestGroup{first}, nil).Once()
+	repo.On("CountInTimeWindow", mock.Anything, mock.Anything, entity.Marketing, mock.Anything).Return(3, nil)
+
+	tmpl := template.Must(template.New("digest").Parse(entity.DefaultDigestTemplate))
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDigests(tmpl, entity.Marketing))
+
+	for range 4 {
+		_, err := svc.FlushDigests(context.Background(), 1)
+		assert.NoError(t, err)
+	}
+	repo.AssertExpectations(t)
+	repo.AssertNumberOfCalls(t, "CountInTimeWindow", 3)
+}
+
+func TestListNotificationsReturnsNextCursor(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+	now := time.Now()
+	rows := []entity.Notification{
+		{ID: uuid.New(), UserID: userID, CreatedAt: now},
+		{ID: uuid.New(), UserID: userID, CreatedAt: now.Add(-time.Minute)},
+		{ID: uuid.New(), UserID: userID, CreatedAt: now.Add(-2 * time.Minute)},
+	}
+
+	repo.On("List", mock.Anything, entity.NotificationFilter{UserID: userID, Limit: 3}).Return(rows, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	page, err := svc.List(context.Background(), entity.NotificationFilter{UserID: userID, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Notifications, 2)
+	assert.Equal(t, &entity.NotificationCursor{CreatedAt: rows[1].CreatedAt, ID: rows[1].ID}, page.Next)
+}
+
+func TestListNotificationsLastPage(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	userID := uuid.New()
+
+	repo.On("List", mock.Anything, entity.NotificationFilter{UserID: userID, Limit: entity.DefaultPageSize + 1}).Return([]entity.Notification{{ID: uuid.New()}}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	page, err := svc.List(context.Background(), entity.NotificationFilter{UserID: userID})
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Notifications, 1)
+	assert.Nil(t, page.Next)
+}
+
+type MockStream struct {
+	mock.Mock
+}
+
+func (m *MockStream) Publish(n entity.Notification) {
+	m.Called(n)
+}
+
+func (m *MockStream) Subscribe(tenant entity.TenantID, userID uuid.UUID) (<-chan entity.Notification, func()) {
+	args := m.Called(tenant, userID)
+	return args.Get(0).(<-chan entity.Notification), args.Get(1).(func())
+}
+
+func TestSendNotificationPublishesToStream(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	stream := new(MockStream)
+	userID := uuid.New()
+	saved := entity.Notification{ID: uuid.New(), UserID: userID, Type: entity.Status, Status: entity.StatusSent}
+
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.AnythingOfType("entity.Notification")).Return(saved, nil)
+	gw.On("Send", saved).Return(nil)
+	stream.On("Publish", saved).Return()
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithStream(stream))
+	_, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, Message: "hi"})
+
+	assert.NoError(t, err)
+	stream.AssertExpectations(t)
+}
+
+func TestReplayRejectsOtherUsersNotification(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	id := uuid.New()
+
+	repo.On("GetByID", mock.Anything, id).Return(entity.Notification{ID: id, UserID: uuid.New(), Status: entity.StatusSent}, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits)
+	_, err := svc.Replay(context.Background(), uuid.New(), id, 10)
+
+	assert.ErrorIs(t, err, errs.ErrNotificationNotFound)
+	repo.AssertNotCalled(t, "ListSentAfter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSendBulkAppliesLimitsPerRecipient(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	alice, bob, carol := uuid.New(), uuid.New(), uuid.New()
+
+	// status allows 2 per minute and alice already has one.
+	repo.On("CountInTimeWindowByUser", mock.Anything, []uuid.UUID{alice, bob}, entity.Status, mock.Anything).Return(map[uuid.UUID]int{alice: 1}, nil)
+	repo.On("CountInTimeWindowByUser", mock.Anything, []uuid.UUID{carol}, entity.Marketing, mock.Anything).Return(map[uuid.UUID]int{carol: 3}, nil)
+	repo.On("CreateMany", mock.Anything, mock.MatchedBy(func(ns []entity.Notification) bool {
+		for _, n := range ns {
+			if n.CreatedAt.Location() != time.UTC || n.CreatedAt.IsZero() {
+				return false
+			}
+		}
+		return len(ns) == 3 && ns[0].Status == entity.StatusSent && ns[1].Status == entity.StatusSent && ns[2].Status == entity.StatusHeld
+	})).Return(nil)
+	gw.On("Send", mock.Anything).Return(nil).Twice()
+
+	tmpl := template.Must(template.New("digest").Parse(entity.DefaultDigestTemplate))
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithDigests(tmpl, entity.Marketing))
+
+	results, err := svc.SendBulk(context.Background(), []entity.Notification{
+		{UserID: alice, Type: entity.Status, Message: "one"},
+		{UserID: alice, Type: entity.Status, Message: "two"},
+		{UserID: bob, Type: entity.Status, Message: "one"},
+		{UserID: bob, Type: "unknown", Message: "one"},
+		{UserID: carol, Type: entity.Marketing, Message: "sale"},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, entity.StatusSent, results[0].Notification.Status)
+	assert.NotEqual(t, uuid.Nil, results[0].Notification.ID)
+	assert.ErrorIs(t, results[1].Err, errs.ErrRateLimitExceeded)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, bob, results[2].Notification.UserID)
+	assert.ErrorIs(t, results[3].Err, errs.ErrInvalidNotification)
+	assert.NoError(t, results[4].Err)
+	assert.Equal(t, entity.StatusHeld, results[4].Notification.Status)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestSendBulkNothingToStore(t *testing.T) {
+	repo := new(MockRepo)
+	svc := usecase.NewNotificationUseCase(repo, new(MockGateway), entity.DefaultRateLimits)
+
+	results, err := svc.SendBulk(context.Background(), []entity.Notification{{UserID: uuid.New(), Type: entity.News}})
+
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, errs.ErrInvalidNotification)
+	repo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+}
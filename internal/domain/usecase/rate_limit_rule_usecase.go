@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+)
+
+// RateLimitRuleUseCase manages the rate limits the tenant in the context
+// overrides. NotificationUseCase applies them when built with
+// WithTenantRateLimits over the same repository.
+type RateLimitRuleUseCase struct {
+	repo     ports.RateLimitRuleRepository
+	defaults map[entity.NotificationType]entity.RateLimit
+}
+
+func NewRateLimitRuleUseCase(repo ports.RateLimitRuleRepository, defaults map[entity.NotificationType]entity.RateLimit) *RateLimitRuleUseCase {
+	return &RateLimitRuleUseCase{repo: repo, defaults: defaults}
+}
+
+// List returns the rate limit in effect for every type, sorted by type:
+// the tenant's own rule where it has one, or else the default.
+func (s *RateLimitRuleUseCase) List(ctx context.Context) ([]entity.RateLimitRule, error) {
+	overrides, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[entity.NotificationType]entity.RateLimitRule, len(s.defaults))
+	for notifType, limit := range s.defaults {
+		byType[notifType] = entity.RateLimitRule{Type: notifType, RateLimit: limit, Default: true}
+	}
+	for _, rule := range overrides {
+		if _, ok := byType[rule.Type]; ok {
+			byType[rule.Type] = rule
+		}
+	}
+
+	rules := make([]entity.RateLimitRule, 0, len(byType))
+	for _, rule := range byType {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Type < rules[j].Type })
+	return rules, nil
+}
+
+// Put overrides the rate limit of rule.Type for the tenant.
+func (s *RateLimitRuleUseCase) Put(ctx context.Context, rule entity.RateLimitRule) (entity.RateLimitRule, error) {
+	if err := rule.Validate(); err != nil {
+		return entity.RateLimitRule{}, err
+	}
+	if _, ok := s.defaults[rule.Type]; !ok {
+		return entity.RateLimitRule{}, fmt.Errorf("%w: type %q is not rate limited", errs.ErrInvalidRateLimitRule, rule.Type)
+	}
+
+	rule.Default = false
+	return s.repo.Upsert(ctx, rule)
+}
+
+// Delete drops the tenant's override of notifType, so that the default
+// applies again. It returns errs.ErrRateLimitRuleNotFound when the tenant
+// had not overridden it.
+func (s *RateLimitRuleUseCase) Delete(ctx context.Context, notifType entity.NotificationType) error {
+	return s.repo.Delete(ctx, notifType)
+}
@@ -0,0 +1,72 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+type MockRateLimitRuleRepo struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRuleRepo) Get(ctx context.Context, notifType entity.NotificationType) (entity.RateLimitRule, error) {
+	args := m.Called(ctx, notifType)
+	return args.Get(0).(entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) List(ctx context.Context) ([]entity.RateLimitRule, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) Upsert(ctx context.Context, rule entity.RateLimitRule) (entity.RateLimitRule, error) {
+	args := m.Called(ctx, rule)
+	return args.Get(0).(entity.RateLimitRule), args.Error(1)
+}
+
+func (m *MockRateLimitRuleRepo) Delete(ctx context.Context, notifType entity.NotificationType) error {
+	return m.Called(ctx, notifType).Error(0)
+}
+
+func TestListRateLimitRulesFallsBackToDefaults(t *testing.T) {
+	repo := new(MockRateLimitRuleRepo)
+	override := entity.RateLimitRule{Type: entity.News, RateLimit: entity.RateLimit{Limit: 5, Interval: time.Hour}}
+	repo.On("List", mock.Anything).Return([]entity.RateLimitRule{override}, nil)
+
+	svc := usecase.NewRateLimitRuleUseCase(repo, entity.DefaultRateLimits)
+	rules, err := svc.List(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.RateLimitRule{
+		{Type: entity.Marketing, RateLimit: entity.DefaultRateLimits[entity.Marketing], Default: true},
+		override,
+		{Type: entity.Status, RateLimit: entity.DefaultRateLimits[entity.Status], Default: true},
+	}, rules)
+}
+
+func TestPutRateLimitRuleRejectsInvalidRule(t *testing.T) {
+	svc := usecase.NewRateLimitRuleUseCase(new(MockRateLimitRuleRepo), entity.DefaultRateLimits)
+	_, err := svc.Put(context.Background(), entity.RateLimitRule{Type: entity.News, RateLimit: entity.RateLimit{Limit: 0, Interval: time.Hour}})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidRateLimitRule)
+}
+
+func TestPutRateLimitRuleStoresOverride(t *testing.T) {
+	repo := new(MockRateLimitRuleRepo)
+	rule := entity.RateLimitRule{Type: entity.Status, RateLimit: entity.RateLimit{Limit: 10, Interval: time.Minute}}
+	repo.On("Upsert", mock.Anything, rule).Return(rule, nil)
+
+	svc := usecase.NewRateLimitRuleUseCase(repo, entity.DefaultRateLimits)
+	_, err := svc.Put(context.Background(), rule)
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
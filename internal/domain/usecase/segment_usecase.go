@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+type SegmentUseCase struct {
+	segments ports.SegmentRepository
+}
+
+func NewSegmentUseCase(segments ports.SegmentRepository) *SegmentUseCase {
+	return &SegmentUseCase{segments: segments}
+}
+
+// Create stores a new segment. Repeated user ids and tags are dropped.
+func (s *SegmentUseCase) Create(ctx context.Context, seg entity.Segment) (entity.Segment, error) {
+	seg.UserIDs = uniqueIDs(seg.UserIDs)
+	seg.Tags = uniqueTags(seg.Tags)
+	if err := seg.Validate(); err != nil {
+		return entity.Segment{}, err
+	}
+
+	seg.ID = uuid.New()
+	return s.segments.Create(ctx, seg)
+}
+
+// Get returns a segment with the number of users it currently matches.
+func (s *SegmentUseCase) Get(ctx context.Context, id uuid.UUID) (entity.Segment, int, error) {
+	seg, err := s.segments.Get(ctx, id)
+	if err != nil {
+		return entity.Segment{}, 0, err
+	}
+	size, err := s.segments.Count(ctx, seg)
+	if err != nil {
+		return entity.Segment{}, 0, err
+	}
+	return seg, size, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := ids[:0:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// uniqueTags trims tags and drops empty and repeated ones, sorted so that
+// equal sets are stored the same way.
+func uniqueTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
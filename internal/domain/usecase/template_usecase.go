@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+type TemplateUseCase struct {
+	repo ports.TemplateRepository
+}
+
+func NewTemplateUseCase(repo ports.TemplateRepository) *TemplateUseCase {
+	return &TemplateUseCase{repo: repo}
+}
+
+// Create stores t as the first version of a new template.
+func (s *TemplateUseCase) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	t, err := prepareTemplate(t)
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	t.ID = uuid.New()
+	t.Version = 1
+	return s.repo.Create(ctx, t)
+}
+
+// Update stores t as the next version of the template with the given id.
+// Earlier versions stay available for notifications that reference them.
+func (s *TemplateUseCase) Update(ctx context.Context, id uuid.UUID, t entity.Template) (entity.Template, error) {
+	t, err := prepareTemplate(t)
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	latest, err := s.repo.Get(ctx, id, 0)
+	if err != nil {
+		return entity.Template{}, err
+	}
+
+	t.ID = id
+	t.Version = latest.Version + 1
+	return s.repo.Create(ctx, t)
+}
+
+// Get returns the given version of a template, or its latest version when
+// version is zero.
+func (s *TemplateUseCase) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	return s.repo.Get(ctx, id, version)
+}
+
+func (s *TemplateUseCase) List(ctx context.Context) ([]entity.Template, error) {
+	return s.repo.List(ctx)
+}
+
+// prepareTemplate canonicalizes the locales of t, defaulting the body to
+// entity.DefaultLocale, and validates it.
+func prepareTemplate(t entity.Template) (entity.Template, error) {
+	if t.Locale == "" {
+		t.Locale = entity.DefaultLocale
+	}
+	if l, ok := entity.NormalizeLocale(t.Locale); ok {
+		t.Locale = l
+	}
+
+	variants := make(map[string]string, len(t.Variants))
+	for locale, body := range t.Variants {
+		if l, ok := entity.NormalizeLocale(locale); ok {
+			locale = l
+		}
+		if _, dup := variants[locale]; dup || locale == t.Locale {
+			return entity.Template{}, fmt.Errorf("%w: locale %s is defined more than once", errs.ErrInvalidTemplate, locale)
+		}
+		variants[locale] = body
+	}
+	t.Variants = variants
+
+	if err := t.Validate(); err != nil {
+		return entity.Template{}, err
+	}
+	return t, nil
+}
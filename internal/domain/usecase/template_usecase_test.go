@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/domain/usecase"
+)
+
+type MockTemplateRepo struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepo) Create(ctx context.Context, t entity.Template) (entity.Template, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error) {
+	args := m.Called(ctx, id, version)
+	return args.Get(0).(entity.Template), args.Error(1)
+}
+
+func (m *MockTemplateRepo) List(ctx context.Context) ([]entity.Template, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]entity.Template), args.Error(1)
+}
+
+func TestCreateTemplateStartsAtVersionOne(t *testing.T) {
+	repo := new(MockTemplateRepo)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(t entity.Template) bool {
+		return t.ID != uuid.Nil && t.Version == 1
+	})).Return(entity.Template{Version: 1}, nil)
+
+	svc := usecase.NewTemplateUseCase(repo)
+	tmpl, err := svc.Create(context.Background(), entity.Template{
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, tmpl.Version)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateTemplateRejectsUndeclaredVariable(t *testing.T) {
+	repo := new(MockTemplateRepo)
+
+	svc := usecase.NewTemplateUseCase(repo)
+	_, err := svc.Create(context.Background(), entity.Template{Name: "welcome", Body: "Hi {{.name}}"})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidTemplate)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTemplateAddsVersion(t *testing.T) {
+	repo := new(MockTemplateRepo)
+	id := uuid.New()
+
+	repo.On("Get", mock.Anything, id, 0).Return(entity.Template{ID: id, Version: 2}, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(t entity.Template) bool {
+		return t.ID == id && t.Version == 3
+	})).Return(entity.Template{ID: id, Version: 3}, nil)
+
+	svc := usecase.NewTemplateUseCase(repo)
+	tmpl, err := svc.Update(context.Background(), id, entity.Template{Name: "welcome", Body: "Hello"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, tmpl.Version)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationRendersTemplate(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	templates := new(MockTemplateRepo)
+	id := uuid.New()
+
+	templates.On("Get", mock.Anything, id, 0).Return(entity.Template{
+		ID:        id,
+		Version:   2,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, mock.Anything, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Message == "Hi Ana" && n.TemplateVersion == 2
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTemplates(templates))
+	_, err := svc.Send(context.Background(), entity.Notification{
+		UserID:       uuid.New(),
+		Type:         entity.Status,
+		TemplateID:   &id,
+		TemplateData: map[string]any{"name": "Ana"},
+	})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	gw.AssertExpectations(t)
+}
+
+func TestSendNotificationTemplateMissingVariable(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	templates := new(MockTemplateRepo)
+	id := uuid.New()
+
+	templates.On("Get", mock.Anything, id, 0).Return(entity.Template{
+		ID:        id,
+		Version:   1,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, mock.Anything, entity.Status, mock.Anything).Return(0, nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits, usecase.WithTemplates(templates))
+	_, err := svc.Send(context.Background(), entity.Notification{UserID: uuid.New(), Type: entity.Status, TemplateID: &id})
+
+	assert.ErrorIs(t, err, errs.ErrTemplateMissingVariable)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+type MockPreferencesRepo struct {
+	mock.Mock
+}
+
+func (m *MockPreferencesRepo) Get(ctx context.Context, userID uuid.UUID) (entity.UserPreferences, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(entity.UserPreferences), args.Error(1)
+}
+
+func (m *MockPreferencesRepo) Upsert(ctx context.Context, p entity.UserPreferences) (entity.UserPreferences, error) {
+	args := m.Called(ctx, p)
+	return args.Get(0).(entity.UserPreferences), args.Error(1)
+}
+
+func TestCreateTemplateNormalizesLocales(t *testing.T) {
+	repo := new(MockTemplateRepo)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(t entity.Template) bool {
+		return t.Locale == entity.DefaultLocale && t.Variants["pt-BR"] == "Oi" && len(t.Variants) == 1
+	})).Return(entity.Template{Version: 1}, nil)
+
+	svc := usecase.NewTemplateUseCase(repo)
+	_, err := svc.Create(context.Background(), entity.Template{
+		Name:     "welcome",
+		Body:     "Hi",
+		Variants: map[string]string{"pt_br": "Oi"},
+	})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestSendNotificationRendersPreferredLocale(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	templates := new(MockTemplateRepo)
+	prefs := new(MockPreferencesRepo)
+	id := uuid.New()
+	userID := uuid.New()
+
+	templates.On("Get", mock.Anything, id, 0).Return(entity.Template{
+		ID:        id,
+		Version:   1,
+		Name:      "welcome",
+		Body:      "Hi {{.name}}",
+		Locale:    "en",
+		Variants:  map[string]string{"pt": "Oi {{.name}}", "es": "Hola {{.name}}"},
+		Variables: []entity.TemplateVariable{{Name: "name", Required: true}},
+	}, nil)
+	prefs.On("Get", mock.Anything, userID).Return(entity.UserPreferences{UserID: userID, Locale: "pt-BR"}, nil)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Message == "Oi Ana" && n.Locale == "pt"
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits,
+		usecase.WithTemplates(templates),
+		usecase.WithLocales(prefs, entity.DefaultLocale),
+	)
+	_, err := svc.Send(context.Background(), entity.Notification{
+		UserID:       userID,
+		Type:         entity.Status,
+		TemplateID:   &id,
+		TemplateData: map[string]any{"name": "Ana"},
+	})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	prefs.AssertExpectations(t)
+}
+
+func TestSendNotificationFallsBackToTemplateLocale(t *testing.T) {
+	repo := new(MockRepo)
+	gw := new(MockGateway)
+	templates := new(MockTemplateRepo)
+	prefs := new(MockPreferencesRepo)
+	id := uuid.New()
+	userID := uuid.New()
+
+	templates.On("Get", mock.Anything, id, 0).Return(entity.Template{
+		ID:       id,
+		Version:  1,
+		Name:     "welcome",
+		Body:     "Hi",
+		Locale:   "en",
+		Variants: map[string]string{"pt": "Oi"},
+	}, nil)
+	prefs.On("Get", mock.Anything, userID).Return(entity.UserPreferences{}, errs.ErrUserPreferencesNotFound)
+	repo.On("CountInTimeWindow", mock.Anything, userID, entity.Status, mock.Anything).Return(0, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(n entity.Notification) bool {
+		return n.Message == "Hi" && n.Locale == "en"
+	})).Return(entity.Notification{Status: entity.StatusSent}, nil)
+	gw.On("Send", mock.AnythingOfType("entity.Notification")).Return(nil)
+
+	svc := usecase.NewNotificationUseCase(repo, gw, entity.DefaultRateLimits,
+		usecase.WithTemplates(templates),
+		usecase.WithLocales(prefs, entity.DefaultLocale),
+	)
+	_, err := svc.Send(context.Background(), entity.Notification{UserID: userID, Type: entity.Status, TemplateID: &id})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdatePreferencesRejectsInvalidLocale(t *testing.T) {
+	prefs := new(MockPreferencesRepo)
+
+	svc := usecase.NewUserPreferencesUseCase(prefs)
+	_, err := svc.Update(context.Background(), entity.UserPreferences{UserID: uuid.New(), Locale: "not a locale"})
+
+	assert.ErrorIs(t, err, errs.ErrInvalidLocale)
+	prefs.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
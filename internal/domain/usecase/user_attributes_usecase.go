@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+type UserAttributesUseCase struct {
+	repo ports.UserAttributesRepository
+}
+
+func NewUserAttributesUseCase(repo ports.UserAttributesRepository) *UserAttributesUseCase {
+	return &UserAttributesUseCase{repo: repo}
+}
+
+func (s *UserAttributesUseCase) Get(ctx context.Context, userID uuid.UUID) (entity.UserAttributes, error) {
+	return s.repo.Get(ctx, userID)
+}
+
+// Update replaces the attributes and tags of a.UserID.
+func (s *UserAttributesUseCase) Update(ctx context.Context, a entity.UserAttributes) (entity.UserAttributes, error) {
+	a.Tags = uniqueTags(a.Tags)
+	return s.repo.Upsert(ctx, a)
+}
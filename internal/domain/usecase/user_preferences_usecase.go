@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/config/errs"
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/Paulooo0/modak-challenge/internal/ports"
+	"github.com/google/uuid"
+)
+
+type UserPreferencesUseCase struct {
+	repo ports.UserPreferencesRepository
+}
+
+func NewUserPreferencesUseCase(repo ports.UserPreferencesRepository) *UserPreferencesUseCase {
+	return &UserPreferencesUseCase{repo: repo}
+}
+
+func (s *UserPreferencesUseCase) Get(ctx context.Context, userID uuid.UUID) (entity.UserPreferences, error) {
+	return s.repo.Get(ctx, userID)
+}
+
+// Update stores the preferences of p.UserID with the locale in its
+// canonical form.
+func (s *UserPreferencesUseCase) Update(ctx context.Context, p entity.UserPreferences) (entity.UserPreferences, error) {
+	locale, ok := entity.NormalizeLocale(p.Locale)
+	if !ok {
+		return entity.UserPreferences{}, errs.ErrInvalidLocale
+	}
+	p.Locale = locale
+	return s.repo.Upsert(ctx, p)
+}
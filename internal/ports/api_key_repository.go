@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, k entity.APIKey) (entity.APIKey, error)
+	// GetActiveByHash returns the key that is not revoked with the given
+	// hash, or errs.ErrAPIKeyNotFound.
+	GetActiveByHash(ctx context.Context, hash string) (entity.APIKey, error)
+	List(ctx context.Context) ([]entity.APIKey, error)
+	// Rotate replaces the secret of a key that is not revoked.
+	Rotate(ctx context.Context, id uuid.UUID, prefix, hash string) (entity.APIKey, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type BroadcastRepository interface {
+	Create(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error)
+	Get(ctx context.Context, id uuid.UUID) (entity.Broadcast, error)
+	// Claim leases the unfinished broadcast that waited longest and is not
+	// leased at now until leasedUntil, marking it running. It returns
+	// errs.ErrBroadcastNotFound when there is none.
+	Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Broadcast, error)
+	// SaveProgress stores the status, cursor and counters of b and releases
+	// its lease. It returns errs.ErrBroadcastNotFound when the lease in
+	// b.LeasedUntil has been taken over by another worker.
+	SaveProgress(ctx context.Context, b entity.Broadcast) (entity.Broadcast, error)
+}
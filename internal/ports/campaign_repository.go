@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type CampaignRepository interface {
+	Create(ctx context.Context, c entity.Campaign) (entity.Campaign, error)
+	Get(ctx context.Context, id uuid.UUID) (entity.Campaign, error)
+	// Claim leases the scheduled or running campaign whose next batch has
+	// been due longest at now and is not leased, until leasedUntil, marking
+	// it running. It returns errs.ErrCampaignNotFound when there is none.
+	Claim(ctx context.Context, now, leasedUntil time.Time) (entity.Campaign, error)
+	// SaveProgress stores the cursor, counters and next run of c and
+	// releases its lease. c.Status and c.CompletedAt are only stored when the
+	// campaign is still running, so a pause or cancel made meanwhile sticks.
+	// It returns errs.ErrCampaignNotFound when the lease in c.LeasedUntil
+	// has been taken over by another worker.
+	SaveProgress(ctx context.Context, c entity.Campaign) (entity.Campaign, error)
+	// UpdateStatus moves the campaign to status to if it is in one of the
+	// from statuses, and returns errs.ErrCampaignNotFound otherwise.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from []entity.CampaignStatus, to entity.CampaignStatus) (entity.Campaign, error)
+}
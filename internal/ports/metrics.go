@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+)
+
+// NotificationOutcome is what became of a notification handed to the use
+// case.
+type NotificationOutcome string
+
+const (
+	// OutcomeAccepted notifications were stored, whether they were sent,
+	// scheduled, held for a digest or recorded as duplicate or expired.
+	OutcomeAccepted    NotificationOutcome = "accepted"
+	OutcomeRateLimited NotificationOutcome = "rate_limited"
+	OutcomeInvalid     NotificationOutcome = "invalid"
+	OutcomeFailed      NotificationOutcome = "failed"
+)
+
+// Operation names a timed step of sending notifications.
+type Operation string
+
+const (
+	OpSend              Operation = "send"
+	OpCountInTimeWindow Operation = "count_in_time_window"
+	OpGatewaySend       Operation = "gateway_send"
+)
+
+// Metrics records how notifications fare and how long sending them takes,
+// for an adapter to expose to a monitoring system.
+type Metrics interface {
+	CountNotification(notifType entity.NotificationType, outcome NotificationOutcome)
+	ObserveDuration(op Operation, d time.Duration)
+}
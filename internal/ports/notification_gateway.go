@@ -1,7 +1,11 @@
 package ports
 
-import "github.com/Paulooo0/modak-challenge/internal/domain/entity"
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+)
 
 type NotificationGateway interface {
-	Send(n entity.Notification) error
+	Send(ctx context.Context, n entity.Notification) error
 }
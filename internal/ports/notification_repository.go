@@ -10,5 +10,59 @@ import (
 
 type NotificationRepository interface {
 	Create(ctx context.Context, n entity.Notification) (entity.Notification, error)
+	// CreateMany stores notifications in one batch. Unlike Create it does
+	// not return the stored rows, nor support idempotency keys or templates.
+	CreateMany(ctx context.Context, ns []entity.Notification) error
 	CountInTimeWindow(ctx context.Context, userID uuid.UUID, notifType entity.NotificationType, window time.Time) (int, error)
+	// CountInTimeWindowByUser is CountInTimeWindow for several users at once.
+	// Users without notifications in the window are left out of the result.
+	CountInTimeWindowByUser(ctx context.Context, userIDs []uuid.UUID, notifType entity.NotificationType, window time.Time) (map[uuid.UUID]int, error)
+	// FindDuplicate returns the latest scheduled or sent notification created
+	// since the given time with the same user, type and content as n.
+	FindDuplicate(ctx context.Context, n entity.Notification, since time.Time) (entity.Notification, error)
+	GetByID(ctx context.Context, id uuid.UUID) (entity.Notification, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (entity.Notification, error)
+	// ReleaseIdempotencyKey detaches the idempotency key from a notification
+	// so that the key can be used again.
+	ReleaseIdempotencyKey(ctx context.Context, id uuid.UUID) error
+	// ClaimDue leases up to limit scheduled notifications whose delivery
+	// time is at or before now until leasedUntil, marking them sending, and
+	// returns them. Notifications still sending under a lease that ran out
+	// before now are claimed again.
+	ClaimDue(ctx context.Context, now, leasedUntil time.Time, limit int) ([]entity.Notification, error)
+	// ListHeldGroups returns up to limit users and types that have
+	// notifications held for a digest, ordered by tenant, user and type and
+	// starting after the given group. The zero group starts at the first.
+	ListHeldGroups(ctx context.Context, after entity.DigestGroup, limit int) ([]entity.DigestGroup, error)
+	// ClaimHeld marks every held notification of the group as digested with
+	// the given reason and returns them.
+	ClaimHeld(ctx context.Context, g entity.DigestGroup, reason string) ([]entity.Notification, error)
+	// List returns up to filter.Limit notifications matching filter, newest
+	// first.
+	List(ctx context.Context, filter entity.NotificationFilter) ([]entity.Notification, error)
+	// ListSentAfter returns up to limit sent notifications of last.UserID
+	// delivered after last, ordered by delivery time.
+	ListSentAfter(ctx context.Context, last entity.Notification, limit int) ([]entity.Notification, error)
+	// CountUnread returns how many sent notifications of the user are neither
+	// read nor archived.
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead marks one sent notification of the user as read, keeping
+	// the original read time if it was already read. Notifications not
+	// sent yet are not found.
+	MarkRead(ctx context.Context, userID, id uuid.UUID) (entity.Notification, error)
+	// MarkManyRead marks the given unread sent notifications of the user
+	// as read and returns how many changed. Unknown ids and notifications
+	// not sent yet are ignored.
+	MarkManyRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error)
+	// MarkAllRead marks every unread sent notification of the user as read
+	// and returns how many changed.
+	MarkAllRead(ctx context.Context, userID uuid.UUID) (int, error)
+	// SetArchived archives or restores one sent notification of the user.
+	// Notifications not sent yet are not found.
+	SetArchived(ctx context.Context, userID, id uuid.UUID, archived bool) (entity.Notification, error)
+	// UpdateStatus moves a notification from one status to another at the
+	// given time, recording reason, and returns errs.ErrNotificationNotFound
+	// when it is not in the from status. at becomes its SentAt when to is
+	// entity.StatusSent.
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to entity.NotificationStatus, reason string, at time.Time) (entity.Notification, error)
 }
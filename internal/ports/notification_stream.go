@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// NotificationStream fans delivered notifications out to live listeners,
+// such as open Server-Sent Events connections.
+type NotificationStream interface {
+	// Publish hands n to the current subscribers of n.UserID in
+	// n.TenantID. It never blocks; a subscriber that cannot keep up is
+	// dropped instead.
+	Publish(n entity.Notification)
+	// Subscribe returns a channel receiving the notifications of the user
+	// of tenant as they are delivered and a function that ends the
+	// subscription. The channel is closed when the subscription ends or the
+	// subscriber falls behind.
+	Subscribe(tenant entity.TenantID, userID uuid.UUID) (<-chan entity.Notification, func())
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+)
+
+// RateLimitRuleRepository stores the rate limit overrides of the tenant in
+// the context.
+type RateLimitRuleRepository interface {
+	// Get returns errs.ErrRateLimitRuleNotFound when the tenant has not
+	// overridden the limit of the type.
+	Get(ctx context.Context, notifType entity.NotificationType) (entity.RateLimitRule, error)
+	List(ctx context.Context) ([]entity.RateLimitRule, error)
+	Upsert(ctx context.Context, rule entity.RateLimitRule) (entity.RateLimitRule, error)
+	// Delete returns errs.ErrRateLimitRuleNotFound when there was no rule.
+	Delete(ctx context.Context, notifType entity.NotificationType) error
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type SegmentRepository interface {
+	Create(ctx context.Context, s entity.Segment) (entity.Segment, error)
+	// Get returns errs.ErrSegmentNotFound for an unknown id. The members of
+	// a static segment are not loaded.
+	Get(ctx context.Context, id uuid.UUID) (entity.Segment, error)
+	// Count returns how many users the segment currently matches.
+	Count(ctx context.Context, s entity.Segment) (int, error)
+	// ListMembers returns up to limit users of the segment with an id
+	// greater than after, ordered by id.
+	ListMembers(ctx context.Context, s entity.Segment, after uuid.UUID, limit int) ([]uuid.UUID, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type TemplateRepository interface {
+	Create(ctx context.Context, t entity.Template) (entity.Template, error)
+	// Get returns the given version of a template, or its latest version
+	// when version is zero.
+	Get(ctx context.Context, id uuid.UUID, version int) (entity.Template, error)
+	// List returns the latest version of every template.
+	List(ctx context.Context) ([]entity.Template, error)
+}
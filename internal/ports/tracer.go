@@ -0,0 +1,21 @@
+package ports
+
+import "context"
+
+// Tracer starts the spans of the use case, for an adapter to send to a
+// tracing backend.
+type Tracer interface {
+	// Start starts a span named name as a child of the span in ctx, if
+	// any, with args as attributes: alternating keys and values, as in
+	// slog.
+	Start(ctx context.Context, name string, args ...any) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes adds args, alternating keys and values, to the span.
+	SetAttributes(args ...any)
+	// End marks the span as failed with err, when it is not nil, and ends
+	// it.
+	End(err error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type UserAttributesRepository interface {
+	// Get returns errs.ErrUserAttributesNotFound when the user has no
+	// attributes.
+	Get(ctx context.Context, userID uuid.UUID) (entity.UserAttributes, error)
+	// Upsert replaces every attribute and tag of the user.
+	Upsert(ctx context.Context, a entity.UserAttributes) (entity.UserAttributes, error)
+}
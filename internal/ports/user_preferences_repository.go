@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/Paulooo0/modak-challenge/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type UserPreferencesRepository interface {
+	// Get returns errs.ErrUserPreferencesNotFound when the user has not set
+	// any preferences.
+	Get(ctx context.Context, userID uuid.UUID) (entity.UserPreferences, error)
+	Upsert(ctx context.Context, p entity.UserPreferences) (entity.UserPreferences, error)
+}